@@ -5,6 +5,10 @@
 // All structures in this package are backwards compatible.
 package api
 
+import (
+	"time"
+)
+
 const Version = 1
 
 type BugGroup struct {
@@ -51,3 +55,24 @@ type Commit struct {
 	Repo   string `json:"repo,omitempty"`
 	Branch string `json:"branch,omitempty"`
 }
+
+// BugListItem is an element of the bug list returned by /<namespace>/api/v1/bugs.
+type BugListItem struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Link       string    `json:"link"`
+	Status     string    `json:"status"`
+	Subsystems []string  `json:"subsystems,omitempty"`
+	FirstCrash time.Time `json:"first-crash"`
+	LastCrash  time.Time `json:"last-crash"`
+	NumCrashes int64     `json:"num-crashes"`
+	// ReproLevel is "c", "syz" or empty.
+	ReproLevel string `json:"repro-level,omitempty"`
+}
+
+// BugList is a page of bugs. Pass NextCursor as the cursor parameter to get the next page.
+// NextCursor is empty for the last page.
+type BugList struct {
+	Items      []BugListItem `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
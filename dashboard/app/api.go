@@ -978,9 +978,10 @@ func saveCrash(c context.Context, ns string, req *dashapi.Crash, bug *Bug, bugKe
 		Maintainers: email.MergeEmailLists(req.Maintainers,
 			GetEmails(req.Recipients, dashapi.To),
 			GetEmails(req.Recipients, dashapi.Cc)),
-		ReproOpts: req.ReproOpts,
-		Flags:     int64(req.Flags),
-		Assets:    assets,
+		ReproOpts:      req.ReproOpts,
+		Flags:          int64(req.Flags),
+		Assets:         assets,
+		TriagePriority: int64(req.Priority),
 		ReportElements: CrashReportElements{
 			GuiltyFiles: req.GuiltyFiles,
 		},
@@ -624,6 +624,53 @@ func TestUnreliableBisect(t *testing.T) {
 	c.expectTrue(!strings.Contains(msg.Body, "bisection"))
 }
 
+func TestBisectCauseLowConfidence(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client2.UploadBuild(build)
+	crash := testCrashWithRepro(build, 1)
+	c.client2.ReportCrash(crash)
+	_ = c.client2.pollEmailBug()
+
+	pollResp := c.client2.pollJobs(build.Manager)
+	jobID := pollResp.ID
+	done := &dashapi.JobDoneReq{
+		ID:    jobID,
+		Build: *build,
+		Log:   []byte("bisect log"),
+		Flags: dashapi.BisectResultLowConfidence,
+		Commits: []dashapi.Commit{
+			{
+				Hash:       "111111111111111111111111",
+				Title:      "kernel: add a bug",
+				Author:     "author@kernel.org",
+				AuthorName: "Author Kernelov",
+				Date:       time.Date(2000, 2, 9, 4, 5, 6, 7, time.UTC),
+			},
+		},
+		Confidence: 0.7,
+		ConfidenceFactors: []dashapi.ConfidenceFactor{
+			{Reason: "5 revision(s) tested as good may be false negatives", Score: 0.8},
+			{Reason: "1 revision(s) tested as bad crashed differently from the original crash", Score: 0.9},
+		},
+	}
+	done.Build.ID = jobID
+	c.expectOK(c.client2.JobDone(done))
+
+	// Low confidence results are still reported, but with an explanation.
+	msg := c.pollEmailBug()
+	c.expectTrue(strings.Contains(msg.Body, "syzbot has bisected this issue to:"))
+	c.expectTrue(strings.Contains(msg.Body, `    kernel: add a bug
+
+Note: this result has low confidence (0.70):
+  - 5 revision(s) tested as good may be false negatives
+  - 1 revision(s) tested as bad crashed differently from the original crash
+
+bisection log:  `))
+}
+
 func TestBisectWrong(t *testing.T) {
 	// Test bisection results with BisectResultMerge/BisectResultNoop flags set.
 	// If any of these set, the result must not be reported separately,
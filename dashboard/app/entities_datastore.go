@@ -371,6 +371,7 @@ type Crash struct {
 	ReportLen       int64
 	Assets          []Asset   // crash-related assets
 	AssetsLastCheck time.Time // the last time we checked the assets for deprecation
+	TriagePriority  int64     // priority assigned by the manager triage rules
 }
 
 type CrashReportElements struct {
@@ -600,6 +601,9 @@ type Job struct {
 	Log         int64 // reference to Log text entity
 	Error       int64 // reference to Error text entity, if set job failed
 	Flags       dashapi.JobDoneFlags
+	// Bisection result confidence and the factors that have decreased it.
+	Confidence        float64
+	ConfidenceFactors []dashapi.ConfidenceFactor
 
 	Reported         bool   // have we reported result back to user?
 	InvalidatedBy    string // user who marked this bug as invalid, empty by default
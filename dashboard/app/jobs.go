@@ -1103,6 +1103,8 @@ func doneJob(c context.Context, req *dashapi.JobDoneReq) error {
 		job.Finished = now
 		job.IsRunning = false
 		job.Flags = req.Flags
+		job.Confidence = req.Confidence
+		job.ConfidenceFactors = req.ConfidenceFactors
 		if job.Type == JobBisectCause || job.Type == JobBisectFix {
 			// Update bug.BisectCause/Fix status and also remember current bug reporting to send results.
 			var err error
@@ -1350,11 +1352,14 @@ func createBugReportForJob(c context.Context, job *Job, jobKey *db.Key, config i
 
 func bisectFromJob(c context.Context, job *Job) (*dashapi.BisectResult, []string) {
 	bisect := &dashapi.BisectResult{
-		LogLink:         externalLink(c, textLog, job.Log),
-		CrashLogLink:    externalLink(c, textCrashLog, job.CrashLog),
-		CrashReportLink: externalLink(c, textCrashReport, job.CrashReport),
-		Fix:             job.Type == JobBisectFix,
-		CrossTree:       job.IsCrossTree(),
+		LogLink:           externalLink(c, textLog, job.Log),
+		CrashLogLink:      externalLink(c, textCrashLog, job.CrashLog),
+		CrashReportLink:   externalLink(c, textCrashReport, job.CrashReport),
+		Fix:               job.Type == JobBisectFix,
+		CrossTree:         job.IsCrossTree(),
+		LowConfidence:     job.Flags&dashapi.BisectResultLowConfidence != 0,
+		Confidence:        job.Confidence,
+		ConfidenceFactors: job.ConfidenceFactors,
 	}
 	for _, com := range job.Commits {
 		bisect.Commits = append(bisect.Commits, com.toDashapi())
@@ -58,6 +58,7 @@ func initHTTPHandlers() {
 	http.Handle("/x/bisect.txt", handlerWrapper(handleTextX(textLog)))
 	http.Handle("/x/error.txt", handlerWrapper(handleTextX(textError)))
 	http.Handle("/x/minfo.txt", handlerWrapper(handleTextX(textMachineInfo)))
+	http.Handle(apiPrefix+"/openapi.json", handlerWrapper(handleAPISpec))
 	for ns, nsConfig := range getConfig(context.Background()).Namespaces {
 		http.Handle("/"+ns, handlerWrapper(handleMain))
 		http.Handle("/"+ns+"/fixed", handlerWrapper(handleFixed))
@@ -82,6 +83,7 @@ func initHTTPHandlers() {
 		http.Handle("/"+ns+"/backports", handlerWrapper(handleBackports))
 		http.Handle("/"+ns+"/s/", handlerWrapper(handleSubsystemPage))
 		http.Handle("/"+ns+"/manager/", handlerWrapper(handleManagerPage))
+		http.Handle("/"+ns+apiPrefix+"/bugs", handlerWrapper(handleAPIBugs))
 	}
 	http.HandleFunc("/cron/cache_update", cacheUpdate)
 	http.HandleFunc("/cron/minute_cache_update", handleMinuteCacheUpdate)
@@ -410,6 +412,7 @@ type uiCrash struct {
 	ReproLogLink    string
 	MachineInfoLink string
 	Assets          []*uiAsset
+	TriagePriority  int64
 	*uiBuild
 }
 
@@ -2089,6 +2092,7 @@ func makeUICrash(c context.Context, crash *Crash, build *Build) *uiCrash {
 		ReproIsRevoked:  crash.ReproIsRevoked,
 		MachineInfoLink: textLink(textMachineInfo, crash.MachineInfo),
 		Assets:          makeUIAssets(c, build, crash, true),
+		TriagePriority:  crash.TriagePriority,
 	}
 	if build != nil {
 		ui.uiBuild = makeUIBuild(c, build, true)
@@ -8,12 +8,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/civil"
 	"github.com/google/syzkaller/dashboard/api"
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/coveragedb"
+	"github.com/google/syzkaller/pkg/jsonapi"
+	db "google.golang.org/appengine/v2/datastore"
 )
 
 func getExtAPIDescrForBugPage(bugPage *uiBugPage) *api.Bug {
@@ -270,3 +275,142 @@ func genFuncsCov(fc *coveragedb.FileCoverageWithLineInfo, ff *coveragedb.Functio
 	}
 	return res, nil
 }
+
+// The versioned JSON API. Unlike the ?json=1 versions of the HTML pages, it's paginated and supports filtering.
+// Incompatible changes need a new version prefix, new fields and parameters may be added to the existing version.
+const apiPrefix = "/api/v1"
+
+var apiBugStatuses = map[string]int{
+	"open":    BugStatusOpen,
+	"fixed":   BugStatusFixed,
+	"invalid": BugStatusInvalid,
+}
+
+var apiSpec = func() *jsonapi.Spec {
+	spec := jsonapi.NewSpec("syzbot", "v1")
+	spec.Get("/{namespace}"+apiPrefix+"/bugs", "list bugs, most recently crashed first", append([]jsonapi.Param{
+		{Name: "namespace", In: "path", Type: "string", Required: true},
+		{Name: "status", Type: "string", Description: "open (default), fixed or invalid"},
+		{Name: "title", Type: "string", Description: "regexp that bug titles must match"},
+		{Name: "subsystem", Type: "string", Description: "only bugs assigned to the subsystem"},
+		{Name: "since", Type: "string", Description: "only bugs that crashed after this time (RFC 3339 or date)"},
+		{Name: "until", Type: "string", Description: "only bugs that first crashed before this time"},
+		{Name: "has_repro", Type: "boolean", Description: "only bugs with (true) or without (false) a reproducer"},
+	}, jsonapi.PageParams...), api.BugList{})
+	return spec
+}()
+
+func handleAPISpec(c context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(apiSpec)
+}
+
+func handleAPIBugs(c context.Context, w http.ResponseWriter, r *http.Request) error {
+	ns := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+	accessLevel := accessLevel(c, r)
+	if getNsConfig(c, ns).AccessLevel > accessLevel {
+		return ErrAccess
+	}
+	params := jsonapi.NewParams(r)
+	statusName := params.String("status")
+	if statusName == "" {
+		statusName = "open"
+	}
+	status, ok := apiBugStatuses[statusName]
+	if !ok {
+		return fmt.Errorf("%w: unknown status %q", ErrClientBadRequest, statusName)
+	}
+	title := params.Regexp("title")
+	subsystem := params.String("subsystem")
+	since, until := params.Time("since"), params.Time("until")
+	hasRepro := params.Bool("has_repro")
+	cursor, limit := params.String("cursor"), params.Int("limit")
+	if err := params.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrClientBadRequest, err)
+	}
+	if limit <= 0 || limit > jsonapi.MaxLimit {
+		limit = jsonapi.DefaultLimit
+	}
+	query := db.NewQuery("Bug").
+		Filter("Namespace=", ns).
+		Filter("Status=", status).
+		Order("-LastTime")
+	if !since.IsZero() {
+		query = query.Filter("LastTime>=", since)
+	}
+	if hasRepro != nil && !*hasRepro {
+		query = query.Filter("ReproLevel=", ReproLevelNone)
+	}
+	if cursor != "" {
+		start, err := db.DecodeCursor(cursor)
+		if err != nil {
+			return fmt.Errorf("%w: bad cursor %q", ErrClientBadRequest, cursor)
+		}
+		query = query.Start(start)
+	}
+	page := &api.BugList{Items: []api.BugListItem{}}
+	var pageEnd db.Cursor
+	iter := query.Run(c)
+	for {
+		bug := new(Bug)
+		_, err := iter.Next(bug)
+		if err == db.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch bugs: %w", err)
+		}
+		// Datastore permits inequality filters only on the property the results are sorted by,
+		// so the rest of the conditions are checked here.
+		if accessLevel < bug.sanitizeAccess(c, accessLevel) ||
+			title != nil && !title.MatchString(bug.displayTitle()) ||
+			!until.IsZero() && bug.FirstTime.After(until) ||
+			hasRepro != nil && *hasRepro && bug.ReproLevel == ReproLevelNone {
+			continue
+		}
+		item := makeAPIBugListItem(c, bug)
+		if subsystem != "" && !slices.Contains(item.Subsystems, subsystem) {
+			continue
+		}
+		if len(page.Items) == limit {
+			// There's at least one more bug, so the next page is not empty.
+			page.NextCursor = pageEnd.String()
+			break
+		}
+		page.Items = append(page.Items, item)
+		if len(page.Items) == limit {
+			if pageEnd, err = iter.Cursor(); err != nil {
+				return fmt.Errorf("cursor failed while fetching bugs: %w", err)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(page)
+}
+
+func makeAPIBugListItem(c context.Context, bug *Bug) api.BugListItem {
+	id := bug.keyHash(c)
+	item := api.BugListItem{
+		ID:         id,
+		Title:      bug.displayTitle(),
+		Link:       bugLink(id),
+		FirstCrash: bug.FirstTime,
+		LastCrash:  bug.LastTime,
+		NumCrashes: bug.NumCrashes,
+	}
+	for name, status := range apiBugStatuses {
+		if bug.Status == status {
+			item.Status = name
+		}
+	}
+	for _, label := range bug.LabelValues(SubsystemLabel) {
+		item.Subsystems = append(item.Subsystems, label.Value)
+	}
+	switch bug.ReproLevel {
+	case ReproLevelC:
+		item.ReproLevel = "c"
+	case ReproLevelSyz:
+		item.ReproLevel = "syz"
+	}
+	return item
+}
@@ -6,8 +6,10 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/syzkaller/dashboard/api"
 	"github.com/google/syzkaller/dashboard/dashapi"
@@ -341,3 +343,57 @@ func fileFuncLinesDBFixture(t *testing.T, funcLines []*coveragedb.FuncLines,
 		Return(mFullTran).Once()
 	return m
 }
+
+func TestJSONAPIBugList(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+	c.client.ReportCrash(testCrash(build, 1))
+	c.client.pollBug()
+	c.advanceTime(time.Hour)
+	c.client.ReportCrash(testCrashWithRepro(build, 2))
+	c.client.pollBug()
+
+	getList := func(query string) *api.BugList {
+		reply, err := c.GET("/test1/api/v1/bugs?" + query)
+		c.expectOK(err)
+		list := new(api.BugList)
+		c.expectOK(json.Unmarshal(reply, list))
+		return list
+	}
+
+	// The most recently crashed bug goes first.
+	list := getList("limit=1")
+	c.expectEQ(len(list.Items), 1)
+	c.expectEQ(list.Items[0].Title, "title2")
+	c.expectEQ(list.Items[0].ReproLevel, "c")
+	c.expectNE(list.NextCursor, "")
+	list = getList("limit=1&cursor=" + list.NextCursor)
+	c.expectEQ(len(list.Items), 1)
+	c.expectEQ(list.Items[0].Title, "title1")
+	c.expectEQ(list.NextCursor, "")
+
+	list = getList("has_repro=false")
+	c.expectEQ(len(list.Items), 1)
+	c.expectEQ(list.Items[0].Title, "title1")
+	list = getList("has_repro=true")
+	c.expectEQ(len(list.Items), 1)
+	c.expectEQ(list.Items[0].Title, "title2")
+	list = getList("title=title[0-9]")
+	c.expectEQ(len(list.Items), 2)
+	list = getList("status=fixed")
+	c.expectEQ(len(list.Items), 0)
+	list = getList("subsystem=no-such-subsystem")
+	c.expectEQ(len(list.Items), 0)
+
+	_, err := c.GET("/test1/api/v1/bugs?title=(")
+	c.expectBadReqest(err)
+	_, err = c.GET("/test1/api/v1/bugs?cursor=foo")
+	c.expectBadReqest(err)
+
+	reply, err := c.GET("/api/v1/openapi.json")
+	c.expectOK(err)
+	c.expectTrue(bytes.Contains(reply, []byte(`"/{namespace}/api/v1/bugs"`)))
+}
@@ -596,6 +596,25 @@ func TestMachineInfo(t *testing.T) {
 	c.expectEQ(receivedInfo, machineInfo)
 }
 
+func TestTriagePriority(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+
+	crash := testCrash(build, 1)
+	crash.Priority = 5
+	c.client.ReportCrash(crash)
+	rep := c.client.pollBug()
+
+	bug, dbCrash, _ := c.loadBug(rep.ID)
+	c.expectEQ(dbCrash.TriagePriority, int64(5))
+	bugPage, err := c.AuthGET(AccessAdmin, fmt.Sprintf("/bug?id=%v", bug.keyHash(c.ctx)))
+	c.expectOK(err)
+	assert.Contains(t, string(bugPage), "(prio 5)")
+}
+
 func TestAltTitles1(t *testing.T) {
 	c := NewCtx(t)
 	defer c.Close()
@@ -217,6 +217,15 @@ type JobDoneReq struct {
 	// If there are more than 1: suspected commits due to skips (broken build/boot).
 	Commits []Commit
 	Flags   JobDoneFlags
+	// Estimate of the probability that the bisection result is correct
+	// (bisect.Result.ConfidenceScore), and the factors that have decreased it.
+	Confidence        float64
+	ConfidenceFactors []ConfidenceFactor
+}
+
+type ConfidenceFactor struct {
+	Reason string
+	Score  float64
 }
 
 type JobType int
@@ -230,11 +239,12 @@ const (
 type JobDoneFlags int64
 
 const (
-	BisectResultMerge      JobDoneFlags = 1 << iota // bisected to a merge commit
-	BisectResultNoop                                // commit does not affect resulting kernel binary
-	BisectResultRelease                             // commit is a kernel release
-	BisectResultIgnore                              // this particular commit should be ignored, see syz-ci/jobs.go
-	BisectResultInfraError                          // the bisect failed due to an infrastructure problem
+	BisectResultMerge         JobDoneFlags = 1 << iota // bisected to a merge commit
+	BisectResultNoop                                   // commit does not affect resulting kernel binary
+	BisectResultRelease                                // commit is a kernel release
+	BisectResultIgnore                                 // this particular commit should be ignored, see syz-ci/jobs.go
+	BisectResultInfraError                             // the bisect failed due to an infrastructure problem
+	BisectResultLowConfidence                          // the result is likely to be wrong, see ConfidenceFactors
 )
 
 func (flags JobDoneFlags) String() string {
@@ -254,6 +264,9 @@ func (flags JobDoneFlags) String() string {
 	if flags&BisectResultIgnore != 0 {
 		res += "ignored "
 	}
+	if flags&BisectResultLowConfidence != 0 {
+		res += "low-confidence "
+	}
 	if res == "" {
 		return res
 	}
@@ -332,6 +345,7 @@ type Crash struct {
 	MachineInfo []byte
 	Assets      []NewAsset
 	GuiltyFiles []string
+	Priority    int // priority assigned by the manager triage rules, higher values are more important
 	// The following is optional and is filled only after repro.
 	ReproOpts     []byte
 	ReproSyz      []byte
@@ -521,6 +535,10 @@ type BisectResult struct {
 	CrashReportLink string
 	Fix             bool
 	CrossTree       bool
+	// Set if the result is likely to be wrong; ConfidenceFactors explain why.
+	LowConfidence     bool
+	Confidence        float64
+	ConfidenceFactors []ConfidenceFactor
 	// In case a missing backport was backported.
 	Backported *Commit
 }
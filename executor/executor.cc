@@ -72,6 +72,7 @@ const int kOutPipeFd = kMaxFd - 2; // remapped from stdout
 const int kCoverFd = kOutPipeFd - kMaxThreads;
 const int kExtraCoverFd = kCoverFd - 1;
 const int kMaxArgs = 9;
+const int kMaxCopyouts = 64; // max number of collected copyout values per call
 const int kCoverSize = 512 << 10;
 const int kFailStatus = 67;
 
@@ -277,6 +278,8 @@ static bool flag_collect_cover;
 static bool flag_collect_signal;
 static bool flag_dedup_cover;
 static bool flag_threaded;
+// If true, then executor should write the values copied out by the calls to fuzzer.
+static bool flag_collect_copyout;
 
 // If true, then executor should write the comparisons data to fuzzer.
 static bool flag_comparisons;
@@ -386,6 +389,8 @@ struct thread_t {
 	uint32 reserrno;
 	bool fault_injected;
 	cover_t cov;
+	int num_copyouts;
+	uint64 copyouts[kMaxCopyouts];
 	bool soft_fail_state;
 };
 
@@ -814,6 +819,7 @@ void parse_execute(const execute_req& req)
 	flag_dedup_cover = req.exec_flags & (1 << 2);
 	flag_comparisons = req.exec_flags & (1 << 3);
 	flag_threaded = req.exec_flags & (1 << 4);
+	flag_collect_copyout = req.exec_flags & (1 << 5);
 	all_call_signal = req.all_call_signal;
 	all_extra_signal = req.all_extra_signal;
 
@@ -1141,6 +1147,7 @@ thread_t* schedule_call(int call_index, int call_num, uint64 copyout_index, uint
 	last_scheduled = th;
 	th->copyout_pos = pos;
 	th->copyout_index = copyout_index;
+	th->num_copyouts = 0;
 	event_reset(&th->done);
 	// We do this both right before execute_syscall in the thread and here because:
 	// the former is useful to reset all unrelated coverage from our syscalls (e.g. futex in event_wait),
@@ -1308,6 +1315,9 @@ void copyout_call_results(thread_t* th)
 				results[index].executed = true;
 				results[index].val = val;
 			}
+			// Failed copyouts are recorded as 0, so that the values stay in the program order.
+			if (flag_collect_copyout && th->num_copyouts < kMaxCopyouts)
+				th->copyouts[th->num_copyouts++] = val;
 			debug_verbose("copyout 0x%llx from %p\n", val, addr);
 			break;
 		}
@@ -1318,7 +1328,8 @@ void copyout_call_results(thread_t* th)
 	}
 }
 
-void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bool all_signal)
+void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bool all_signal,
+		  const uint64* copyouts, int num_copyouts)
 {
 	CoverAccessScope scope(cov);
 	auto& fbb = *output_builder;
@@ -1327,6 +1338,9 @@ void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bo
 	uint32 signal_off = 0;
 	uint32 cover_off = 0;
 	uint32 comps_off = 0;
+	uint32 copyout_off = 0;
+	if (num_copyouts)
+		copyout_off = fbb.CreateVector(copyouts, num_copyouts).o;
 	if (flag_comparisons) {
 		comps_off = write_comparisons(fbb, cov);
 	} else {
@@ -1355,6 +1369,8 @@ void write_output(int index, cover_t* cov, rpc::CallFlag flags, uint32 error, bo
 		builder.add_cover(cover_off);
 	if (comps_off)
 		builder.add_comps(comps_off);
+	if (copyout_off)
+		builder.add_copyout(copyout_off);
 	auto off = builder.Finish();
 	uint32 slot = output_data->completed.load(std::memory_order_relaxed);
 	if (slot >= kMaxCalls)
@@ -1381,7 +1397,9 @@ void write_call_output(thread_t* th, bool finished)
 			flags |= rpc::CallFlag::FaultInjected;
 	}
 	bool all_signal = th->call_index < 64 ? (all_call_signal & (1ull << th->call_index)) : false;
-	write_output(th->call_index, &th->cov, flags, reserrno, all_signal);
+	// Values are copied out only when the call has finished.
+	int num_copyouts = finished ? th->num_copyouts : 0;
+	write_output(th->call_index, &th->cov, flags, reserrno, all_signal, th->copyouts, num_copyouts);
 }
 
 void write_extra_output()
@@ -1391,7 +1409,7 @@ void write_extra_output()
 	cover_collect(&extra_cov);
 	if (!extra_cov.size)
 		return;
-	write_output(-1, &extra_cov, rpc::CallFlag::NONE, 997, all_extra_signal);
+	write_output(-1, &extra_cov, rpc::CallFlag::NONE, 997, all_extra_signal, nullptr, 0);
 	cover_reset(&extra_cov);
 }
 
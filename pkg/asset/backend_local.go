@@ -0,0 +1,128 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/gcs"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// localStorageBackend keeps assets in a local directory.
+// The directory is supposed to be served over HTTP (e.g. by syz-manager, see Config.LocalDir).
+type localStorageBackend struct {
+	dir string
+	// URL prefix the assets are downloaded from. If empty, file:// URLs are used.
+	baseURL string
+	tracer  debugtracer.DebugTracer
+}
+
+func makeLocalStorageBackend(dir, baseURL string, tracer debugtracer.DebugTracer) (*localStorageBackend, error) {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	if baseURL == "" {
+		baseURL = "file://" + dir
+	}
+	tracer.Log("created local backend for dir '%s'", dir)
+	return &localStorageBackend{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+		tracer:  tracer,
+	}, nil
+}
+
+func (lsb *localStorageBackend) file(path string) (string, error) {
+	file := filepath.Join(lsb.dir, filepath.FromSlash(path))
+	if !strings.HasPrefix(file, filepath.Clean(lsb.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid asset path: %s", path)
+	}
+	return file, nil
+}
+
+func (lsb *localStorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	file, err := lsb.file(req.savePath)
+	if err != nil {
+		return nil, err
+	}
+	if osutil.IsExist(file) {
+		return nil, &FileExistsError{req.savePath}
+	}
+	if err := osutil.MkdirAll(filepath.Dir(file)); err != nil {
+		return nil, err
+	}
+	// Write to a temp file first, so that partially written assets are never served.
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	lsb.tracer.Log("local upload: writing %s", file)
+	return &uploadResponse{
+		writer: &wrappedWriteCloser{
+			writer: tmp,
+			closeCallback: func() error {
+				return os.Rename(tmp.Name(), file)
+			},
+		},
+		path: req.savePath,
+	}, nil
+}
+
+func (lsb *localStorageBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return lsb.baseURL + path, nil
+}
+
+func (lsb *localStorageBackend) getPath(downloadURL string) (string, error) {
+	if !strings.HasPrefix(downloadURL, lsb.baseURL) {
+		return "", ErrUnknownBucket
+	}
+	return strings.TrimPrefix(downloadURL, lsb.baseURL), nil
+}
+
+func (lsb *localStorageBackend) list() ([]*gcs.Object, error) {
+	var ret []*gcs.Object
+	err := filepath.WalkDir(lsb.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(lsb.dir, file)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, &gcs.Object{
+			Path:      filepath.ToSlash(rel),
+			CreatedAt: info.ModTime(),
+		})
+		return nil
+	})
+	return ret, err
+}
+
+func (lsb *localStorageBackend) remove(path string) error {
+	file, err := lsb.file(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil {
+		if os.IsNotExist(err) {
+			return ErrAssetDoesNotExist
+		}
+		return err
+	}
+	// Remove the pseudo-unique folder if it became empty, ignore errors otherwise.
+	if dir := filepath.Dir(file); dir != filepath.Clean(lsb.dir) {
+		os.Remove(dir)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	dashMock := newDashMock()
+	cfg := &Config{
+		UploadTo:    "file://" + dir,
+		DownloadURL: "http://manager:56741/assets",
+	}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, dir, cfg.LocalDir())
+	storage, err := StorageFromConfig(cfg, dashMock)
+	assert.NoError(t, err)
+	build := &dashapi.Build{ID: "1234", KernelCommit: "abcdef2134"}
+
+	content := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+	assert.NoError(t, storage.sendBuildAsset(bytes.NewReader(content), "vmlinux", dashapi.KernelObject, build))
+	assert.Len(t, dashMock.downloadURLs, 1)
+	var url string
+	for url = range dashMock.downloadURLs {
+	}
+	assert.True(t, strings.HasPrefix(url, "http://manager:56741/assets/"), url)
+	path := strings.TrimPrefix(url, "http://manager:56741/assets/")
+	file, err := os.Open(filepath.Join(dir, path))
+	assert.NoError(t, err)
+	defer file.Close()
+	reader, err := xz.NewReader(file)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// Upload an asset that will not be reported to the dashboard.
+	extra := &ExtraUploadArg{UniqueTag: "tag", SkipIfExists: true}
+	orphan, err := storage.UploadBuildAsset(bytes.NewReader(content), "disk.img", dashapi.KernelImage, build, extra)
+	assert.NoError(t, err)
+	again, err := storage.UploadBuildAsset(bytes.NewReader(content), "disk.img", dashapi.KernelImage, build, extra)
+	assert.NoError(t, err)
+	assert.Equal(t, orphan.DownloadURL, again.DownloadURL)
+
+	// Recent assets are not deleted.
+	stats, err := storage.DeprecateAssets()
+	assert.NoError(t, err)
+	assert.Equal(t, DeprecateStats{Needed: 1, Existing: 2}, stats)
+
+	old := time.Now().Add(-2 * deletionEmbargo)
+	objects, err := storage.backend.list()
+	assert.NoError(t, err)
+	for _, obj := range objects {
+		assert.NoError(t, os.Chtimes(filepath.Join(dir, obj.Path), old, old))
+	}
+	stats, err = storage.DeprecateAssets()
+	assert.NoError(t, err)
+	assert.Equal(t, DeprecateStats{Needed: 1, Existing: 2, Deleted: 1}, stats)
+	assert.FileExists(t, filepath.Join(dir, path))
+	assert.NoDirExists(t, filepath.Join(dir, "tag"))
+}
+
+func TestLocalGetPaths(t *testing.T) {
+	be, err := makeLocalStorageBackend(t.TempDir(), "", &debugtracer.NullTracer{})
+	assert.NoError(t, err)
+	url, err := be.downloadURL("folder/file.txt", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "file://"+be.dir+"/folder/file.txt", url)
+	path, err := be.getPath(url)
+	assert.NoError(t, err)
+	assert.Equal(t, "folder/file.txt", path)
+	_, err = be.getPath("file:///other/dir/folder/file.txt")
+	assert.ErrorIs(t, err, ErrUnknownBucket)
+	_, err = be.upload(&uploadRequest{savePath: "../file.txt"})
+	assert.ErrorContains(t, err, "invalid asset path")
+	assert.ErrorIs(t, be.remove("folder/file.txt"), ErrAssetDoesNotExist)
+}
@@ -0,0 +1,345 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/gcs"
+)
+
+// s3StorageBackend keeps assets in an S3-compatible object store (AWS S3, MinIO, Ceph, etc).
+// It implements the few requests it needs with the path-style addressing and
+// AWS Signature Version 4 authentication.
+type s3StorageBackend struct {
+	endpoint  string
+	region    string
+	accessKey string
+	secretKey string
+	bucket    string
+	// Key prefix of all assets (either empty or ends with /).
+	prefix string
+	// URL prefix the assets are downloaded from.
+	baseURL string
+	client  *http.Client
+	tracer  debugtracer.DebugTracer
+	now     func() time.Time
+}
+
+func makeS3StorageBackend(bucketPath string, cfg *Config, tracer debugtracer.DebugTracer) (*s3StorageBackend, error) {
+	s3cfg := cfg.S3
+	if s3cfg == nil || s3cfg.Endpoint == "" {
+		return nil, fmt.Errorf("s3 endpoint is not specified")
+	}
+	bucket, prefix, _ := strings.Cut(bucketPath, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is not specified")
+	}
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+	be := &s3StorageBackend{
+		endpoint:  strings.TrimSuffix(s3cfg.Endpoint, "/"),
+		region:    s3cfg.Region,
+		accessKey: s3cfg.AccessKey,
+		secretKey: s3cfg.SecretKey,
+		bucket:    bucket,
+		prefix:    prefix,
+		baseURL:   cfg.DownloadURL,
+		client:    http.DefaultClient,
+		tracer:    tracer,
+		now:       time.Now,
+	}
+	if be.region == "" {
+		be.region = "us-east-1"
+	}
+	if be.accessKey == "" && be.secretKey == "" {
+		be.accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		be.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if be.baseURL == "" {
+		be.baseURL = be.endpoint + "/" + bucket + "/" + prefix
+	}
+	be.baseURL = strings.TrimSuffix(be.baseURL, "/") + "/"
+	tracer.Log("created s3 backend for bucket '%s' at %s", bucketPath, be.endpoint)
+	return be, nil
+}
+
+func (be *s3StorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	key := be.prefix + req.savePath
+	// Best-effort check only, same as for GCS.
+	exists, err := be.exists(key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, &FileExistsError{req.savePath}
+	}
+	// S3 needs the content length and hash before the upload, so we first write the data to a temp file.
+	tmp, err := os.CreateTemp("", "syz-asset-s3")
+	if err != nil {
+		return nil, err
+	}
+	return &uploadResponse{
+		writer: &s3Writer{
+			be:   be,
+			key:  key,
+			req:  req,
+			file: tmp,
+			hash: sha256.New(),
+		},
+		path: req.savePath,
+	}, nil
+}
+
+type s3Writer struct {
+	be   *s3StorageBackend
+	key  string
+	req  *uploadRequest
+	file *os.File
+	hash hash.Hash
+	size int64
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.hash.Write(p[:n])
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *s3Writer) Close() error {
+	defer os.Remove(w.file.Name())
+	defer w.file.Close()
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(http.MethodPut, w.be.objectURL(w.key), w.file)
+	if err != nil {
+		return err
+	}
+	httpReq.ContentLength = w.size
+	httpReq.Header.Set("Content-Type", w.req.contentType)
+	if w.req.contentEncoding != "" {
+		httpReq.Header.Set("Content-Encoding", w.req.contentEncoding)
+	}
+	w.be.tracer.Log("s3 upload: %s, %d bytes", w.key, w.size)
+	resp, err := w.be.do(httpReq, hex.EncodeToString(w.hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (be *s3StorageBackend) exists(key string) (bool, error) {
+	req, err := http.NewRequest(http.MethodHead, be.objectURL(key), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := be.do(req, emptyPayloadHash)
+	if err == errS3NotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+func (be *s3StorageBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return be.baseURL + path, nil
+}
+
+func (be *s3StorageBackend) getPath(downloadURL string) (string, error) {
+	if !strings.HasPrefix(downloadURL, be.baseURL) {
+		return "", ErrUnknownBucket
+	}
+	return strings.TrimPrefix(downloadURL, be.baseURL), nil
+}
+
+type s3ListResult struct {
+	IsTruncated           bool
+	NextContinuationToken string
+	Contents              []struct {
+		Key          string
+		LastModified time.Time
+	}
+}
+
+func (be *s3StorageBackend) list() ([]*gcs.Object, error) {
+	var ret []*gcs.Object
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if be.prefix != "" {
+			query.Set("prefix", be.prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := http.NewRequest(http.MethodGet, be.endpoint+"/"+be.bucket+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := be.do(req, emptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+		var res s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse s3 object list: %w", err)
+		}
+		for _, obj := range res.Contents {
+			ret = append(ret, &gcs.Object{
+				Path:      strings.TrimPrefix(obj.Key, be.prefix),
+				CreatedAt: obj.LastModified,
+			})
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			return ret, nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+func (be *s3StorageBackend) remove(path string) error {
+	req, err := http.NewRequest(http.MethodDelete, be.objectURL(be.prefix+path), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := be.do(req, emptyPayloadHash)
+	if err == errS3NotFound {
+		// S3 returns success for missing objects, but other implementations may not.
+		return ErrAssetDoesNotExist
+	} else if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (be *s3StorageBackend) objectURL(key string) string {
+	return be.endpoint + "/" + be.bucket + "/" + s3URIEncode(key, false)
+}
+
+var errS3NotFound = fmt.Errorf("s3 object not found")
+
+// do signs and sends the request. The caller must close the response body.
+func (be *s3StorageBackend) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	be.sign(req, payloadHash, be.now())
+	resp, err := be.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errS3NotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	return nil, fmt.Errorf("s3 %s %s failed: %s: %s", req.Method, req.URL.Path, resp.Status, body)
+}
+
+var emptyPayloadHash = hex.EncodeToString(sha256.New().Sum(nil))
+
+// sign adds the AWS Signature Version 4 authorization header to the request.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func (be *s3StorageBackend) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+	var query []string
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			query = append(query, s3URIEncode(name, true)+"="+s3URIEncode(value, true))
+		}
+	}
+	sort.Strings(query)
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.Join(query, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + be.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := []byte("AWS4" + be.secretKey)
+	for _, part := range []string{date, be.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		be.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3URIEncode encodes the string as required by the signature algorithm:
+// everything except unreserved characters is percent-encoded, slashes are kept in object keys.
+func s3URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -5,6 +5,7 @@ package asset
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/google/syzkaller/dashboard/dashapi"
@@ -16,9 +17,20 @@ type Config struct {
 	Debug bool `json:"debug"`
 	// Where to upload artifacts.
 	// If "gs://bucket/" is specified, assets will be stored in the corresponding GCS bucket.
+	// If "file:///path/to/dir" is specified, assets will be stored in the local directory
+	// (syz-manager serves it over HTTP at /assets/).
+	// If "s3://bucket/[prefix]" is specified, assets will be stored in an S3-compatible
+	// object store configured by S3.
 	// If "dummy://" is specified, assets will not be actually stored anywhere. May be helpful
 	// for debugging.
 	UploadTo string `json:"upload_to"`
+	// For file:// and s3:// storages: the URL prefix assets are downloaded from,
+	// e.g. "http://manager-host:56741/assets/" for a directory served by syz-manager.
+	// By default, file:// URLs are used for local directories and the path-style
+	// endpoint/bucket/ URLs are used for S3.
+	DownloadURL string `json:"download_url"`
+	// S3-compatible object store settings, required for s3:// storages.
+	S3 *S3Config `json:"s3"`
 	// Perform asset deprecation from this instance. If several syz-ci's share a common stoage,
 	// it make sense to enable derprecation only on one of them.
 	DoDeprecation bool `json:"do_deprecation"`
@@ -29,6 +41,16 @@ type Config struct {
 	Assets map[dashapi.AssetType]TypeConfig `json:"assets"`
 }
 
+type S3Config struct {
+	// Endpoint of the service, e.g. "https://s3.us-east-1.amazonaws.com" or "http://localhost:9000".
+	Endpoint string `json:"endpoint"`
+	// Region used for request signing, "us-east-1" by default.
+	Region string `json:"region"`
+	// If not set, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables are used.
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+}
+
 type TypeConfig struct {
 	Never bool `json:"never"`
 	// TODO: in future there'll also be `OnlyOn` and `NeverOn`, but so far we don't really need that.
@@ -47,6 +69,14 @@ func (c *Config) IsEmpty() bool {
 	return c == nil
 }
 
+// LocalDir returns the directory assets are stored in for file:// storages, and "" otherwise.
+func (c *Config) LocalDir() string {
+	if c == nil || !strings.HasPrefix(c.UploadTo, "file://") {
+		return ""
+	}
+	return strings.TrimPrefix(c.UploadTo, "file://")
+}
+
 func (c *Config) Validate() error {
 	for assetType, cfg := range c.Assets {
 		if GetTypeDescription(assetType) == nil {
@@ -59,7 +89,7 @@ func (c *Config) Validate() error {
 	if c.UploadTo == "" && len(c.Assets) != 0 {
 		return fmt.Errorf("assets are specified, but upload_to is empty")
 	}
-	allowedFormats := []string{"gs://", "dummy://"}
+	allowedFormats := []string{"gs://", "file://", "s3://", "dummy://"}
 	if c.UploadTo != "" {
 		any := false
 		for _, prefix := range allowedFormats {
@@ -71,5 +101,11 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("the currently supported upload destinations are: %v", allowedFormats)
 		}
 	}
+	if strings.HasPrefix(c.UploadTo, "file://") && !filepath.IsAbs(c.LocalDir()) {
+		return fmt.Errorf("file:// upload destination must be an absolute path")
+	}
+	if strings.HasPrefix(c.UploadTo, "s3://") && (c.S3 == nil || c.S3.Endpoint == "") {
+		return fmt.Errorf("s3:// upload destination requires s3 endpoint")
+	}
 	return nil
 }
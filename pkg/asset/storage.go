@@ -52,6 +52,18 @@ func StorageFromConfig(cfg *Config, dash Dashboard) (*Storage, error) {
 		if err != nil {
 			return nil, fmt.Errorf("the call to MakeCloudStorageBackend failed: %w", err)
 		}
+	} else if dir := cfg.LocalDir(); dir != "" {
+		var err error
+		backend, err = makeLocalStorageBackend(dir, cfg.DownloadURL, tracer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local storage backend: %w", err)
+		}
+	} else if strings.HasPrefix(cfg.UploadTo, "s3://") {
+		var err error
+		backend, err = makeS3StorageBackend(strings.TrimPrefix(cfg.UploadTo, "s3://"), cfg, tracer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 storage backend: %w", err)
+		}
 	} else if strings.HasPrefix(cfg.UploadTo, "dummy://") {
 		backend = makeDummyStorageBackend()
 	} else {
@@ -0,0 +1,35 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package ast
+
+// Deps returns names of all identifiers the node refers to: types, resources, flags, consts,
+// as well as field names and template arguments (the node's own name is not included).
+// Since names are not resolved at this level, the result is a superset of
+// declarations and consts the node depends on.
+func Deps(n Node) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	var rec func(Node)
+	rec = Recursive(func(n Node) bool {
+		switch n := n.(type) {
+		case *Type:
+			add(n.Ident)
+			// Colon types (ranges and bitfields) are not visited by walk.
+			for _, col := range n.Colon {
+				rec(col)
+			}
+		case *Int:
+			add(n.Ident)
+		}
+		return true
+	})
+	rec(n)
+	return names
+}
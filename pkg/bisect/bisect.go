@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/syzkaller/pkg/build"
@@ -43,6 +46,16 @@ type Config struct {
 	// Kernel.Commit is not reachable from Kernel.Branch.
 	// In this case, bisection starts from their merge base.
 	CrossTree bool
+	// Parallel is the max number of commits that are built and tested at the same time.
+	// If it's > 1, bisection also tests commits that it will likely need to test in the next steps
+	// (each in a separate kernel checkout next to Manager.KernelSrc), so that every step
+	// narrows down the commit range more than 2x. Note that this requires Parallel times
+	// more VMs and build resources.
+	Parallel int
+	// ConfigBisect makes bisection find the kernel config options that trigger the crash
+	// instead of the guilty commit. Kernel.Config must reproduce the crash on Kernel.Commit,
+	// while Kernel.BaselineConfig must not.
+	ConfigBisect bool
 }
 
 type KernelConfig struct {
@@ -82,19 +95,22 @@ type env struct {
 	repo         vcs.Repo
 	bisecter     vcs.Bisecter
 	minimizer    vcs.ConfigMinimizer
+	configs      vcs.ConfigBisecter
 	commit       *vcs.Commit
 	head         *vcs.Commit
 	kernelConfig []byte
 	inst         instance.Env
 	numTests     int
+	// The value of numTests when the env was forked, see fork.
+	forkNumTests int
 	startTime    time.Time
 	buildTime    time.Duration
 	testTime     time.Duration
 	reportTypes  []crash.Type
 	// The current estimate of the reproducer's kernel crashing probability.
 	reproChance float64
-	// The product of our confidence in every bisection step result.
-	confidence float64
+	// What we know about reliability of every bisection step result.
+	confidence *confidence
 	// Whether we should do 2x more execution runs for every test step.
 	// We could have inferred this data from reproChance, but we want to be
 	// able to react faster to sudden drops of reproducibility than an estimate
@@ -103,6 +119,22 @@ type env struct {
 	// A cache of already performed revision tests.
 	results  map[string]*testResult
 	buildCfg instance.BuildKernelConfig
+	// Additional kernel checkouts used for parallel bisection.
+	workers  []*worker
+	parallel vcs.ParallelBisecter
+	// Results of parallel tests of commits that bisection may need in the next steps.
+	speculative        map[string]*testResult
+	numSpeculative     int
+	numSpeculativeUsed int
+	// Estimate of the wall-clock time saved by testing commits in parallel.
+	timeSaved time.Duration
+}
+
+// worker is an additional kernel checkout along with a test environment.
+type worker struct {
+	repo     vcs.Repo
+	bisecter vcs.Bisecter
+	inst     instance.Env
 }
 
 const MaxNumTests = 20 // number of tests we do per commit
@@ -126,14 +158,31 @@ const MaxNumTests = 20 // number of tests we do per commit
 //   - Commit points to the oldest/latest commit where crash happens.
 //
 // 4. Config contains kernel config used for bisection.
+//
+// 5. TimeSaved is an estimate of the wall-clock time saved by parallel bisection.
+//
+// 6. For config bisection, Configs contains the minimal set of config options that trigger the crash,
+// Config is the baseline config with these options enabled, and Commit is the tested commit.
+//
+// 7. Confidence is the product of our confidence in every bisection step result,
+// i.e. the probability that none of the revisions tested as good was a false negative.
+// ConfidenceScore additionally takes into account heuristic penalties (ignored transient errors,
+// different crash types, skipped revisions, infra errors, config minimization).
+// ConfidenceFactors explain what has decreased ConfidenceScore, and LowConfidence is set
+// if ConfidenceScore is below the LowConfidence threshold.
 type Result struct {
-	Commits    []*vcs.Commit
-	Report     *report.Report
-	Commit     *vcs.Commit
-	Config     []byte
-	NoopChange bool
-	IsRelease  bool
-	Confidence float64
+	Commits           []*vcs.Commit
+	Report            *report.Report
+	Commit            *vcs.Commit
+	Config            []byte
+	NoopChange        bool
+	IsRelease         bool
+	Confidence        float64
+	ConfidenceScore   float64
+	ConfidenceFactors []ConfidenceFactor
+	LowConfidence     bool
+	TimeSaved         time.Duration
+	Configs           []string
 }
 
 // Run does the bisection and returns either the Result,
@@ -154,10 +203,48 @@ func Run(cfg *Config) (*Result, error) {
 	if _, err = repo.CheckoutBranch(cfg.Kernel.Repo, cfg.Kernel.Branch); err != nil {
 		return nil, &build.InfraError{Title: fmt.Sprintf("%v", err)}
 	}
-	return runImpl(cfg, repo, inst)
+	workers, err := createWorkers(cfg, repo)
+	if err != nil {
+		return nil, err
+	}
+	return runImpl(cfg, repo, inst, workers...)
+}
+
+func createWorkers(cfg *Config, repo vcs.Repo) ([]*worker, error) {
+	if cfg.Parallel <= 1 {
+		return nil, nil
+	}
+	parallel, ok := repo.(vcs.ParallelBisecter)
+	if !ok {
+		return nil, fmt.Errorf("parallel bisection is not implemented for %v", cfg.Manager.TargetOS)
+	}
+	var workers []*worker
+	for i := 1; i < cfg.Parallel; i++ {
+		dir := fmt.Sprintf("%v-bisect%v", strings.TrimSuffix(cfg.Manager.KernelSrc, "/"), i)
+		if err := parallel.AddWorktree(dir); err != nil {
+			return nil, fmt.Errorf("failed to create kernel checkout %v: %w", dir, err)
+		}
+		workerRepo, err := vcs.NewRepo(cfg.Manager.TargetOS, cfg.Manager.Type, dir)
+		if err != nil {
+			return nil, err
+		}
+		mgrcfg := *cfg.Manager
+		mgrcfg.KernelSrc = dir
+		mgrcfg.Workdir = filepath.Join(cfg.Manager.Workdir, fmt.Sprintf("bisect%v", i))
+		inst, err := instance.NewEnv(&mgrcfg, cfg.BuildSemaphore, cfg.TestSemaphore)
+		if err != nil {
+			return nil, err
+		}
+		workers = append(workers, &worker{
+			repo:     workerRepo,
+			bisecter: workerRepo.(vcs.Bisecter),
+			inst:     inst,
+		})
+	}
+	return workers, nil
 }
 
-func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
+func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env, workers ...*worker) (*Result, error) {
 	bisecter, ok := repo.(vcs.Bisecter)
 	if !ok {
 		return nil, fmt.Errorf("bisection is not implemented for %v", cfg.Manager.TargetOS)
@@ -166,14 +253,28 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 	if !ok && len(cfg.Kernel.BaselineConfig) != 0 {
 		return nil, fmt.Errorf("config minimization is not implemented for %v", cfg.Manager.TargetOS)
 	}
+	configs, ok := repo.(vcs.ConfigBisecter)
+	if !ok && cfg.ConfigBisect {
+		return nil, fmt.Errorf("config bisection is not implemented for %v", cfg.Manager.TargetOS)
+	}
+	var parallel vcs.ParallelBisecter
+	if len(workers) != 0 {
+		if parallel, ok = repo.(vcs.ParallelBisecter); !ok {
+			return nil, fmt.Errorf("parallel bisection is not implemented for %v", cfg.Manager.TargetOS)
+		}
+	}
 	env := &env{
-		cfg:        cfg,
-		repo:       repo,
-		bisecter:   bisecter,
-		minimizer:  minimizer,
-		inst:       inst,
-		startTime:  time.Now(),
-		confidence: 1.0,
+		cfg:         cfg,
+		repo:        repo,
+		bisecter:    bisecter,
+		minimizer:   minimizer,
+		configs:     configs,
+		inst:        inst,
+		startTime:   time.Now(),
+		confidence:  newConfidence(),
+		workers:     workers,
+		parallel:    parallel,
+		speculative: make(map[string]*testResult),
 		buildCfg: instance.BuildKernelConfig{
 			CompilerBin:  cfg.DefaultCompiler,
 			MakeBin:      cfg.Make,
@@ -197,22 +298,40 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 		hostname = "unnamed host"
 	}
 	env.logf("%s starts bisection %s", hostname, env.startTime.String())
-	if cfg.Fix {
+	if cfg.ConfigBisect {
+		env.logf("bisecting kernel configs on %v", cfg.Kernel.Commit)
+	} else if cfg.Fix {
 		env.logf("bisecting fixing commit since %v", cfg.Kernel.Commit)
 	} else {
 		env.logf("bisecting cause commit starting from %v", cfg.Kernel.Commit)
 	}
 	start := time.Now()
-	res, err := env.bisect()
+	var res *Result
+	if cfg.ConfigBisect {
+		res, err = env.bisectConfig()
+	} else {
+		res, err = env.bisect()
+	}
 	if env.flaky {
 		env.logf("reproducer is flaky (%.2f repro chance estimate)", env.reproChance)
 	}
 	env.logf("revisions tested: %v, total time: %v (build: %v, test: %v)",
 		env.numTests, time.Since(start), env.buildTime, env.testTime)
+	if len(workers) != 0 {
+		env.logf("parallel bisection: %v speculative tests, %v used, saved %v",
+			env.numSpeculative, env.numSpeculativeUsed, env.timeSaved)
+	}
 	if err != nil {
 		env.logf("error: %v", err)
 		return nil, err
 	}
+	if cfg.ConfigBisect {
+		env.logf("configs that trigger the crash: %v", res.Configs)
+		if res.Report != nil {
+			env.logf("crash: %v\n%s", res.Report.Title, res.Report.Report)
+		}
+		return res, nil
+	}
 	if len(res.Commits) == 0 {
 		if cfg.Fix {
 			env.logf("crash still not fixed or there were kernel test errors")
@@ -261,6 +380,12 @@ func (env *env) bisect() (*Result, error) {
 	if _, err := env.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
 		return nil, err
 	}
+	for _, w := range env.workers {
+		// Syzkaller is already built at this point, but the test environment needs to detect its features.
+		if _, err := w.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
+			return nil, err
+		}
+	}
 
 	cfg.Kernel.Commit, err = env.identifyRewrittenCommit()
 	if err != nil {
@@ -290,6 +415,7 @@ func (env *env) bisect() (*Result, error) {
 	if testRes1 != nil {
 		// If config minimization even partially succeeds, minimizeConfig()
 		// would return a non-nil value of a new report.
+		env.confidence.addMinimization(testRes, testRes1)
 		testRes = testRes1
 		// Overwrite bug's reproducibility - it may be different after config minimization.
 		env.reproChance = testRes.badRatio
@@ -312,12 +438,12 @@ func (env *env) bisect() (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	env.logf("accumulated error probability: %0.2f", 1.0-env.confidence)
 	res := &Result{
-		Commits:    commits,
-		Config:     env.kernelConfig,
-		Confidence: env.confidence,
+		Commits:   commits,
+		Config:    env.kernelConfig,
+		TimeSaved: max(0, env.timeSaved),
 	}
+	env.setConfidence(res)
 	if len(commits) == 1 {
 		com := commits[0]
 		testRes := env.results[com.Hash]
@@ -599,6 +725,15 @@ type testResult struct {
 	badRatio float64
 	// An estimate how much we can trust the result.
 	confidence float64
+	// The number of test runs, and how many of them failed due to infra problems
+	// or crashed with ignored transient errors.
+	runs      int
+	infra     int
+	transient int
+	// How long it took to build and test the revision.
+	duration time.Duration
+	// Whether the reproducer was considered flaky when the number of test runs was chosen.
+	flaky bool
 }
 
 func (env *env) build() (*vcs.Commit, string, error) {
@@ -642,6 +777,7 @@ func (env *env) test() (*testResult, error) {
 	if cfg.Timeout != 0 && time.Since(env.startTime) > cfg.Timeout {
 		return nil, fmt.Errorf("bisection is taking too long (>%v), aborting", cfg.Timeout)
 	}
+	start := time.Now()
 	current, kernelSign, err := env.build()
 	res := &testResult{
 		verdict:    vcs.BisectSkip,
@@ -670,9 +806,11 @@ func (env *env) test() (*testResult, error) {
 
 		env.logf("%s", errInfo)
 		res.rep = &report.Report{Title: errInfo}
+		res.duration = time.Since(start)
 		return res, nil
 	}
 
+	res.flaky = env.flaky
 	numTests := MaxNumTests / 2
 	if env.flaky || env.numTests == 0 {
 		// Use twice as many instances if the bug is flaky and during initial testing
@@ -690,7 +828,8 @@ func (env *env) test() (*testResult, error) {
 		env.log(problem)
 		return res, &build.InfraError{Title: problem}
 	}
-	bad, good, infra, rep, types := env.processResults(current, results)
+	bad, good, infra, transient, rep, types := env.processResults(current, results)
+	res.runs, res.infra, res.transient = len(results), infra, transient
 	res.verdict, err = env.bisectionDecision(len(results), bad, good, infra)
 	if err != nil {
 		return nil, err
@@ -712,6 +851,7 @@ func (env *env) test() (*testResult, error) {
 		res.rep = rep
 	}
 	res.types = types
+	res.duration = time.Since(start)
 	env.updateFlaky(res)
 	// TODO: when we start supporting boot/test error bisection, we need to make
 	// processResults treat that verdit as "good".
@@ -740,7 +880,7 @@ func (env *env) testPredicate() (vcs.BisectResult, error) {
 	}
 	if testRes1 == nil {
 		var err error
-		testRes1, err = env.test()
+		testRes1, err = env.testBisectStep()
 		if err != nil {
 			return 0, err
 		}
@@ -758,6 +898,131 @@ func (env *env) testPredicate() (vcs.BisectResult, error) {
 	return testRes1.verdict, nil
 }
 
+// testBisectStep tests the current bisection revision. For parallel bisection it also tests
+// the revisions that bisection will likely need to test in the next steps on other workers.
+func (env *env) testBisectStep() (*testResult, error) {
+	current, err := env.repo.Commit(vcs.HEAD)
+	if err != nil {
+		return nil, err
+	}
+	if res := env.takeSpeculative(current.Hash); res != nil {
+		return res, nil
+	}
+	if len(env.workers) == 0 {
+		return env.test()
+	}
+	candidates, err := env.parallel.BisectCandidates(len(env.workers))
+	if err != nil {
+		env.logf("failed to determine next bisection steps: %v", err)
+		return env.test()
+	}
+	var joins []func()
+	var wg sync.WaitGroup
+	for i, hash := range candidates {
+		if env.speculative[hash] != nil {
+			continue
+		}
+		w := env.workers[i]
+		fork := env.fork(w)
+		joins = append(joins, func() { env.join(fork) })
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := w.repo.SwitchCommit(hash); err != nil {
+				fork.logf("failed to checkout %v: %v", hash, err)
+				return
+			}
+			res, err := fork.test()
+			if err != nil {
+				fork.logf("parallel test of %v failed: %v", hash, err)
+				return
+			}
+			fork.speculative[hash] = res
+		}()
+	}
+	start := time.Now()
+	res, err := env.test()
+	mainDuration := time.Since(start)
+	wg.Wait()
+	// We could wait for slower parallel tests.
+	env.timeSaved -= time.Since(start) - mainDuration
+	for _, join := range joins {
+		join()
+	}
+	return res, err
+}
+
+// takeSpeculative returns the result of the parallel test of the commit, if it's still usable.
+func (env *env) takeSpeculative(hash string) *testResult {
+	res := env.speculative[hash]
+	if res == nil {
+		return nil
+	}
+	delete(env.speculative, hash)
+	if env.flaky && !res.flaky {
+		// The reproducer has become flaky after the test was started,
+		// so the result is based on fewer test runs than we need now.
+		env.logf("discarding the parallel test of %v: the reproducer is flaky", hash)
+		return nil
+	}
+	env.logf("using the result of the parallel test of %v", hash)
+	env.numSpeculativeUsed++
+	env.timeSaved += res.duration
+	return res
+}
+
+// fork creates a copy of env that tests revisions on the worker.
+// The copy buffers its log, so that logs of parallel tests are not intermixed.
+func (env *env) fork(w *worker) *env {
+	cfg := *env.cfg
+	cfg.Trace = &bufferTracer{parent: env.cfg.Trace}
+	fork := *env
+	fork.cfg = &cfg
+	fork.repo = w.repo
+	fork.bisecter = w.bisecter
+	fork.inst = w.inst
+	fork.forkNumTests = env.numTests
+	fork.buildTime = 0
+	fork.testTime = 0
+	fork.workers = nil
+	fork.speculative = make(map[string]*testResult)
+	return &fork
+}
+
+// join merges the results and statistics of the forked env back.
+func (env *env) join(fork *env) {
+	fork.cfg.Trace.(*bufferTracer).flush()
+	env.numTests += fork.numTests - fork.forkNumTests
+	env.buildTime += fork.buildTime
+	env.testTime += fork.testTime
+	env.flaky = env.flaky || fork.flaky
+	for hash, res := range fork.speculative {
+		env.speculative[hash] = res
+		env.numSpeculative++
+	}
+}
+
+// bufferTracer postpones all calls to the parent tracer until flush,
+// which is invoked only from the main bisection goroutine.
+type bufferTracer struct {
+	parent debugtracer.DebugTracer
+	log    []func()
+}
+
+func (bt *bufferTracer) Log(msg string, args ...interface{}) {
+	bt.log = append(bt.log, func() { bt.parent.Log("parallel: "+msg, args...) })
+}
+
+func (bt *bufferTracer) SaveFile(filename string, data []byte) {
+	bt.log = append(bt.log, func() { bt.parent.SaveFile(filename, data) })
+}
+
+func (bt *bufferTracer) flush() {
+	for _, log := range bt.log {
+		log()
+	}
+}
+
 // If there's a merge from a branch that was based on a much older code revision,
 // it's likely that the bug was not yet present at all.
 var errUnknownBugPresence = errors.New("unable to determine whether there was a bug")
@@ -855,7 +1120,7 @@ func (env *env) bisectionDecision(total, bad, good, infra int) (vcs.BisectResult
 }
 
 func (env *env) processResults(current *vcs.Commit, results []instance.EnvTestResult) (
-	bad, good, infra int, rep *report.Report, types []crash.Type) {
+	bad, good, infra, transient int, rep *report.Report, types []crash.Type) {
 	var verdicts []string
 	var reports []*report.Report
 	for i, res := range results {
@@ -888,6 +1153,7 @@ func (env *env) processResults(current *vcs.Commit, results []instance.EnvTestRe
 			}
 			env.saveDebugFile(current.Hash, i, output)
 			if env.isTransientError(crashError.Report) {
+				transient++
 				verdicts = append(verdicts, fmt.Sprintf("ignore: %v", crashError))
 				break
 			}
@@ -923,7 +1189,7 @@ func (env *env) processResults(current *vcs.Commit, results []instance.EnvTestRe
 // postTestResult() is to be run after we have got the results of a test() call for a revision.
 // It updates the estimates of reproducibility and the overall result confidence.
 func (env *env) postTestResult(res *testResult) {
-	env.confidence *= res.confidence
+	env.confidence.add(res, env.reportTypes)
 	if res.verdict == vcs.BisectBad {
 		// Let's be conservative and only decrease our reproduction likelihood estimate.
 		// As the estimate of each test() can also be flaky, only partially update the result.
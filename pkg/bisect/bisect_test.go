@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/google/syzkaller/pkg/build"
@@ -106,8 +107,15 @@ func (env *testEnv) Test(numVMs int, reproSyz, reproOpts, reproC []byte) ([]inst
 		introduced = commit != nil
 	}
 
-	if (env.config == "baseline-repro" || env.config == "new-minimized-config" || env.config == "original config") &&
-		introduced && !fixed {
+	crashConfig := env.config == "baseline-repro" || env.config == "new-minimized-config" ||
+		env.config == "original config"
+	if len(env.test.crashConfigs) != 0 {
+		crashConfig = true
+		for _, cfg := range env.test.crashConfigs {
+			crashConfig = crashConfig && strings.Contains(env.config, cfg+"=y\n")
+		}
+	}
+	if crashConfig && introduced && !fixed {
 		if env.test.flaky {
 			crashed := max(2, numVMs/6)
 			ret = crashErrors(crashed, numVMs-crashed, "crash occurs", env.test.reportType)
@@ -241,6 +249,26 @@ func testBisection(t *testing.T, baseDir string, test BisectionTest) {
 		r:    r,
 		test: test,
 	}
+	var workers []*worker
+	for i := 1; i < test.parallel; i++ {
+		dir := t.TempDir()
+		if err := r.(vcs.ParallelBisecter).AddWorktree(dir); err != nil {
+			t.Fatal(err)
+		}
+		wr, err := vcs.NewRepo(targets.TestOS, targets.TestArch64, dir, vcs.OptPrecious)
+		if err != nil {
+			t.Fatal(err)
+		}
+		workers = append(workers, &worker{
+			repo:     wr,
+			bisecter: wr.(vcs.Bisecter),
+			inst: &testEnv{
+				t:    t,
+				r:    wr,
+				test: test,
+			},
+		})
+	}
 
 	checkBisectionError := func(test BisectionTest, res *Result, err error) {
 		if test.expectErr != (err != nil) {
@@ -261,16 +289,70 @@ func testBisection(t *testing.T, baseDir string, test BisectionTest) {
 		}
 	}
 
-	res, err := runImpl(cfg, r, inst)
+	res, err := runImpl(cfg, r, inst, workers...)
 	checkBisectionError(test, res, err)
 	if !test.crossTree && !test.noFakeHashTest {
 		// Should be mitigated via GetCommitByTitle during bisection.
 		cfg.Kernel.Commit = fmt.Sprintf("fake-hash-for-%v-%v", cfg.Kernel.Commit, cfg.Kernel.CommitTitle)
-		res, err = runImpl(cfg, r, inst)
+		res, err = runImpl(cfg, r, inst, workers...)
 		checkBisectionError(test, res, err)
 	}
 }
 
+func TestConfigBisection(t *testing.T) {
+	t.Parallel()
+	baseDir := createTestRepo(t)
+	r, err := vcs.NewRepo(targets.TestOS, targets.TestArch64, baseDir, vcs.OptPrecious)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SwitchCommit("master")
+	head, err := r.Commit(vcs.HEAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := BisectionTest{
+		crashConfigs: []string{"CONFIG_B", "CONFIG_D"},
+	}
+	cfg := &Config{
+		Trace: &debugtracer.TestTracer{T: t},
+		Manager: &mgrconfig.Config{
+			Derived: mgrconfig.Derived{
+				TargetOS:     targets.TestOS,
+				TargetVMArch: targets.TestArch64,
+			},
+			Type:      "qemu",
+			KernelSrc: baseDir,
+		},
+		Kernel: KernelConfig{
+			Repo:           baseDir,
+			Branch:         "master",
+			Commit:         head.Hash,
+			Config:         []byte("CONFIG_A=y\nCONFIG_B=y\nCONFIG_C=y\nCONFIG_D=y\nCONFIG_E=y\n"),
+			BaselineConfig: []byte("CONFIG_A=y\n"),
+		},
+		ConfigBisect: true,
+	}
+	inst := &testEnv{
+		t:    t,
+		r:    r,
+		test: test,
+	}
+	res, err := runImpl(cfg, r, inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"B", "D"}, res.Configs)
+	assert.Equal(t, "CONFIG_A=y\nCONFIG_B=y\nCONFIG_D=y\n", string(res.Config))
+	assert.Equal(t, head.Hash, res.Commit.Hash)
+	assert.NotNil(t, res.Report)
+
+	// The crash must not reproduce with the baseline config.
+	cfg.Kernel.BaselineConfig = []byte("CONFIG_B=y\nCONFIG_D=y\n")
+	_, err = runImpl(cfg, r, inst)
+	assert.Error(t, err)
+}
+
 func checkBisectionResult(t *testing.T, test BisectionTest, res *Result) {
 	if len(res.Commits) != test.commitLen {
 		t.Fatalf("expected %d commits got %d commits", test.commitLen, len(res.Commits))
@@ -343,6 +425,10 @@ type BisectionTest struct {
 	resultingConfig string
 	crossTree       bool
 	noFakeHashTest  bool
+	// Number of commits to test in parallel.
+	parallel int
+	// For config bisection: the crash happens only if all these configs are enabled.
+	crashConfigs []string
 
 	extraTest func(t *testing.T, res *Result)
 }
@@ -357,6 +443,7 @@ var bisectionTests = []BisectionTest{
 		introduced:  "602",
 		extraTest: func(t *testing.T, res *Result) {
 			assert.Greater(t, res.Confidence, 0.99)
+			assert.Empty(t, res.ConfidenceFactors)
 		},
 	},
 	{
@@ -371,6 +458,10 @@ var bisectionTests = []BisectionTest{
 			// We get three "good" results, so our accumulated confidence is ~85%.
 			assert.Less(t, res.Confidence, 0.9)
 			assert.Greater(t, res.Confidence, 0.8)
+			assert.False(t, res.LowConfidence)
+			assert.Len(t, res.ConfidenceFactors, 1)
+			// There are no heuristic penalties.
+			assert.Equal(t, res.Confidence, res.ConfidenceScore)
 		},
 	},
 	// Test bisection returns correct cause with different baseline/config combinations.
@@ -723,6 +814,33 @@ var bisectionTests = []BisectionTest{
 		brokenEnd:         800,
 		oldestLatest:      800,
 	},
+	// Tests that parallel bisection gives the same results as the sequential one.
+	{
+		name:        "cause-finds-cause-parallel",
+		startCommit: 905,
+		commitLen:   1,
+		expectRep:   true,
+		introduced:  "602",
+		parallel:    3,
+	},
+	{
+		name:        "cause-inconclusive-parallel",
+		startCommit: 802,
+		brokenStart: 500,
+		brokenEnd:   700,
+		commitLen:   15,
+		introduced:  "605",
+		parallel:    4,
+	},
+	{
+		name:        "fix-after-bug-parallel",
+		fix:         true,
+		startCommit: 802,
+		commitLen:   1,
+		fixCommit:   "803",
+		introduced:  "704",
+		parallel:    2,
+	},
 }
 
 func TestBisectionResults(t *testing.T) {
@@ -1069,3 +1187,36 @@ func TestPickReleaseTags(t *testing.T) {
 		})
 	}
 }
+
+func TestJoinNumTests(t *testing.T) {
+	env := &env{
+		cfg:         &Config{Trace: &debugtracer.NullTracer{}},
+		numTests:    5,
+		speculative: make(map[string]*testResult),
+	}
+	fork1, fork2 := env.fork(&worker{}), env.fork(&worker{})
+	env.numTests++
+	fork1.numTests += 2
+	fork2.numTests += 3
+	env.join(fork1)
+	env.join(fork2)
+	assert.Equal(t, 11, env.numTests)
+}
+
+func TestTakeSpeculative(t *testing.T) {
+	env := &env{
+		cfg:         &Config{Trace: &debugtracer.NullTracer{}},
+		speculative: make(map[string]*testResult),
+	}
+	env.speculative["a"] = &testResult{}
+	env.speculative["b"] = &testResult{}
+	env.speculative["c"] = &testResult{flaky: true}
+	assert.NotNil(t, env.takeSpeculative("a"))
+	assert.Nil(t, env.takeSpeculative("a"))
+	// The results obtained before the reproducer turned out to be flaky must not be used.
+	env.flaky = true
+	assert.Nil(t, env.takeSpeculative("b"))
+	assert.NotNil(t, env.takeSpeculative("c"))
+	assert.Empty(t, env.speculative)
+	assert.Equal(t, 2, env.numSpeculativeUsed)
+}
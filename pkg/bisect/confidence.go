@@ -0,0 +1,167 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package bisect
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// LowConfidence is the confidence score below which the bisection result is flagged as unreliable.
+// It's compared against Result.ConfidenceScore.
+const LowConfidence = 0.8
+
+// ConfidenceFactor is one of the reasons why the bisection result may be wrong.
+type ConfidenceFactor struct {
+	// Human-readable explanation.
+	Reason string
+	// The multiplier this factor contributes to Result.ConfidenceScore, in (0, 1].
+	Score float64
+}
+
+const (
+	// A good verdict for a revision where some crashes were ignored as transient errors:
+	// these crashes might actually have been the bug.
+	transientErrorScore = 0.95
+	// A bad verdict for a revision that crashed differently from the original crash.
+	otherCrashScore = 0.9
+	// Each revision that could not be tested makes the culprit commit less certain.
+	skippedRevisionScore = 0.98
+	// Infra problems reduce the number of useful runs and are correlated with flaky results.
+	infraErrorsScore = 0.95
+	infraErrorsRatio = 0.1
+	// Config minimization changed the crash types or significantly reduced reproducibility.
+	minimizationTypesScore = 0.9
+	minimizationReproScore = 0.95
+)
+
+// confidence accumulates what we know about reliability of individual bisection steps.
+type confidence struct {
+	falseNegative float64
+	good          int
+	transient     int
+	otherCrash    int
+	skipped       int
+	infraRuns     int
+	totalRuns     int
+	minimization  []ConfidenceFactor
+}
+
+func newConfidence() *confidence {
+	return &confidence{falseNegative: 1.0}
+}
+
+// add records the result of a bisection step.
+// reportTypes are crash types of the bug we are bisecting.
+func (c *confidence) add(res *testResult, reportTypes []crash.Type) {
+	c.infraRuns += res.infra
+	c.totalRuns += res.runs
+	switch res.verdict {
+	case vcs.BisectGood:
+		c.good++
+		c.falseNegative *= res.confidence
+		if res.transient != 0 {
+			c.transient++
+		}
+	case vcs.BisectBad:
+		if len(res.types) != 0 && len(reportTypes) != 0 && !typesIntersect(res.types, reportTypes) {
+			c.otherCrash++
+		}
+	case vcs.BisectSkip:
+		c.skipped++
+	}
+}
+
+// addMinimization records the effect of config minimization.
+func (c *confidence) addMinimization(orig, minimized *testResult) {
+	if len(orig.types) != 0 && len(minimized.types) != 0 && !typesIntersect(orig.types, minimized.types) {
+		c.minimization = append(c.minimization, ConfidenceFactor{
+			Reason: fmt.Sprintf("config minimization changed crash types from %v to %v",
+				orig.types, minimized.types),
+			Score: minimizationTypesScore,
+		})
+	}
+	if minimized.badRatio < orig.badRatio/2 {
+		c.minimization = append(c.minimization, ConfidenceFactor{
+			Reason: fmt.Sprintf("config minimization reduced reproducibility from %.2f to %.2f",
+				orig.badRatio, minimized.badRatio),
+			Score: minimizationReproScore,
+		})
+	}
+}
+
+// factors returns all factors that decrease the confidence.
+func (c *confidence) factors() []ConfidenceFactor {
+	var res []ConfidenceFactor
+	if c.falseNegative < 1.0 {
+		res = append(res, ConfidenceFactor{
+			Reason: fmt.Sprintf("%v revision(s) tested as good may be false negatives", c.good),
+			Score:  c.falseNegative,
+		})
+	}
+	if c.transient != 0 {
+		res = append(res, ConfidenceFactor{
+			Reason: fmt.Sprintf("%v revision(s) tested as good had crashes ignored as transient errors",
+				c.transient),
+			Score: math.Pow(transientErrorScore, float64(c.transient)),
+		})
+	}
+	if c.otherCrash != 0 {
+		res = append(res, ConfidenceFactor{
+			Reason: fmt.Sprintf("%v revision(s) tested as bad crashed differently from the original crash",
+				c.otherCrash),
+			Score: math.Pow(otherCrashScore, float64(c.otherCrash)),
+		})
+	}
+	if c.skipped != 0 {
+		res = append(res, ConfidenceFactor{
+			Reason: fmt.Sprintf("%v revision(s) were skipped because of build, boot or test failures",
+				c.skipped),
+			Score: math.Pow(skippedRevisionScore, float64(c.skipped)),
+		})
+	}
+	if c.totalRuns != 0 && float64(c.infraRuns) > infraErrorsRatio*float64(c.totalRuns) {
+		res = append(res, ConfidenceFactor{
+			Reason: fmt.Sprintf("%v out of %v test runs failed because of infrastructure problems",
+				c.infraRuns, c.totalRuns),
+			Score: infraErrorsScore,
+		})
+	}
+	return append(res, c.minimization...)
+}
+
+// score returns the overall confidence in the bisection result.
+func (c *confidence) score() float64 {
+	score := 1.0
+	for _, f := range c.factors() {
+		score *= f.Score
+	}
+	return score
+}
+
+func typesIntersect(a, b []crash.Type) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (env *env) setConfidence(res *Result) {
+	res.Confidence = env.confidence.falseNegative
+	res.ConfidenceScore = env.confidence.score()
+	res.ConfidenceFactors = env.confidence.factors()
+	res.LowConfidence = res.ConfidenceScore < LowConfidence
+	env.logf("accumulated error probability: %0.2f", 1.0-res.Confidence)
+	env.logf("confidence score: %0.2f", res.ConfidenceScore)
+	for _, f := range res.ConfidenceFactors {
+		env.logf("confidence %0.2f: %v", f.Score, f.Reason)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package bisect
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/pkg/vcs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfidence(t *testing.T) {
+	c := newConfidence()
+	assert.Empty(t, c.factors())
+	assert.Equal(t, 1.0, c.score())
+
+	reportTypes := []crash.Type{crash.KASANRead}
+	// A clean bad result does not affect the confidence.
+	c.add(&testResult{verdict: vcs.BisectBad, types: reportTypes, runs: 10}, reportTypes)
+	assert.Empty(t, c.factors())
+
+	c.add(&testResult{verdict: vcs.BisectGood, confidence: 0.9, runs: 10, transient: 2}, reportTypes)
+	c.add(&testResult{verdict: vcs.BisectGood, confidence: 0.9, runs: 10}, reportTypes)
+	c.add(&testResult{verdict: vcs.BisectBad, types: []crash.Type{crash.Warning}, runs: 10}, reportTypes)
+	c.add(&testResult{verdict: vcs.BisectSkip, runs: 10, infra: 6}, reportTypes)
+	c.addMinimization(
+		&testResult{types: reportTypes, badRatio: 1.0},
+		&testResult{types: []crash.Type{crash.Warning}, badRatio: 0.3},
+	)
+	var scores []float64
+	for _, f := range c.factors() {
+		scores = append(scores, f.Score)
+	}
+	assert.InDeltaSlice(t, []float64{
+		0.81,
+		transientErrorScore,
+		otherCrashScore,
+		skippedRevisionScore,
+		infraErrorsScore,
+		minimizationTypesScore,
+		minimizationReproScore,
+	}, scores, 1e-9)
+	assert.InDelta(t, 0.81*0.95*0.9*0.98*0.95*0.9*0.95, c.score(), 1e-9)
+	assert.Less(t, c.score(), LowConfidence)
+	// The false negative probability (Result.Confidence) is not affected by the heuristic penalties.
+	assert.InDelta(t, 0.81, c.falseNegative, 1e-9)
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package bisect
+
+import (
+	"fmt"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// bisectConfig finds the config options that are enabled in Kernel.Config, but not in
+// Kernel.BaselineConfig, and that trigger the crash on Kernel.Commit.
+func (env *env) bisectConfig() (*Result, error) {
+	cfg := env.cfg
+	if len(cfg.Kernel.BaselineConfig) == 0 {
+		return nil, fmt.Errorf("config bisection requires a baseline config")
+	}
+	if err := env.inst.CleanKernel(&env.buildCfg); err != nil {
+		return nil, fmt.Errorf("kernel clean failed: %w", err)
+	}
+	env.logf("building syzkaller on %v", cfg.Syzkaller.Commit)
+	if _, err := env.inst.BuildSyzkaller(cfg.Syzkaller.Repo, cfg.Syzkaller.Commit); err != nil {
+		return nil, err
+	}
+	com, err := env.repo.SwitchCommit(cfg.Kernel.Commit)
+	if err != nil {
+		return nil, err
+	}
+	env.commit = com
+	testResults := make(map[hash.Sig]*testResult)
+	pred := func(test []byte) (vcs.BisectResult, error) {
+		env.kernelConfig = test
+		testRes, err := env.test()
+		if err != nil {
+			return 0, err
+		}
+		if testRes.verdict == vcs.BisectBad {
+			if env.reportTypes == nil {
+				// The first test is done with the original config, remember the crash.
+				env.reportTypes = testRes.types
+				env.reproChance = testRes.badRatio
+			}
+			testResults[hash.Hash(test)] = testRes
+		}
+		env.postTestResult(testRes)
+		return testRes.verdict, nil
+	}
+	configs, err := env.configs.BisectConfig(cfg.Manager.SysTarget, cfg.Kernel.Config,
+		cfg.Kernel.BaselineConfig, cfg.Trace, pred)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{
+		Commit:  com,
+		Config:  configs.Config,
+		Configs: configs.Culprits,
+	}
+	if testRes := testResults[hash.Hash(configs.Config)]; testRes != nil {
+		res.Report = testRes.rep
+	}
+	env.setConfidence(res)
+	return res, nil
+}
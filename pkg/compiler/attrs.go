@@ -5,6 +5,7 @@ package compiler
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/google/syzkaller/pkg/ast"
 	"github.com/google/syzkaller/prog"
@@ -111,3 +112,46 @@ func makeAttrs(attrs ...*attrDesc) map[string]*attrDesc {
 	}
 	return m
 }
+
+// Keywords lists builtin names that can be used in descriptions (e.g. for code completion in editors).
+type Keywords struct {
+	Types       []string // builtin types and type templates
+	StructAttrs []string
+	UnionAttrs  []string
+	FieldAttrs  []string // attributes of struct and union fields
+	CallAttrs   []string
+}
+
+func BuiltinKeywords() *Keywords {
+	kw := &Keywords{
+		StructAttrs: sortedAttrs(structAttrs),
+		UnionAttrs:  sortedAttrs(unionAttrs),
+		FieldAttrs:  sortedAttrs(structFieldAttrs, unionFieldAttrs),
+		CallAttrs:   sortedAttrs(callAttrs),
+	}
+	for name := range builtinTypes {
+		kw.Types = append(kw.Types, name)
+	}
+	for _, n := range builtinDescs.Nodes {
+		if typedef, ok := n.(*ast.TypeDef); ok {
+			kw.Types = append(kw.Types, typedef.Name.Name)
+		}
+	}
+	sort.Strings(kw.Types)
+	return kw
+}
+
+func sortedAttrs(maps ...map[string]*attrDesc) []string {
+	dedup := make(map[string]bool)
+	var res []string
+	for _, m := range maps {
+		for name := range m {
+			if !dedup[name] {
+				dedup[name] = true
+				res = append(res, name)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -29,10 +29,16 @@ func (comp *compiler) typecheck() {
 func (comp *compiler) check(consts map[string]uint64) {
 	comp.checkTypeValues()
 	comp.checkAttributeValues()
-	comp.checkUnused()
+	// Unused declarations and resource constructors can be checked only given all declarations,
+	// in the partial mode they are checked by checkAll.
+	if !comp.partial {
+		comp.checkUnused()
+	}
 	comp.checkRecursion()
 	comp.checkFieldPaths()
-	comp.checkConstructors()
+	if !comp.partial {
+		comp.checkConstructors()
+	}
 	comp.checkVarlens()
 	comp.checkDupConsts()
 	comp.checkConstsFlags(consts)
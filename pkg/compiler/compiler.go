@@ -38,6 +38,8 @@ type Prog struct {
 	Types     []prog.Type
 	// Set of unsupported syscalls/flags.
 	Unsupported map[string]bool
+	// Dependencies of all syscalls (including unsupported), filled only by CompileIncremental.
+	Calls map[string]*CallDeps
 	// Returned if consts was nil.
 	fileConsts map[string]*ConstInfo
 }
@@ -53,6 +55,7 @@ func createCompiler(desc *ast.Description, target *targets.Target, eh ast.ErrorH
 		eh:             eh,
 		ptrSize:        target.PtrSize,
 		unsupported:    make(map[string]bool),
+		missingConsts:  make(map[string]bool),
 		resources:      make(map[string]*ast.Resource),
 		typedefs:       make(map[string]*ast.TypeDef),
 		structs:        make(map[string]*ast.Struct),
@@ -99,7 +102,7 @@ func Compile(desc *ast.Description, consts map[string]uint64, target *targets.Ta
 	if comp.errors != 0 {
 		return nil
 	}
-	syscalls := comp.genSyscalls()
+	syscalls := comp.genSyscalls(comp.collectCallArgSizes())
 	comp.layoutTypes(syscalls)
 	types := comp.generateTypes(syscalls)
 	prg := &Prog{
@@ -124,8 +127,11 @@ type compiler struct {
 	errors   int
 	warnings []warn
 	ptrSize  uint64
+	// Set if desc contains only some of the declarations (see CompileIncremental).
+	partial bool
 
 	unsupported    map[string]bool
+	missingConsts  map[string]bool // subset of unsupported due to missing consts
 	resources      map[string]*ast.Resource
 	typedefs       map[string]*ast.TypeDef
 	structs        map[string]*ast.Struct
@@ -134,6 +134,60 @@ func TestData(t *testing.T) {
 	}
 }
 
+func TestLint(t *testing.T) {
+	t.Parallel()
+	target := targets.List[targets.TestOS][targets.TestArch64]
+	fileName := filepath.Join("testdata", "lint.txt")
+	em := ast.NewErrorMatcher(t, fileName)
+	desc := ast.Parse(em.Data, "lint.txt", em.ErrorHandler)
+	if desc == nil {
+		em.DumpErrors()
+		t.Fatalf("parsing failed")
+	}
+	constInfo := ExtractConsts(desc, target, em.ErrorHandler)
+	if constInfo == nil {
+		em.DumpErrors()
+		t.Fatalf("const extraction failed")
+	}
+	cf := NewConstFile()
+	if err := cf.AddArch(targets.TestArch64, map[string]uint64{
+		"LINT_IOW":         0x40046101,
+		"LINT_IOW2":        0x40046102,
+		"LINT_IOR":         0x80046103,
+		"LINT_IOWR":        0xc0046104,
+		"LINT_IO":          0x6105,
+		"LINT_FLAG1":       1,
+		"LINT_FLAG2":       2,
+		"LINT_FLAG1_ALIAS": 1,
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	FabricateSyscallConsts(target, constInfo, cf)
+	issues, err := Lint(desc, cf.Arch(targets.TestArch64), target, nil, em.ErrorHandler)
+	if err != nil {
+		em.DumpErrors()
+		t.Fatal(err)
+	}
+	for _, issue := range issues {
+		em.ErrorHandler(issue.Pos, fmt.Sprintf("%v (%v)", issue.Msg, issue.Check))
+		if issue.Fix != "" {
+			em.ErrorHandler(issue.Pos, fmt.Sprintf("fix: %v", issue.Fix))
+		}
+	}
+	em.Check()
+
+	issues, err = Lint(desc, cf.Arch(targets.TestArch64), target, []string{"ioctl-dir"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %v ioctl-dir issues, want 2", len(issues))
+	}
+	if _, err := Lint(desc, nil, target, []string{"no-such-check"}, nil); err == nil {
+		t.Fatalf("unknown check is not detected")
+	}
+}
+
 func TestAutoConsts(t *testing.T) {
 	t.Parallel()
 	eh := func(pos ast.Pos, msg string) {
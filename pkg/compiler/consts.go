@@ -256,6 +256,7 @@ func (comp *compiler) assignSyscallNumbers(consts map[string]uint64) {
 		}
 		c.NR = ^uint64(0) // mark as unused to not generate it
 		name := "syscall " + c.CallName
+		comp.missingConsts[name] = true
 		if !comp.unsupported[name] {
 			comp.unsupported[name] = true
 			comp.warning(c.Pos, "unsupported syscall: %v due to missing const %v",
@@ -333,7 +334,9 @@ func (comp *compiler) patchConsts(consts0 map[string]uint64) {
 			// better to remove just that option. But then if we get to 0
 			// options in the union, we still need to remove it entirely.
 			pos, typ, name := decl.Info()
-			if id := typ + " " + name; !comp.unsupported[id] {
+			id := typ + " " + name
+			comp.missingConsts[id] = true
+			if !comp.unsupported[id] {
 				comp.unsupported[id] = true
 				comp.warning(pos, "unsupported %v: %v due to missing const %v",
 					typ, name, missing)
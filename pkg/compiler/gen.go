@@ -53,48 +53,66 @@ func (comp *compiler) collectCallArgSizes() map[string][]uint64 {
 		if !ok {
 			continue
 		}
-		// Figure out number of arguments and their sizes for each syscall.
-		// For example, we may have:
-		// ioctl(fd fd, cmd int32, arg intptr)
-		// ioctl$FOO(fd fd, cmd const[FOO])
-		// Here we will figure out that ioctl$FOO have 3 args, even that
-		// only 2 are specified and that size of cmd is 4 even that
-		// normally we would assume it's 8 (intptr).
-		argSizes := callArgSizes[n.CallName]
-		for i, arg := range n.Args {
-			if len(argSizes) <= i {
-				argSizes = append(argSizes, comp.ptrSize)
-			}
-			desc, _, _ := comp.getArgsBase(arg.Type, true)
-			typ := comp.genField(arg, comp.ptrSize, prog.DirInOut)
-			// Ignore all types with base (const, flags). We don't have base in syscall args.
-			// Also ignore resources and pointers because fd can be 32-bits and pointer 64-bits,
-			// and then there is no way to fix this.
-			// The only relevant types left is plain int types.
-			if desc != typeInt {
-				continue
-			}
-			if !comp.target.Int64SyscallArgs && typ.Size() > comp.ptrSize {
-				comp.error(arg.Pos, "%v arg %v is larger than pointer size", n.Name.Name, arg.Name.Name)
-				continue
-			}
-			argID := fmt.Sprintf("%v|%v", comp.getCallName(n), i)
-			if _, ok := argPos[argID]; !ok {
-				argSizes[i] = typ.Size()
-				argPos[argID] = arg.Pos
-				continue
-			}
-			if argSizes[i] != typ.Size() {
-				comp.error(arg.Pos, "%v arg %v is redeclared with size %v, previously declared with size %v at %v",
-					n.Name.Name, arg.Name.Name, typ.Size(), argSizes[i], argPos[argID])
-				continue
-			}
-		}
-		callArgSizes[comp.getCallName(n)] = argSizes
+		comp.addCallArgSizes(callArgSizes, argPos, n, comp.intArgSizes(n))
 	}
 	return callArgSizes
 }
 
+// intArgSizes returns sizes of plain int arguments of the syscall (0 for all other arguments).
+func (comp *compiler) intArgSizes(n *ast.Call) []uint64 {
+	sizes := make([]uint64, len(n.Args))
+	for i, arg := range n.Args {
+		desc, _, _ := comp.getArgsBase(arg.Type, true)
+		typ := comp.genField(arg, comp.ptrSize, prog.DirInOut)
+		// Ignore all types with base (const, flags). We don't have base in syscall args.
+		// Also ignore resources and pointers because fd can be 32-bits and pointer 64-bits,
+		// and then there is no way to fix this.
+		// The only relevant types left is plain int types.
+		if desc != typeInt {
+			continue
+		}
+		if !comp.target.Int64SyscallArgs && typ.Size() > comp.ptrSize {
+			comp.error(arg.Pos, "%v arg %v is larger than pointer size", n.Name.Name, arg.Name.Name)
+			continue
+		}
+		sizes[i] = typ.Size()
+	}
+	return sizes
+}
+
+func (comp *compiler) addCallArgSizes(callArgSizes map[string][]uint64, argPos map[string]ast.Pos,
+	n *ast.Call, sizes []uint64) {
+	// Figure out number of arguments and their sizes for each syscall.
+	// For example, we may have:
+	// ioctl(fd fd, cmd int32, arg intptr)
+	// ioctl$FOO(fd fd, cmd const[FOO])
+	// Here we will figure out that ioctl$FOO have 3 args, even that
+	// only 2 are specified and that size of cmd is 4 even that
+	// normally we would assume it's 8 (intptr).
+	argSizes := callArgSizes[n.CallName]
+	for i, size := range sizes {
+		if len(argSizes) <= i {
+			argSizes = append(argSizes, comp.ptrSize)
+		}
+		if size == 0 {
+			continue
+		}
+		arg := n.Args[i]
+		argID := fmt.Sprintf("%v|%v", comp.getCallName(n), i)
+		if _, ok := argPos[argID]; !ok {
+			argSizes[i] = size
+			argPos[argID] = arg.Pos
+			continue
+		}
+		if argSizes[i] != size {
+			comp.error(arg.Pos, "%v arg %v is redeclared with size %v, previously declared with size %v at %v",
+				n.Name.Name, arg.Name.Name, size, argSizes[i], argPos[argID])
+			continue
+		}
+	}
+	callArgSizes[comp.getCallName(n)] = argSizes
+}
+
 func (comp *compiler) getCallName(n *ast.Call) string {
 	// getCallName is used for checking that all variants of the same syscall have same argument sizes
 	// for matching arguments. Automatically-generated syscalls may violate that condition,
@@ -106,8 +124,7 @@ func (comp *compiler) getCallName(n *ast.Call) string {
 	return n.CallName
 }
 
-func (comp *compiler) genSyscalls() []*prog.Syscall {
-	callArgSizes := comp.collectCallArgSizes()
+func (comp *compiler) genSyscalls(callArgSizes map[string][]uint64) []*prog.Syscall {
 	var calls []*prog.Syscall
 	for _, decl := range comp.desc.Nodes {
 		if n, ok := decl.(*ast.Call); ok && n.NR != ^uint64(0) {
@@ -0,0 +1,410 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// Overview of incremental compilation:
+// Generated syscall depends only on declarations it transitively refers to, consts used in these
+// declarations, and on other variants of the same syscall (they affect number and sizes of arguments,
+// see collectCallArgSizes). CompileIncremental hashes declarations and consts for each syscall,
+// and compiles only syscalls with changed hashes together with declarations they depend on.
+// Other syscalls are taken from the previous compilation result. Argument sizes contributed by each
+// syscall are saved, so that final argument sizes can be computed without compiling all variants;
+// if they change for a reused syscall, the syscall is recompiled as well. Then all syscalls are merged
+// and their types are deduplicated in the same way Compile does it, so the result is the same.
+
+// CallDeps describes what compilation of a syscall depends on.
+type CallDeps struct {
+	// Hash of all declarations and consts the syscall depends on.
+	Hash string
+	// Resources used by the syscall (only if the syscall is supported).
+	Resources []string
+	// Unsupported declarations the syscall depends on (see Prog.Unsupported).
+	Unsupported []string
+	// Sizes of plain int arguments of the syscall (see intArgSizes).
+	ArgSizes []uint64
+	// Final argument sizes of the syscall with all its variants taken into account.
+	CallArgSizes []uint64
+}
+
+// CompileIncremental is like Compile, but reuses the result of the previous compilation prev
+// (returned by CompileIncremental for the same target) for syscalls that don't depend on any changed
+// declarations or consts. Only the remaining syscalls and declarations they depend on are checked and generated.
+// If prev is nil, the whole description is compiled, but the result can be used for future incremental
+// compilation. If prev is not nil, the whole description is still checked for errors in the same way
+// Compile does it, but warnings are produced only for the recompiled declarations.
+// prev is reused for the result, so it must not be used after the call.
+func CompileIncremental(desc *ast.Description, consts map[string]uint64, target *targets.Target,
+	prev *Prog, eh ast.ErrorHandler) *Prog {
+	if prev != nil && !checkAll(desc, consts, target, eh) {
+		return nil
+	}
+	forced := make(map[string]bool)
+	for {
+		prg, changed := compileIncremental(desc, consts, target, prev, forced, eh)
+		if len(changed) == 0 {
+			return prg
+		}
+		// Changes in some syscalls affected argument sizes of their unchanged variants
+		// (see collectCallArgSizes), so these variants need to be recompiled as well.
+		for _, name := range changed {
+			forced[name] = true
+		}
+	}
+}
+
+// checkAll does all checks that Compile does on the whole description. Some of them (e.g. for unused
+// declarations) can't be done on the subset of declarations that is recompiled.
+func checkAll(desc *ast.Description, consts map[string]uint64, target *targets.Target, eh ast.ErrorHandler) bool {
+	comp := createCompiler(desc.Clone(), target, eh)
+	comp.filterArch()
+	comp.typecheck()
+	comp.flattenFlags()
+	if comp.errors != 0 {
+		return false
+	}
+	if comp.target.SyscallNumbers {
+		comp.assignSyscallNumbers(consts)
+	}
+	comp.patchConsts(consts)
+	comp.check(consts)
+	return comp.errors == 0
+}
+
+// compileIncremental does one round of CompileIncremental that additionally recompiles forced syscalls.
+// If argument sizes of any reused syscalls have changed, it returns names of these syscalls.
+func compileIncremental(desc *ast.Description, consts map[string]uint64, target *targets.Target,
+	prev *Prog, forced map[string]bool, eh ast.ErrorHandler) (*Prog, []string) {
+	comp := createCompiler(desc.Clone(), target, eh)
+	comp.filterArch()
+	graph := comp.buildDepGraph()
+	hashes := make(map[*ast.Call]string)
+	typedefs := make(map[*ast.Call][]string)
+	affected := make(map[ast.Node]bool)
+	for _, call := range graph.calls {
+		deps := graph.closure(call)
+		hashes[call] = graph.hash(deps, consts)
+		for n := range deps {
+			if typedef, ok := n.(*ast.TypeDef); ok {
+				typedefs[call] = append(typedefs[call], typedef.Name.Name)
+			}
+		}
+		name := call.Name.Name
+		if prevDeps := prev.callDeps(name); forced[name] || prevDeps == nil || prevDeps.Hash != hashes[call] {
+			for n := range deps {
+				affected[n] = true
+			}
+		}
+	}
+	if prev != nil {
+		comp.partial = true
+		comp.desc.Nodes = graph.filter(comp.desc.Nodes, affected)
+	}
+	comp.typecheck()
+	comp.flattenFlags()
+	if comp.errors != 0 {
+		return nil, nil
+	}
+	if comp.target.SyscallNumbers {
+		comp.assignSyscallNumbers(consts)
+	}
+	comp.patchConsts(consts)
+	comp.check(consts)
+	if comp.errors != 0 {
+		return nil, nil
+	}
+
+	// Argument sizes depend on all variants of the syscall, so we replay collectCallArgSizes
+	// for all syscalls using saved argument sizes of the syscalls that are not recompiled.
+	argSizes := make(map[*ast.Call][]uint64)
+	for _, decl := range comp.desc.Nodes {
+		if call, ok := decl.(*ast.Call); ok {
+			argSizes[call] = comp.intArgSizes(call)
+		}
+	}
+	argPos := make(map[string]ast.Pos)
+	callArgSizes := make(map[string][]uint64)
+	for _, call := range graph.calls {
+		sizes, ok := argSizes[call]
+		if !ok {
+			sizes = prev.Calls[call.Name.Name].ArgSizes
+		}
+		comp.addCallArgSizes(callArgSizes, argPos, call, sizes)
+	}
+	if comp.errors != 0 {
+		return nil, nil
+	}
+	var changed []string
+	for _, call := range graph.calls {
+		if _, ok := argSizes[call]; ok {
+			continue
+		}
+		name := call.Name.Name
+		if !slices.Equal(prev.Calls[name].CallArgSizes, callArgSizes[comp.getCallName(call)]) {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) != 0 {
+		return nil, changed
+	}
+	syscalls := comp.genSyscalls(callArgSizes)
+	comp.layoutTypes(syscalls)
+
+	calls := make(map[string]*CallDeps)
+	for call, sizes := range argSizes {
+		deps := comp.callDeps(call, hashes[call], typedefs[call])
+		deps.ArgSizes = sizes
+		deps.CallArgSizes = slices.Clone(callArgSizes[comp.getCallName(call)])
+		calls[call.Name.Name] = deps
+	}
+	var reused []*prog.Syscall
+	if prev != nil {
+		prevSyscalls := make(map[string]*prog.Syscall)
+		for _, c := range prev.Syscalls {
+			prevSyscalls[c.Name] = c
+		}
+		for _, call := range graph.calls {
+			name := call.Name.Name
+			if calls[name] != nil {
+				continue
+			}
+			calls[name] = prev.Calls[name]
+			if c := prevSyscalls[name]; c != nil {
+				reused = append(reused, c)
+			}
+		}
+		restoreTypes(reused, prev.Types)
+	}
+	syscalls = append(syscalls, reused...)
+	sort.Slice(syscalls, func(i, j int) bool {
+		return syscalls[i].Name < syscalls[j].Name
+	})
+	types := comp.generateTypes(syscalls)
+
+	unsupported := comp.unsupported
+	resources := make(map[string]*prog.ResourceDesc)
+	for _, deps := range calls {
+		for _, what := range deps.Unsupported {
+			unsupported[what] = true
+		}
+		for _, name := range deps.Resources {
+			resources[name] = nil
+		}
+	}
+	if prev != nil {
+		for _, res := range prev.Resources {
+			if _, ok := resources[res.Name]; ok {
+				resources[res.Name] = res
+			}
+		}
+	}
+	var resourceList []*prog.ResourceDesc
+	for name, res := range resources {
+		// Resources that are present in the compiled declarations are generated anew.
+		if n := comp.resources[name]; n != nil {
+			res = comp.genResource(n)
+		}
+		resourceList = append(resourceList, res)
+	}
+	sort.Slice(resourceList, func(i, j int) bool {
+		return resourceList[i].Name < resourceList[j].Name
+	})
+	prg := &Prog{
+		Resources:   resourceList,
+		Syscalls:    syscalls,
+		Types:       types,
+		Unsupported: unsupported,
+		Calls:       calls,
+	}
+	if comp.errors != 0 {
+		return nil, nil
+	}
+	for _, w := range comp.warnings {
+		eh(w.pos, w.msg)
+	}
+	return prg, nil
+}
+
+func (prg *Prog) callDeps(name string) *CallDeps {
+	if prg == nil {
+		return nil
+	}
+	return prg.Calls[name]
+}
+
+// callDeps returns dependencies of the call, must be called after check.
+func (comp *compiler) callDeps(n *ast.Call, hash string, typedefs []string) *CallDeps {
+	structs, flags, strflags := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, arg := range n.Args {
+		comp.collectUsedType(structs, flags, strflags, arg.Type, true)
+	}
+	if n.Ret != nil {
+		comp.collectUsedType(structs, flags, strflags, n.Ret, true)
+	}
+	deps := &CallDeps{
+		Hash: hash,
+	}
+	related := map[string]bool{
+		"syscall " + n.Name.Name: true,
+		"syscall " + n.CallName:  true,
+	}
+	for name := range structs {
+		if res := comp.resources[name]; res != nil {
+			_, typ, _ := res.Info()
+			related[typ+" "+name] = true
+			if n.NR != ^uint64(0) {
+				deps.Resources = append(deps.Resources, name)
+			}
+		} else if str := comp.structs[name]; str != nil {
+			_, typ, _ := str.Info()
+			related[typ+" "+name] = true
+		}
+	}
+	for _, name := range typedefs {
+		related["type "+name] = true
+	}
+	for what := range related {
+		if comp.missingConsts[what] {
+			deps.Unsupported = append(deps.Unsupported, what)
+		}
+	}
+	sort.Strings(deps.Resources)
+	sort.Strings(deps.Unsupported)
+	return deps
+}
+
+// restoreTypes replaces Ref's produced by generateTypes in syscalls with the corresponding types.
+func restoreTypes(syscalls []*prog.Syscall, types []prog.Type) {
+	prog.ForeachType(syscalls, func(typ prog.Type, ctx *prog.TypeCtx) {
+		if ref, ok := typ.(prog.Ref); ok {
+			*ctx.Ptr = types[ref]
+		}
+	})
+}
+
+// depGraph represents dependencies between declarations.
+// Names are not resolved, so a declaration depends on all declarations with the names it refers to.
+type depGraph struct {
+	comp  *compiler
+	calls []*ast.Call
+	// Declarations of types and flags by name.
+	decls  map[string][]ast.Node
+	deps   map[ast.Node][]string
+	hashes map[ast.Node]string
+	metas  map[string]string
+}
+
+func (comp *compiler) buildDepGraph() *depGraph {
+	graph := &depGraph{
+		comp:   comp,
+		decls:  make(map[string][]ast.Node),
+		deps:   make(map[ast.Node][]string),
+		hashes: make(map[ast.Node]string),
+		metas:  make(map[string]string),
+	}
+	for _, decl := range comp.desc.Nodes {
+		pos, _, name := decl.Info()
+		switch n := decl.(type) {
+		case *ast.Meta:
+			// File metadata affects compilation of all declarations in the file.
+			graph.metas[filepath.Base(pos.File)] += ast.SerializeNode(n)
+			continue
+		case *ast.Call:
+			graph.calls = append(graph.calls, n)
+		case *ast.Resource, *ast.Struct, *ast.TypeDef, *ast.IntFlags, *ast.StrFlags:
+			graph.decls[name] = append(graph.decls[name], decl)
+		default:
+			continue
+		}
+		graph.deps[decl] = ast.Deps(decl)
+	}
+	return graph
+}
+
+// closure returns all declarations the call depends on (including the call itself).
+func (graph *depGraph) closure(call *ast.Call) map[ast.Node]bool {
+	res := map[ast.Node]bool{call: true}
+	queue := []ast.Node{call}
+	add := func(n ast.Node) {
+		if !res[n] {
+			res[n] = true
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) != 0 {
+		n := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, name := range graph.deps[n] {
+			for _, decl := range graph.decls[name] {
+				add(decl)
+			}
+		}
+	}
+	return res
+}
+
+// hash returns hash of the declarations and of values of all consts they may refer to.
+func (graph *depGraph) hash(decls map[ast.Node]bool, consts map[string]uint64) string {
+	var hashes []string
+	for n := range decls {
+		hashes = append(hashes, graph.declHash(n, consts))
+	}
+	sort.Strings(hashes)
+	h := sha256.New()
+	for _, hash := range hashes {
+		h.Write([]byte(hash))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (graph *depGraph) declHash(n ast.Node, consts map[string]uint64) string {
+	if hash, ok := graph.hashes[n]; ok {
+		return hash
+	}
+	// Line numbers don't affect compilation results, but file names do (see fileMeta).
+	pos, _, _ := n.Info()
+	file := filepath.Base(pos.File)
+	h := sha256.New()
+	fmt.Fprintf(h, "%v\n%v\n%v\n", file, graph.metas[file], ast.SerializeNode(n))
+	names := graph.deps[n]
+	if c, ok := n.(*ast.Call); ok {
+		names = append([]string{graph.comp.target.SyscallPrefix + c.CallName}, names...)
+	}
+	for _, name := range names {
+		if val, ok := consts[name]; ok {
+			fmt.Fprintf(h, "%v=%v\n", name, val)
+		}
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+	graph.hashes[n] = hash
+	return hash
+}
+
+// filter returns nodes without declarations that are not affected.
+// Builtin types are always preserved since the compiler may refer to them implicitly.
+func (graph *depGraph) filter(nodes []ast.Node, affected map[ast.Node]bool) []ast.Node {
+	var res []ast.Node
+	for _, n := range nodes {
+		if _, isDecl := graph.deps[n]; isDecl && !affected[n] {
+			_, isCall := n.(*ast.Call)
+			if pos, _, _ := n.Info(); isCall || !pos.Builtin() {
+				continue
+			}
+		}
+		res = append(res, n)
+	}
+	return res
+}
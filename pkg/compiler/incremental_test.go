@@ -0,0 +1,191 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/generated"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileIncremental(t *testing.T) {
+	type edit struct {
+		file     string
+		old      string
+		new      string
+		constant string
+		value    uint64
+		// Syscalls that must be recompiled (changed) and reused from the previous compilation (same).
+		changed []string
+		same    []string
+	}
+	edits := []edit{
+		{
+			// Nothing has changed.
+			same: []string{"test$int", "test$align0", "test$csum_ipv4_tcp"},
+		},
+		{
+			file:    "test.txt",
+			old:     "syz_align0 {\n\tf0\tint16",
+			new:     "syz_align0 {\n\tf0\tint32",
+			changed: []string{"test$align0"},
+			same:    []string{"test$int", "test$align1", "test$csum_ipv4_tcp"},
+		},
+		{
+			constant: "IPPROTO_TCP",
+			value:    100,
+			changed:  []string{"test$csum_ipv4_tcp"},
+			same:     []string{"test$int", "test$align0"},
+		},
+		{
+			// A new variant changes number and sizes of arguments of other variants of the same syscall.
+			file:    "exec.txt",
+			old:     "syz_compare_int$4(",
+			new:     "syz_compare_int$5(n int8, v0 intptr, v1 intptr, v2 intptr, v3 intptr, v4 intptr)\nsyz_compare_int$4(",
+			changed: []string{"syz_compare_int$5", "syz_compare_int$2"},
+			same:    []string{"test$int", "test$align0", "test$csum_ipv4_tcp"},
+		},
+		{
+			file:    "exec.txt",
+			old:     "syz_compare_int$5(n int8, v0 intptr, v1 intptr, v2 intptr, v3 intptr, v4 intptr)\n",
+			new:     "",
+			changed: []string{"syz_compare_int$2"},
+			same:    []string{"test$int", "test$align0", "test$csum_ipv4_tcp"},
+		},
+	}
+	for _, arch := range []string{targets.TestArch32, targets.TestArch64} {
+		t.Run(arch, func(t *testing.T) {
+			t.Parallel()
+			target, contents, parse, consts, eh := loadIncrementalTest(t, arch)
+			prev := CompileIncremental(parse(), consts, target, nil, eh)
+			require.NotNil(t, prev)
+			for i, edit := range edits {
+				if edit.file != "" {
+					require.Contains(t, contents[edit.file], edit.old)
+					contents[edit.file] = strings.Replace(contents[edit.file], edit.old, edit.new, 1)
+				}
+				if edit.constant != "" {
+					consts[edit.constant] = edit.value
+				}
+				prevSyscalls := syscallMap(prev)
+				desc := parse()
+				full := Compile(desc, consts, target, eh)
+				require.NotNil(t, full)
+				inc := CompileIncremental(desc, consts, target, prev, eh)
+				require.NotNil(t, inc)
+				assert.Equal(t, serializeProg(t, full), serializeProg(t, inc), "edit #%v", i)
+				assert.Equal(t, full.Unsupported, inc.Unsupported, "edit #%v", i)
+				incSyscalls := syscallMap(inc)
+				for _, name := range edit.changed {
+					require.NotNil(t, incSyscalls[name], "edit #%v: %v", i, name)
+					assert.NotSame(t, prevSyscalls[name], incSyscalls[name], "edit #%v: %v", i, name)
+				}
+				for _, name := range edit.same {
+					require.NotNil(t, incSyscalls[name], "edit #%v: %v", i, name)
+					assert.Same(t, prevSyscalls[name], incSyscalls[name], "edit #%v: %v", i, name)
+				}
+				prev = inc
+			}
+		})
+	}
+}
+
+// Errors that can be detected only given all declarations must be reported as well.
+func TestCompileIncrementalErrors(t *testing.T) {
+	tests := []struct {
+		decls string
+		err   string
+	}{
+		{
+			decls: "syz_incremental_unused {\n\tf0\tint32\n}\n",
+			err:   "unused struct syz_incremental_unused",
+		},
+		{
+			decls: "resource syz_incremental_res[int32]\ntest$incremental_res(a syz_incremental_res)\n",
+			err:   "resource syz_incremental_res can't be created",
+		},
+	}
+	target, contents, parse, consts, eh := loadIncrementalTest(t, targets.TestArch64)
+	for _, test := range tests {
+		t.Run(test.err, func(t *testing.T) {
+			prev := CompileIncremental(parse(), consts, target, nil, eh)
+			require.NotNil(t, prev)
+			contents["incremental.txt"] = test.decls
+			defer delete(contents, "incremental.txt")
+			desc := parse()
+			var fullErrors, incErrors []string
+			assert.Nil(t, Compile(desc, consts, target, func(pos ast.Pos, msg string) {
+				fullErrors = append(fullErrors, msg)
+			}))
+			assert.Nil(t, CompileIncremental(desc, consts, target, prev, func(pos ast.Pos, msg string) {
+				incErrors = append(incErrors, msg)
+			}))
+			assert.Equal(t, fullErrors, incErrors)
+			assert.True(t, slices.ContainsFunc(incErrors, func(msg string) bool {
+				return strings.HasPrefix(msg, test.err)
+			}), "errors: %q", incErrors)
+		})
+	}
+}
+
+// loadIncrementalTest returns contents of the test description files that can be changed
+// before parsing them with the returned function, and consts for the descriptions.
+func loadIncrementalTest(t *testing.T, arch string) (*targets.Target, map[string]string,
+	func() *ast.Description, map[string]uint64, ast.ErrorHandler) {
+	path := filepath.Join("..", "..", "sys", targets.TestOS)
+	files, err := filepath.Glob(filepath.Join(path, "*.txt"))
+	require.NoError(t, err)
+	target := targets.List[targets.TestOS][arch]
+	eh := func(pos ast.Pos, msg string) {}
+	contents := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		contents[filepath.Base(file)] = string(data)
+	}
+	parse := func() *ast.Description {
+		var names []string
+		for name := range contents {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		desc := new(ast.Description)
+		for _, name := range names {
+			desc.Nodes = append(desc.Nodes, ast.Parse([]byte(contents[name]), name, eh).Nodes...)
+		}
+		return desc
+	}
+	constFile := DeserializeConstFile(filepath.Join(path, "*.const"), eh)
+	require.NotNil(t, constFile)
+	FabricateSyscallConsts(target, ExtractConsts(parse(), target, eh), constFile)
+	return target, contents, parse, constFile.Arch(arch), eh
+}
+
+func syscallMap(prg *Prog) map[string]*prog.Syscall {
+	res := make(map[string]*prog.Syscall)
+	for _, c := range prg.Syscalls {
+		res[c.Name] = c
+	}
+	return res
+}
+
+func serializeProg(t *testing.T, prg *Prog) []byte {
+	data, err := generated.Serialize(&generated.Desc{
+		Syscalls:  prg.Syscalls,
+		Resources: prg.Resources,
+		Types:     prg.Types,
+	})
+	require.NoError(t, err)
+	return data
+}
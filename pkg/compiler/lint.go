@@ -0,0 +1,406 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// LintCheck is a single lint rule. Lint rules detect descriptions that are valid,
+// but most likely don't match what the kernel does (as opposed to compilation errors).
+type LintCheck struct {
+	Name        string
+	Description string
+	fn          func(l *linter)
+}
+
+// LintIssue is a single problem found by a lint check.
+type LintIssue struct {
+	Pos   ast.Pos
+	Check string
+	Msg   string
+	// Fix is the suggested replacement for the type at Pos, if the check knows how to fix the problem.
+	Fix string
+}
+
+func (issue *LintIssue) String() string {
+	res := fmt.Sprintf("%v: %v (%v)", issue.Pos, issue.Msg, issue.Check)
+	if issue.Fix != "" {
+		res += fmt.Sprintf(", suggested fix: %v", issue.Fix)
+	}
+	return res
+}
+
+var LintChecks = []*LintCheck{
+	{
+		Name:        "len-target",
+		Description: "len field name suggests a different sibling target (e.g. buf_len len[data])",
+		fn:          (*linter).checkLenTargets,
+	},
+	{
+		Name:        "inout-resource",
+		Description: "resource is produced only by inout arguments/fields (never by the kernel alone)",
+		fn:          (*linter).checkInoutResources,
+	},
+	{
+		Name:        "flags-overlap",
+		Description: "flags set contains duplicate values or values that partially overlap other bits",
+		fn:          (*linter).checkFlagsOverlap,
+	},
+	{
+		Name:        "ioctl-dir",
+		Description: "ioctl argument direction contradicts the direction encoded in the command (_IOR/_IOW)",
+		fn:          (*linter).checkIoctlDir,
+	},
+}
+
+// Lint compiles the descriptions and runs the lint checks on the result.
+// If checks is empty, all checks are run, otherwise only the named ones.
+// Compilation errors are reported via eh and lead to an error.
+func Lint(desc *ast.Description, consts map[string]uint64, target *targets.Target, checks []string,
+	eh ast.ErrorHandler) ([]*LintIssue, error) {
+	enabled, err := enabledLintChecks(checks)
+	if err != nil {
+		return nil, err
+	}
+	comp := createCompiler(desc.Clone(), target, eh)
+	comp.filterArch()
+	comp.typecheck()
+	comp.flattenFlags()
+	if comp.errors != 0 {
+		return nil, errors.New("typecheck failed")
+	}
+	if comp.target.SyscallNumbers {
+		comp.assignSyscallNumbers(consts)
+	}
+	comp.patchConsts(consts)
+	comp.check(consts)
+	if comp.errors != 0 {
+		return nil, errors.New("compilation failed")
+	}
+	l := &linter{
+		comp: comp,
+		orig: make(map[ast.Pos]*ast.Type),
+	}
+	// Consts in the compiled tree are already replaced with values,
+	// so fixes are produced from the original types to preserve const names.
+	for _, n := range desc.Nodes {
+		ast.Recursive(func(n ast.Node) bool {
+			if t, ok := n.(*ast.Type); ok && l.orig[t.Pos] == nil {
+				l.orig[t.Pos] = t
+			}
+			return true
+		})(n)
+	}
+	for _, check := range enabled {
+		l.check = check
+		check.fn(l)
+	}
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i].Pos, l.issues[j].Pos
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	return l.issues, nil
+}
+
+func enabledLintChecks(names []string) ([]*LintCheck, error) {
+	if len(names) == 0 {
+		return LintChecks, nil
+	}
+	var res []*LintCheck
+	for _, name := range names {
+		var check *LintCheck
+		for _, c := range LintChecks {
+			if c.Name == name {
+				check = c
+			}
+		}
+		if check == nil {
+			return nil, fmt.Errorf("unknown lint check %q", name)
+		}
+		res = append(res, check)
+	}
+	return res, nil
+}
+
+type linter struct {
+	comp   *compiler
+	orig   map[ast.Pos]*ast.Type
+	check  *LintCheck
+	issues []*LintIssue
+}
+
+func (l *linter) report(pos ast.Pos, fix *ast.Type, msg string, args ...interface{}) {
+	if pos.Builtin() {
+		return
+	}
+	issue := &LintIssue{
+		Pos:   pos,
+		Check: l.check.Name,
+		Msg:   fmt.Sprintf(msg, args...),
+	}
+	if fix != nil {
+		issue.Fix = ast.SerializeNode(fix)
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// fixType returns a copy of the original (not const-patched) type t for modification,
+// or nil if the original type is not known (e.g. it was produced by template instantiation).
+func (l *linter) fixType(t *ast.Type) *ast.Type {
+	orig := l.orig[t.Pos]
+	if orig == nil || orig.Ident != t.Ident || len(orig.Args) != len(t.Args) {
+		return nil
+	}
+	return orig.Clone().(*ast.Type)
+}
+
+func (l *linter) checkLenTargets() {
+	for _, decl := range l.comp.desc.Nodes {
+		switch n := decl.(type) {
+		case *ast.Call:
+			l.checkLenTargetFields(n.Args)
+		case *ast.Struct:
+			if !n.IsUnion {
+				l.checkLenTargetFields(n.Fields)
+			}
+		}
+	}
+}
+
+func (l *linter) checkLenTargetFields(fields []*ast.Field) {
+	names := make(map[string]bool)
+	for _, f := range fields {
+		names[f.Name.Name] = true
+	}
+	for _, f := range fields {
+		t := f.Type
+		if l.comp.getTypeDesc(t) != typeLen || t.Ident == "offsetof" || len(t.Args[0].Colon) != 0 {
+			continue
+		}
+		target := t.Args[0].Ident
+		if !names[target] {
+			// References to parent structs, syscall args, etc.
+			continue
+		}
+		var candidates []string
+		for _, name := range lenTargetNames(f.Name.Name) {
+			if names[name] && name != f.Name.Name {
+				candidates = append(candidates, name)
+			}
+		}
+		if len(candidates) != 1 || candidates[0] == target {
+			continue
+		}
+		fix := l.fixType(t)
+		if fix != nil {
+			fix.Args[0].Ident = candidates[0]
+		}
+		l.report(t.Pos, fix, "%v %v refers to %v, but the name suggests %v",
+			t.Ident, f.Name.Name, target, candidates[0])
+	}
+}
+
+// lenTargetNames returns possible names of the field the len field name refers to
+// (e.g. buf for buf_len, bufsize and nbuf).
+func lenTargetNames(name string) []string {
+	var stems []string
+	for _, suffix := range []string{"length", "len", "size", "count", "cnt", "num"} {
+		if stem := strings.TrimSuffix(name, suffix); stem != name {
+			stems = append(stems, strings.TrimSuffix(stem, "_"))
+		}
+	}
+	for _, prefix := range []string{"num_", "nr_", "n_"} {
+		if stem := strings.TrimPrefix(name, prefix); stem != name {
+			stems = append(stems, stem)
+		}
+	}
+	var res []string
+	for _, stem := range stems {
+		if stem != "" {
+			res = append(res, stem, stem+"s")
+		}
+	}
+	return res
+}
+
+func (l *linter) checkInoutResources() {
+	outs := make(map[string]bool)
+	checked := make(map[structDir]bool)
+	for _, decl := range l.comp.desc.Nodes {
+		if n, ok := decl.(*ast.Call); ok {
+			for _, arg := range n.Args {
+				l.collectOutputs(arg.Type, prog.DirIn, true, true, outs, checked)
+			}
+			if n.Ret != nil {
+				l.collectOutputs(n.Ret, prog.DirOut, true, true, outs, checked)
+			}
+		}
+	}
+	for _, decl := range l.comp.desc.Nodes {
+		if n, ok := decl.(*ast.Resource); ok && l.comp.used[n.Name.Name] && !outs[n.Name.Name] {
+			l.report(n.Pos, nil, "resource %v is produced only by inout arguments/fields"+
+				" (the kernel never returns it without getting it from user-space first)", n.Name.Name)
+		}
+	}
+}
+
+// collectOutputs is similar to checkTypeCtors, but considers only strictly out directions.
+func (l *linter) collectOutputs(t *ast.Type, dir prog.Dir, isArg, canCreate bool,
+	outs map[string]bool, checked map[structDir]bool) {
+	comp := l.comp
+	desc, args, base := comp.getArgsBase(t, isArg)
+	if base.IsOptional {
+		canCreate = false
+	}
+	switch desc {
+	case typeResource:
+		if canCreate && dir == prog.DirOut {
+			for r := comp.resources[t.Ident]; r != nil && !outs[r.Name.Name]; r = comp.resources[r.Base.Ident] {
+				outs[r.Name.Name] = true
+			}
+		}
+		return
+	case typeStruct:
+		s := comp.structs[t.Ident]
+		if s.IsUnion {
+			canCreate = false
+		}
+		key := structDir{s.Name.Name, dir}
+		if checked[key] {
+			return
+		}
+		checked[key] = true
+		for _, fld := range s.Fields {
+			fldDir, fldHasDir := comp.genFieldDir(comp.parseIntAttrs(structFieldAttrs, fld, fld.Attrs))
+			if !fldHasDir {
+				fldDir = dir
+			}
+			l.collectOutputs(fld.Type, fldDir, false, canCreate, outs, checked)
+		}
+		return
+	case typePtr:
+		dir = genDir(t.Args[0])
+	}
+	for i, arg := range args {
+		if desc.Args[i].Type == typeArgType {
+			l.collectOutputs(arg, dir, desc.Args[i].IsArg, canCreate, outs, checked)
+		}
+	}
+}
+
+func (l *linter) checkFlagsOverlap() {
+	for _, decl := range l.comp.desc.Nodes {
+		n, ok := decl.(*ast.IntFlags)
+		if !ok {
+			continue
+		}
+		names := make(map[uint64]string)
+		var bitmask, single uint64
+		nonzero := 0
+		for _, v := range n.Values {
+			name := lintIntName(v)
+			if prev, ok := names[v.Value]; ok {
+				l.report(v.Pos, nil, "flags %v: %v has the same value %#x as %v",
+					n.Name.Name, name, v.Value, prev)
+				continue
+			}
+			names[v.Value] = name
+			if v.Value != 0 {
+				nonzero++
+			}
+			if bits.OnesCount64(v.Value) == 1 {
+				single++
+				bitmask |= v.Value
+			}
+		}
+		// Only sets that are mostly single bits are bitmasks,
+		// for enums values are expected to overlap.
+		if single < 2 || int(single)*2 < nonzero {
+			continue
+		}
+		for _, v := range n.Values {
+			if bits.OnesCount64(v.Value) < 2 || v.Value&bitmask == 0 || v.Value&^bitmask == 0 {
+				continue
+			}
+			overlap := v.Value & bitmask & -(v.Value & bitmask)
+			l.report(v.Pos, nil, "flags %v: %v (%#x) partially overlaps %v (%#x)",
+				n.Name.Name, lintIntName(v), v.Value, names[overlap], overlap)
+		}
+	}
+}
+
+func lintIntName(v *ast.Int) string {
+	if v.Ident != "" {
+		return v.Ident
+	}
+	return ast.FormatInt(v.Value, v.ValueFmt)
+}
+
+func (l *linter) checkIoctlDir() {
+	for _, decl := range l.comp.desc.Nodes {
+		n, ok := decl.(*ast.Call)
+		if !ok || n.CallName != "ioctl" || len(n.Args) < 3 {
+			continue
+		}
+		cmd, arg := n.Args[1].Type, n.Args[2].Type
+		if cmd.Ident != "const" || cmd.Args[0].Ident != "" || l.comp.getTypeDesc(arg) != typePtr {
+			continue
+		}
+		in, out, ok := ioctlDir(l.comp.target, cmd.Args[0].Value)
+		if !ok || in == out {
+			continue
+		}
+		want, bad := prog.DirIn, prog.DirOut
+		if out {
+			want, bad = prog.DirOut, prog.DirIn
+		}
+		if genDir(arg.Args[0]) != bad {
+			continue
+		}
+		fix := l.fixType(arg)
+		if fix != nil {
+			fix.Args[0].Ident = want.String()
+		}
+		l.report(arg.Pos, fix, "%v: argument direction is %v, but the command encodes %v",
+			n.Name.Name, bad, want)
+	}
+}
+
+// ioctlDir decodes data direction encoded in the ioctl command by _IOR/_IOW/_IOWR macros.
+// in means that the kernel reads the argument, out means that the kernel writes it.
+func ioctlDir(target *targets.Target, cmd uint64) (in, out, ok bool) {
+	if cmd>>32 != 0 {
+		return false, false, false
+	}
+	switch target.OS {
+	case targets.Linux, targets.TestOS:
+		switch target.Arch {
+		case targets.MIPS64LE, targets.PPC64LE:
+			dir := cmd >> 29 & 7
+			return dir&4 != 0, dir&2 != 0, dir&^1 != 0
+		default:
+			dir := cmd >> 30 & 3
+			return dir&1 != 0, dir&2 != 0, dir != 0
+		}
+	case targets.FreeBSD, targets.NetBSD, targets.OpenBSD, targets.Darwin:
+		dir := cmd >> 29 & 7
+		return dir&4 != 0, dir&2 != 0, dir&^1 != 0
+	}
+	return false, false, false
+}
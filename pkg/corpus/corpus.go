@@ -32,6 +32,7 @@ type Corpus struct {
 	StatCover  *stat.Val
 
 	focusAreas []*focusAreaState
+	autoAreas  []*focusAreaState
 }
 
 type focusAreaState struct {
@@ -190,24 +191,96 @@ func (corpus *Corpus) Save(inp NewInput) {
 
 func (corpus *Corpus) applyFocusAreas(item *Item, coverDelta []uint64) {
 	for _, area := range corpus.focusAreas {
-		matches := false
-		for _, pc := range coverDelta {
-			if _, ok := area.CoverPCs[pc]; ok {
-				matches = true
-				break
+		corpus.applyFocusArea(area, item, coverDelta)
+	}
+	for _, area := range corpus.autoAreas {
+		corpus.applyFocusArea(area, item, coverDelta)
+	}
+}
+
+func (corpus *Corpus) applyFocusArea(area *focusAreaState, item *Item, coverDelta []uint64) {
+	if _, ok := item.areas[area]; ok {
+		return
+	}
+	matches := false
+	for _, pc := range coverDelta {
+		if _, ok := area.CoverPCs[pc]; ok {
+			matches = true
+			break
+		}
+	}
+	if !matches {
+		return
+	}
+	area.saveProgram(item.Prog, item.Signal)
+	if item.areas == nil {
+		item.areas = make(map[*focusAreaState]struct{})
+	}
+	item.areas[area] = struct{}{}
+}
+
+// SetAutoFocusAreas replaces the set of automatically managed focus areas.
+// Unlike the areas passed to NewFocusedCorpus, these can be changed at any time.
+// Areas are identified by name: if an area with the same name already exists,
+// only its weight is updated, new areas are populated from the existing corpus programs.
+// If there are no other focus areas, the rest of the corpus is chosen with weight 1.0.
+func (corpus *Corpus) SetAutoFocusAreas(areas []FocusArea) {
+	corpus.mu.Lock()
+	defer corpus.mu.Unlock()
+	old := make(map[string]*focusAreaState)
+	for _, area := range corpus.autoAreas {
+		old[area.Name] = area
+	}
+	var added []*focusAreaState
+	corpus.autoAreas = nil
+	for _, area := range areas {
+		state := old[area.Name]
+		if state != nil {
+			state.Weight = area.Weight
+			delete(old, area.Name)
+		} else {
+			state = &focusAreaState{
+				FocusArea:    area,
+				ProgramsList: &ProgramsList{},
 			}
+			added = append(added, state)
 		}
-		if !matches {
-			continue
+		corpus.autoAreas = append(corpus.autoAreas, state)
+	}
+	if len(old) == 0 && len(added) == 0 {
+		return
+	}
+	for _, item := range corpus.progsMap {
+		for _, area := range old {
+			delete(item.areas, area)
 		}
-		area.saveProgram(item.Prog, item.Signal)
-		if item.areas == nil {
-			item.areas = make(map[*focusAreaState]struct{})
-			item.areas[area] = struct{}{}
+		for _, area := range added {
+			corpus.applyFocusArea(area, item, item.Cover)
 		}
 	}
 }
 
+// AutoFocusAreas returns the current automatically managed focus areas
+// along with the number of corpus programs in each of them.
+func (corpus *Corpus) AutoFocusAreas() ([]FocusArea, []int) {
+	corpus.mu.RLock()
+	defer corpus.mu.RUnlock()
+	var areas []FocusArea
+	var progs []int
+	for _, area := range corpus.autoAreas {
+		areas = append(areas, area.FocusArea)
+		progs = append(progs, len(area.progs))
+	}
+	return areas, progs
+}
+
+// Cover returns the total coverage of all corpus items.
+func (corpus *Corpus) Cover() []uint64 {
+	corpus.mu.RLock()
+	defer corpus.mu.RUnlock()
+	return corpus.cover.Serialize()
+}
+
 func (corpus *Corpus) Signal() signal.Signal {
 	corpus.mu.RLock()
 	defer corpus.mu.RUnlock()
@@ -44,6 +44,9 @@ func (corpus *Corpus) Minimize(cover bool) {
 	for _, area := range corpus.focusAreas {
 		area.ProgramsList = &ProgramsList{}
 	}
+	for _, area := range corpus.autoAreas {
+		area.ProgramsList = &ProgramsList{}
+	}
 	for _, ctx := range signal.Minimize(inputs) {
 		inp := ctx.(*Item)
 		corpus.progsMap[inp.Sig] = inp
@@ -47,10 +47,19 @@ func (corpus *Corpus) ChooseProgram(r *rand.Rand) *prog.Prog {
 	// We could have used an approach similar to chooseProgram(), but for small number
 	// of focus areas that is an overkill.
 	var randArea *focusAreaState
-	if len(corpus.focusAreas) > 0 {
+	if len(corpus.focusAreas)+len(corpus.autoAreas) > 0 {
+		areas := corpus.focusAreas
+		if len(areas) == 0 {
+			// Automatic areas only complement the rest of the corpus.
+			areas = []*focusAreaState{{
+				FocusArea:    FocusArea{Weight: 1.0},
+				ProgramsList: corpus.ProgramsList,
+			}}
+		}
+		areas = append(areas[:len(areas):len(areas)], corpus.autoAreas...)
 		sum := 0.0
-		nonEmpty := make([]*focusAreaState, 0, len(corpus.focusAreas))
-		for _, area := range corpus.focusAreas {
+		nonEmpty := make([]*focusAreaState, 0, len(areas))
+		for _, area := range areas {
 			if len(area.progs) == 0 {
 				continue
 			}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"math"
 	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/google/syzkaller/prog"
@@ -117,3 +118,65 @@ func TestFocusAreas(t *testing.T) {
 	assert.InDelta(t, secondCount, TOTAL*0.3, TOTAL/25)
 	assert.InDelta(t, thirdCount, TOTAL*0.6, TOTAL/25)
 }
+
+func TestAutoFocusAreas(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewCorpus(context.Background())
+	rs := rand.NewSource(0)
+	fillGroup := func(from, to, count int) map[*prog.Prog]bool {
+		ret := map[*prog.Prog]bool{}
+		for i := 0; i < count; i++ {
+			inp := generateRangedInput(target, rs, from, to)
+			ret[inp.Prog] = true
+			corpus.Save(inp)
+		}
+		return ret
+	}
+	first := fillGroup(0, 1, 10)
+	second := fillGroup(2, 3, 10)
+
+	rnd := rand.New(rs)
+	const TOTAL = 10000
+	countIn := func(group map[*prog.Prog]bool) int {
+		count := 0
+		for i := 0; i < TOTAL; i++ {
+			if group[corpus.ChooseProgram(rnd)] {
+				count++
+			}
+		}
+		return count
+	}
+	assert.InDelta(t, countIn(second), TOTAL*0.5, TOTAL/25)
+
+	// The area is chosen with probability 1/2, the rest of the corpus in the other 1/2.
+	corpus.SetAutoFocusAreas([]FocusArea{
+		{Name: "second", CoverPCs: map[uint64]struct{}{2: {}}, Weight: 1},
+	})
+	assert.InDelta(t, countIn(second), TOTAL*0.75, TOTAL/25)
+
+	// Re-weighting keeps the programs.
+	corpus.SetAutoFocusAreas([]FocusArea{
+		{Name: "second", CoverPCs: map[uint64]struct{}{2: {}}, Weight: 3},
+	})
+	assert.InDelta(t, countIn(second), TOTAL*0.875, TOTAL/25)
+
+	corpus.SetAutoFocusAreas([]FocusArea{
+		{Name: "first", CoverPCs: map[uint64]struct{}{1: {}}, Weight: 1},
+	})
+	assert.InDelta(t, countIn(first), TOTAL*0.75, TOTAL/25)
+	fillGroup(1, 2, 5)
+	areas, progs := corpus.AutoFocusAreas()
+	assert.Len(t, areas, 1)
+	assert.Equal(t, "first", areas[0].Name)
+	assert.Equal(t, []int{15}, progs)
+
+	corpus.Minimize(true)
+	matching := 0
+	for _, item := range corpus.Items() {
+		if slices.Contains(item.Cover, 1) {
+			matching++
+		}
+	}
+	_, progs = corpus.AutoFocusAreas()
+	assert.Equal(t, []int{matching}, progs)
+}
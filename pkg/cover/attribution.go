@@ -0,0 +1,141 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"sort"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+)
+
+// Attribution describes how much coverage each syscall contributes to the corpus
+// that no other syscall provides.
+type Attribution struct {
+	Calls []CallAttribution `json:"calls"`
+	// Enabled syscalls that don't contribute any unique coverage,
+	// these are candidates for improving descriptions or disabling.
+	Redundant []string `json:"redundant"`
+}
+
+type CallAttribution struct {
+	Call        string `json:"call"`
+	Cover       int    `json:"cover"`        // PCs covered by the call
+	UniqueCover int    `json:"unique_cover"` // PCs that are not covered by any other call
+	// Functions that are reached only by this call.
+	UniqueFuncs []string          `json:"unique_funcs,omitempty"`
+	Files       []FileAttribution `json:"files,omitempty"`
+}
+
+// FileAttribution is the unique coverage of a call in a single file.
+type FileAttribution struct {
+	File        string   `json:"file"`
+	UniqueCover int      `json:"unique_cover"`
+	Funcs       []string `json:"funcs"` // functions that contain the unique PCs
+}
+
+// CallAttribution attributes coverage to syscalls.
+// calls maps syscall names to the PCs covered by the programs added to the corpus because of the call
+// (see corpus.Corpus.CallCover), enabled lists all enabled syscalls.
+// Calls are sorted by the unique coverage (most valuable first).
+func (rg *ReportGenerator) CallAttribution(calls map[string][]uint64, enabled []string) *Attribution {
+	callSets := make(map[string]Cover)
+	pcCalls := make(map[uint64]int)
+	for call, pcs := range calls {
+		cov := FromRaw(pcs)
+		callSets[call] = cov
+		for pc := range cov {
+			pcCalls[pc]++
+		}
+	}
+	for _, call := range enabled {
+		if callSets[call] == nil {
+			callSets[call] = make(Cover)
+		}
+	}
+	// Functions are identified by symbols, so that we don't need to symbolize PCs.
+	funcCalls := make(map[*backend.Symbol]map[string]bool)
+	for call, cov := range callSets {
+		for pc := range cov {
+			if sym := rg.findSymbol(pc); sym != nil {
+				if funcCalls[sym] == nil {
+					funcCalls[sym] = make(map[string]bool)
+				}
+				funcCalls[sym][call] = true
+			}
+		}
+	}
+	res := new(Attribution)
+	for call, cov := range callSets {
+		ca := &CallAttribution{
+			Call:  call,
+			Cover: len(cov),
+		}
+		files := make(map[string]*FileAttribution)
+		fileFuncs := make(map[string]map[string]bool)
+		uniqueFuncs := make(map[string]bool)
+		for pc := range cov {
+			if pcCalls[pc] != 1 {
+				continue
+			}
+			ca.UniqueCover++
+			sym := rg.findSymbol(pc)
+			if sym == nil {
+				continue
+			}
+			if len(funcCalls[sym]) == 1 {
+				uniqueFuncs[sym.Name] = true
+			}
+			file := ""
+			if sym.Unit != nil {
+				file = sym.Unit.Name
+			}
+			fa := files[file]
+			if fa == nil {
+				fa = &FileAttribution{File: file}
+				files[file] = fa
+				fileFuncs[file] = make(map[string]bool)
+			}
+			fa.UniqueCover++
+			fileFuncs[file][sym.Name] = true
+		}
+		ca.UniqueFuncs = sortedKeys(uniqueFuncs)
+		for file, fa := range files {
+			fa.Funcs = sortedKeys(fileFuncs[file])
+			ca.Files = append(ca.Files, *fa)
+		}
+		sort.Slice(ca.Files, func(i, j int) bool {
+			if ca.Files[i].UniqueCover != ca.Files[j].UniqueCover {
+				return ca.Files[i].UniqueCover > ca.Files[j].UniqueCover
+			}
+			return ca.Files[i].File < ca.Files[j].File
+		})
+		res.Calls = append(res.Calls, *ca)
+	}
+	sort.Slice(res.Calls, func(i, j int) bool {
+		if res.Calls[i].UniqueCover != res.Calls[j].UniqueCover {
+			return res.Calls[i].UniqueCover > res.Calls[j].UniqueCover
+		}
+		return res.Calls[i].Call < res.Calls[j].Call
+	})
+	isEnabled := make(map[string]bool)
+	for _, call := range enabled {
+		isEnabled[call] = true
+	}
+	for _, ca := range res.Calls {
+		if isEnabled[ca.Call] && ca.UniqueCover == 0 {
+			res.Redundant = append(res.Redundant, ca.Call)
+		}
+	}
+	sort.Strings(res.Redundant)
+	return res
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var res []string
+	for key := range m {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
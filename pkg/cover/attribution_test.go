@@ -0,0 +1,56 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallAttribution(t *testing.T) {
+	fileA := &backend.CompileUnit{ObjectUnit: backend.ObjectUnit{Name: "a.c"}}
+	fileB := &backend.CompileUnit{ObjectUnit: backend.ObjectUnit{Name: "b.c"}}
+	rg := &ReportGenerator{
+		Impl: &backend.Impl{
+			Symbols: []*backend.Symbol{
+				{ObjectUnit: backend.ObjectUnit{Name: "foo"}, Unit: fileA, Start: 0x100, End: 0x1ff},
+				{ObjectUnit: backend.ObjectUnit{Name: "bar"}, Unit: fileA, Start: 0x200, End: 0x2ff},
+				{ObjectUnit: backend.ObjectUnit{Name: "baz"}, Unit: fileB, Start: 0x300, End: 0x3ff},
+			},
+		},
+	}
+	calls := map[string][]uint64{
+		"open":  {0x100, 0x110, 0x200, 0x300},
+		"read":  {0x100, 0x210, 0x220},
+		"write": {0x100, 0x110},
+	}
+	res := rg.CallAttribution(calls, []string{"open", "read", "write", "close"})
+	assert.Equal(t, &Attribution{
+		Calls: []CallAttribution{
+			{
+				Call:        "open",
+				Cover:       4,
+				UniqueCover: 2,
+				UniqueFuncs: []string{"baz"},
+				Files: []FileAttribution{
+					{File: "a.c", UniqueCover: 1, Funcs: []string{"bar"}},
+					{File: "b.c", UniqueCover: 1, Funcs: []string{"baz"}},
+				},
+			},
+			{
+				Call:        "read",
+				Cover:       3,
+				UniqueCover: 2,
+				Files: []FileAttribution{
+					{File: "a.c", UniqueCover: 2, Funcs: []string{"bar"}},
+				},
+			},
+			{Call: "close"},
+			{Call: "write", Cover: 2},
+		},
+		Redundant: []string{"close", "write"},
+	}, res)
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/vminfo"
+	"golang.org/x/exp/maps"
+)
+
+// DiffInput is one side of a coverage diff.
+type DiffInput struct {
+	Name string
+	// RG is the report generator for the kernel build the coverage was collected on.
+	// Both sides may share the generator if they come from the same build.
+	RG *ReportGenerator
+	// Modules is the module layout of the machine the PCs were collected on (optional).
+	// If set, PCs are converted to the module layout the report generator was created with.
+	Modules []*vminfo.KernelModule
+	PCs     []uint64
+}
+
+type DiffParams struct {
+	Base DiffInput
+	New  DiffInput
+}
+
+// FileCoverDiff lists source lines and functions of a file that are covered only by one side.
+// Since different builds have different PCs, coverage is compared at the level of source lines.
+type FileCoverDiff struct {
+	FilePath   string   `json:"file_path"`
+	AddedLines []int    `json:"added_lines,omitempty"`
+	LostLines  []int    `json:"lost_lines,omitempty"`
+	AddedFuncs []string `json:"added_funcs,omitempty"`
+	LostFuncs  []string `json:"lost_funcs,omitempty"`
+
+	path     string // full path to the source file in the new build
+	basePath string // full path to the source file in the base build
+}
+
+type lineCover struct {
+	lines map[string]map[lineKey]int  // file -> covered lines
+	funcs map[string]map[string]bool // file -> covered functions
+	paths map[string]string          // file -> full path to the source file
+}
+
+// lineKey identifies a source line by its offset from the first line of the function.
+// Unlike plain line numbers, such keys stay the same across kernel builds if the code
+// above the function has changed.
+type lineKey struct {
+	fn     string
+	offset int
+}
+
+func (input *DiffInput) lineCover() (*lineCover, error) {
+	rg := input.RG
+	pcs := input.PCs
+	if len(input.Modules) != 0 {
+		// Convert the PCs to the module layout of the build.
+		can := NewCanonicalizer(rg.modules, true)
+		pcs = can.NewInstance(input.Modules).Canonicalize(pcs)
+	}
+	pcs = uniquePCs(Prog{PCs: pcs})
+	if len(pcs) != 0 {
+		if err := rg.symbolizePCs(pcs); err != nil {
+			return nil, fmt.Errorf("%v: %w", input.Name, err)
+		}
+	}
+	covered := FromRaw(pcs)
+	funcStart := make(map[string]map[string]int)
+	for _, frame := range rg.Frames {
+		if frame.StartLine < 0 {
+			continue
+		}
+		if funcStart[frame.Name] == nil {
+			funcStart[frame.Name] = make(map[string]int)
+		}
+		if start, ok := funcStart[frame.Name][frame.FuncName]; !ok || frame.StartLine < start {
+			funcStart[frame.Name][frame.FuncName] = frame.StartLine
+		}
+	}
+	lc := &lineCover{
+		lines: make(map[string]map[lineKey]int),
+		funcs: make(map[string]map[string]bool),
+		paths: make(map[string]string),
+	}
+	for _, frame := range rg.Frames {
+		if frame.StartLine < 0 {
+			continue
+		}
+		if _, ok := covered[frame.PC]; !ok {
+			continue
+		}
+		if lc.lines[frame.Name] == nil {
+			lc.lines[frame.Name] = make(map[lineKey]int)
+			lc.funcs[frame.Name] = make(map[string]bool)
+			lc.paths[frame.Name] = frame.Path
+		}
+		key := lineKey{frame.FuncName, frame.StartLine - funcStart[frame.Name][frame.FuncName]}
+		lc.lines[frame.Name][key] = frame.StartLine
+		if frame.FuncName != "" {
+			lc.funcs[frame.Name][frame.FuncName] = true
+		}
+	}
+	return lc, nil
+}
+
+// DiffCover returns files with coverage differences sorted by path.
+// Both sides are first converted to the module layouts of their builds with Canonicalizer.
+// For different kernel builds, lines are then matched by their offset within the function,
+// so the result is accurate as long as the covered functions themselves did not change.
+// Added lines are numbered as in the new build, lost lines as in the base build.
+func DiffCover(params DiffParams) ([]*FileCoverDiff, error) {
+	base, err := params.Base.lineCover()
+	if err != nil {
+		return nil, err
+	}
+	cur, err := params.New.lineCover()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	for name := range base.lines {
+		files[name] = true
+	}
+	for name := range cur.lines {
+		files[name] = true
+	}
+	var res []*FileCoverDiff
+	for _, name := range maps.Keys(files) {
+		fd := &FileCoverDiff{
+			FilePath:   name,
+			AddedLines: lineDiff(cur.lines[name], base.lines[name]),
+			LostLines:  lineDiff(base.lines[name], cur.lines[name]),
+			AddedFuncs: setDiff(cur.funcs[name], base.funcs[name]),
+			LostFuncs:  setDiff(base.funcs[name], cur.funcs[name]),
+			path:       cur.paths[name],
+			basePath:   base.paths[name],
+		}
+		if len(fd.AddedLines)+len(fd.LostLines)+len(fd.AddedFuncs)+len(fd.LostFuncs) != 0 {
+			res = append(res, fd)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].FilePath < res[j].FilePath
+	})
+	return res, nil
+}
+
+// lineDiff returns sorted numbers of the lines that are present in a, but not in b.
+func lineDiff(a, b map[lineKey]int) []int {
+	lines := make(map[int]bool)
+	for key, line := range a {
+		if _, ok := b[key]; !ok {
+			lines[line] = true
+		}
+	}
+	return setDiff(lines, nil)
+}
+
+func setDiff[T int | string](a, b map[T]bool) []T {
+	var res []T
+	for v := range a {
+		if !b[v] {
+			res = append(res, v)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// DoDiffJSONL writes one FileCoverDiff record per line.
+func DoDiffJSONL(w io.Writer, params DiffParams) error {
+	files, err := DiffCover(params)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for _, file := range files {
+		if err := encoder.Encode(file); err != nil {
+			return fmt.Errorf("failed to json.Encode(): %w", err)
+		}
+	}
+	return nil
+}
+
+// DoDiffHTML renders the summary of the coverage diff and the source of the added/lost lines.
+func DoDiffHTML(w io.Writer, params DiffParams) error {
+	files, err := DiffCover(params)
+	if err != nil {
+		return err
+	}
+	data := &templateDiffData{
+		Base: params.Base.Name,
+		New:  params.New.Name,
+	}
+	for _, file := range files {
+		data.AddedLines += len(file.AddedLines)
+		data.LostLines += len(file.LostLines)
+		data.AddedFuncs += len(file.AddedFuncs)
+		data.LostFuncs += len(file.LostFuncs)
+		// Missing sources are not fatal, the report just lacks the line contents.
+		source, _ := parseFile(file.path)
+		baseSource, _ := parseFile(file.basePath)
+		td := &templateDiffFile{FileCoverDiff: file}
+		for _, ln := range file.AddedLines {
+			td.Lines = append(td.Lines, makeTemplateDiffLine(source, ln, true))
+		}
+		for _, ln := range file.LostLines {
+			td.Lines = append(td.Lines, makeTemplateDiffLine(baseSource, ln, false))
+		}
+		sort.SliceStable(td.Lines, func(i, j int) bool {
+			return td.Lines[i].Line < td.Lines[j].Line
+		})
+		data.Files = append(data.Files, td)
+	}
+	return coverDiffTemplate.Execute(w, data)
+}
+
+func makeTemplateDiffLine(source [][]byte, ln int, added bool) templateDiffLine {
+	line := templateDiffLine{Line: ln, Added: added}
+	if ln > 0 && ln <= len(source) {
+		line.Source = string(source[ln-1])
+	}
+	return line
+}
+
+type templateDiffData struct {
+	Base       string
+	New        string
+	AddedLines int
+	LostLines  int
+	AddedFuncs int
+	LostFuncs  int
+	Files      []*templateDiffFile
+}
+
+type templateDiffFile struct {
+	*FileCoverDiff
+	Lines []templateDiffLine
+}
+
+type templateDiffLine struct {
+	Line   int
+	Added  bool
+	Source string
+}
+
+//go:embed templates/cover-diff.html
+var templatesCoverDiff string
+
+var coverDiffTemplate = template.Must(template.New("coverDiff").Parse(templatesCoverDiff))
@@ -0,0 +1,110 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/vminfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeDiffTestRG(modules []*vminfo.KernelModule, frames ...*backend.Frame) *ReportGenerator {
+	return &ReportGenerator{
+		modules: modules,
+		Impl:    &backend.Impl{Frames: frames},
+	}
+}
+
+func diffTestFrame(pc uint64, file, fn string, line int) *backend.Frame {
+	return &backend.Frame{
+		PC:       pc,
+		Name:     file,
+		Path:     "/nonexistent/" + file,
+		FuncName: fn,
+		Range:    backend.Range{StartLine: line, EndLine: line},
+	}
+}
+
+func TestDiffCover(t *testing.T) {
+	rg := makeDiffTestRG(nil,
+		diffTestFrame(0x10, "a.c", "foo", 1),
+		diffTestFrame(0x20, "a.c", "foo", 2),
+		diffTestFrame(0x30, "a.c", "bar", 10),
+		diffTestFrame(0x40, "b.c", "baz", 5),
+		diffTestFrame(0x50, "c.c", "qux", 7),
+	)
+	params := DiffParams{
+		Base: DiffInput{Name: "base", RG: rg, PCs: []uint64{0x10, 0x40, 0x50}},
+		New:  DiffInput{Name: "new", RG: rg, PCs: []uint64{0x10, 0x20, 0x30, 0x50}},
+	}
+	files, err := DiffCover(params)
+	require.NoError(t, err)
+	assert.Equal(t, []*FileCoverDiff{
+		{
+			FilePath:   "a.c",
+			AddedLines: []int{2, 10},
+			AddedFuncs: []string{"bar"},
+			path:       "/nonexistent/a.c",
+			basePath:   "/nonexistent/a.c",
+		},
+		{
+			FilePath:  "b.c",
+			LostLines: []int{5},
+			LostFuncs: []string{"baz"},
+			basePath:  "/nonexistent/b.c",
+		},
+	}, files)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, DoDiffJSONL(buf, params))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var rec FileCoverDiff
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rec))
+	assert.Equal(t, FileCoverDiff{FilePath: "b.c", LostLines: []int{5}, LostFuncs: []string{"baz"}}, rec)
+
+	buf.Reset()
+	require.NoError(t, DoDiffHTML(buf, params))
+	assert.Contains(t, buf.String(), "+10")
+	assert.Contains(t, buf.String(), "-5")
+}
+
+func TestDiffCoverBuilds(t *testing.T) {
+	// The same source lines have different PCs in the two builds,
+	// and both sides were collected with module layouts different from the builds.
+	// In the new build, 10 lines were added above foo.
+	baseRG := makeDiffTestRG([]*vminfo.KernelModule{{Name: "", Addr: 0}, {Name: "mod", Addr: 0x1000, Size: 0x100}},
+		diffTestFrame(0x1010, "mod.c", "foo", 1),
+		diffTestFrame(0x1020, "mod.c", "foo", 2),
+	)
+	newRG := makeDiffTestRG([]*vminfo.KernelModule{{Name: "", Addr: 0}, {Name: "mod", Addr: 0x100, Size: 0x100}},
+		diffTestFrame(0x110, "mod.c", "foo", 11),
+		diffTestFrame(0x120, "mod.c", "foo", 12),
+		diffTestFrame(0x130, "mod.c", "bar", 13),
+	)
+	files, err := DiffCover(DiffParams{
+		Base: DiffInput{
+			RG:      baseRG,
+			Modules: []*vminfo.KernelModule{{Name: "", Addr: 0}, {Name: "mod", Addr: 0x2000, Size: 0x100}},
+			PCs:     []uint64{0x2010, 0x2020},
+		},
+		New: DiffInput{
+			RG:      newRG,
+			Modules: []*vminfo.KernelModule{{Name: "", Addr: 0}, {Name: "mod", Addr: 0x3000, Size: 0x100}},
+			PCs:     []uint64{0x3010, 0x3030},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, []int{13}, files[0].AddedLines)
+	assert.Equal(t, []int{2}, files[0].LostLines)
+	assert.Equal(t, []string{"bar"}, files[0].AddedFuncs)
+	assert.Empty(t, files[0].LostFuncs)
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover/backend"
+)
+
+// exportFile is the coverage of a single source file in the form used by the standard formats:
+// per-line and per-function hit counts, where a hit is a program that covers the line/function.
+type exportFile struct {
+	name  string // path relative to the kernel source dir
+	path  string // full path to the source file
+	lines []exportLine
+	funcs []exportFunc
+}
+
+type exportLine struct {
+	line int
+	hits int
+}
+
+type exportFunc struct {
+	name string
+	line int // 0 if the function has no line information
+	hits int
+}
+
+func (rg *ReportGenerator) prepareExport(params HandlerParams) ([]*exportFile, error) {
+	progs := fixUpPCs(params.Progs, params.Filter)
+	// Only the files that have some coverage are exported. Symbolize all functions
+	// of these files, so that their uncovered lines are reported as well.
+	covered := make(map[*backend.CompileUnit]bool)
+	for _, pc := range uniquePCs(progs...) {
+		if sym := rg.findSymbol(pc); sym != nil {
+			covered[sym.Unit] = true
+		}
+	}
+	var pcs []uint64
+	for _, sym := range rg.Symbols {
+		if covered[sym.Unit] && len(sym.PCs) != 0 {
+			pcs = append(pcs, sym.PCs[0])
+		}
+	}
+	if len(pcs) != 0 {
+		if err := rg.symbolizePCs(pcs); err != nil {
+			return nil, fmt.Errorf("failed to symbolize PCs(): %w", err)
+		}
+	}
+	files, err := rg.prepareFileMap(progs, params.Force, params.Debug)
+	if err != nil {
+		return nil, err
+	}
+	funcLines := make(map[string]map[string]int)
+	for _, frame := range rg.Frames {
+		if frame.Inline || frame.StartLine <= 0 || frame.FuncName == "" {
+			continue
+		}
+		if funcLines[frame.Name] == nil {
+			funcLines[frame.Name] = make(map[string]int)
+		}
+		if ln := funcLines[frame.Name][frame.FuncName]; ln == 0 || frame.StartLine < ln {
+			funcLines[frame.Name][frame.FuncName] = frame.StartLine
+		}
+	}
+	var res []*exportFile
+	for fname, file := range files {
+		hits := make(map[int]int)
+		for _, r := range file.uncovered {
+			hits[r.StartLine] = 0
+		}
+		for _, r := range file.covered {
+			hits[r.StartLine] = len(file.lines[r.StartLine].progCount)
+		}
+		if !hasCoverage(hits, file.functions) {
+			continue
+		}
+		ef := &exportFile{
+			name: fname,
+			path: file.filename,
+		}
+		for ln, n := range hits {
+			ef.lines = append(ef.lines, exportLine{line: ln, hits: n})
+		}
+		sort.Slice(ef.lines, func(i, j int) bool {
+			return ef.lines[i].line < ef.lines[j].line
+		})
+		for _, fn := range file.functions {
+			ef.funcs = append(ef.funcs, exportFunc{
+				name: fn.name,
+				line: funcLines[fname][fn.name],
+				hits: fn.hits,
+			})
+		}
+		res = append(res, ef)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].name < res[j].name
+	})
+	return res, nil
+}
+
+func hasCoverage(hits map[int]int, funcs []*function) bool {
+	for _, n := range hits {
+		if n != 0 {
+			return true
+		}
+	}
+	for _, fn := range funcs {
+		if fn.hits != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DoLCOV generates the coverage report in the LCOV tracefile format (as produced by geninfo).
+// Hit counts are the number of programs that cover the line or function.
+func (rg *ReportGenerator) DoLCOV(w io.Writer, params HandlerParams) error {
+	files, err := rg.prepareExport(params)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(w)
+	for _, file := range files {
+		fmt.Fprintf(buf, "TN:\nSF:%v\n", file.path)
+		// LCOV requires the function start line, so functions without line information are skipped.
+		var funcs []exportFunc
+		for _, fn := range file.funcs {
+			if fn.line != 0 {
+				funcs = append(funcs, fn)
+			}
+		}
+		funcsHit := 0
+		for _, fn := range funcs {
+			fmt.Fprintf(buf, "FN:%v,%v\n", fn.line, fn.name)
+		}
+		for _, fn := range funcs {
+			fmt.Fprintf(buf, "FNDA:%v,%v\n", fn.hits, fn.name)
+			if fn.hits != 0 {
+				funcsHit++
+			}
+		}
+		fmt.Fprintf(buf, "FNF:%v\nFNH:%v\n", len(funcs), funcsHit)
+		linesHit := 0
+		for _, ln := range file.lines {
+			fmt.Fprintf(buf, "DA:%v,%v\n", ln.line, ln.hits)
+			if ln.hits != 0 {
+				linesHit++
+			}
+		}
+		fmt.Fprintf(buf, "LF:%v\nLH:%v\nend_of_record\n", len(file.lines), linesHit)
+	}
+	return buf.Flush()
+}
+
+type coberturaCoverage struct {
+	XMLName      xml.Name           `xml:"coverage"`
+	LineRate     string             `xml:"line-rate,attr"`
+	BranchRate   string             `xml:"branch-rate,attr"`
+	LinesCovered int                `xml:"lines-covered,attr"`
+	LinesValid   int                `xml:"lines-valid,attr"`
+	Version      string             `xml:"version,attr"`
+	Timestamp    int64              `xml:"timestamp,attr"`
+	Sources      []string           `xml:"sources>source"`
+	Packages     []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	Filename   string            `xml:"filename,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Methods    []coberturaMethod `xml:"methods>method"`
+	Lines      []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// DoCobertura generates the coverage report in the Cobertura XML format.
+// Files are grouped into packages by directory, functions are reported as methods
+// with a single line (the function start) that holds the function hit count.
+func (rg *ReportGenerator) DoCobertura(w io.Writer, params HandlerParams) error {
+	files, err := rg.prepareExport(params)
+	if err != nil {
+		return err
+	}
+	res := &coberturaCoverage{
+		BranchRate: "0",
+		Version:    "syzkaller",
+		Timestamp:  time.Now().Unix(),
+		Sources:    []string{rg.srcDir},
+	}
+	packages := make(map[string]*coberturaPackage)
+	packageLines := make(map[string][2]int)
+	var packageNames []string
+	for _, file := range files {
+		dir := filepath.Dir(file.name)
+		pkg := packages[dir]
+		if pkg == nil {
+			pkg = &coberturaPackage{Name: dir, BranchRate: "0"}
+			packages[dir] = pkg
+			packageNames = append(packageNames, dir)
+		}
+		class := coberturaClass{
+			Name:       file.name,
+			Filename:   file.name,
+			BranchRate: "0",
+		}
+		covered := 0
+		for _, ln := range file.lines {
+			class.Lines = append(class.Lines, coberturaLine{Number: ln.line, Hits: ln.hits})
+			if ln.hits != 0 {
+				covered++
+			}
+		}
+		for _, fn := range file.funcs {
+			method := coberturaMethod{
+				Name:       fn.name,
+				LineRate:   lineRate(min(fn.hits, 1), 1),
+				BranchRate: "0",
+			}
+			if fn.line != 0 {
+				method.Lines = []coberturaLine{{Number: fn.line, Hits: fn.hits}}
+			}
+			class.Methods = append(class.Methods, method)
+		}
+		class.LineRate = lineRate(covered, len(file.lines))
+		pkg.Classes = append(pkg.Classes, class)
+		stat := packageLines[dir]
+		packageLines[dir] = [2]int{stat[0] + covered, stat[1] + len(file.lines)}
+		res.LinesCovered += covered
+		res.LinesValid += len(file.lines)
+	}
+	for _, name := range packageNames {
+		pkg := packages[name]
+		pkg.LineRate = lineRate(packageLines[name][0], packageLines[name][1])
+		res.Packages = append(res.Packages, *pkg)
+	}
+	res.LineRate = lineRate(res.LinesCovered, res.LinesValid)
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "\t")
+	if err := encoder.Encode(res); err != nil {
+		return fmt.Errorf("failed to encode cobertura report: %w", err)
+	}
+	return nil
+}
+
+func lineRate(covered, total int) string {
+	if total == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(total), 'f', 4, 64)
+}
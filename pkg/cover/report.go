@@ -20,6 +20,7 @@ type ReportGenerator struct {
 	buildDir        string
 	subsystem       []mgrconfig.Subsystem
 	rawCoverEnabled bool
+	modules         []*vminfo.KernelModule
 	*backend.Impl
 }
 
@@ -48,6 +49,7 @@ func MakeReportGenerator(cfg *mgrconfig.Config, modules []*vminfo.KernelModule)
 		buildDir:        cfg.KernelBuildSrc,
 		subsystem:       cfg.KernelSubsystem,
 		rawCoverEnabled: cfg.RawCover,
+		modules:         modules,
 		Impl:            impl,
 	}
 	return rg, nil
@@ -68,6 +70,7 @@ type function struct {
 	name    string
 	pcs     int
 	covered int
+	hits    int // number of programs that cover the function
 }
 
 type line struct {
@@ -126,11 +129,16 @@ func (rg *ReportGenerator) prepareFileMap(progs []Prog, force, debug bool) (file
 			name: s.Name,
 			pcs:  len(s.PCs),
 		}
+		coveredBy := make(map[int]bool)
 		for _, pc := range s.PCs {
 			if pcToProgs[pc] != nil {
 				fun.covered++
+				for progIndex := range pcToProgs[pc] {
+					coveredBy[progIndex] = true
+				}
 			}
 		}
+		fun.hits = len(coveredBy)
 		f := files[s.Unit.Name]
 		f.functions = append(f.functions, fun)
 	}
@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -178,6 +179,8 @@ func testReportGenerator(t *testing.T, target *targets.Target, test Test) {
 	checkCSVReport(t, reps.csv.Bytes())
 	checkJSONLReport(t, reps.jsonl.Bytes(), sampleCoverJSON)
 	checkJSONLReport(t, reps.jsonlPrograms.Bytes(), sampleJSONLlProgs)
+	checkLCOVReport(t, reps.lcov.String())
+	checkCoberturaReport(t, reps.cobertura.Bytes())
 }
 
 const kcovCode = `
@@ -295,6 +298,8 @@ type reports struct {
 	csv           *bytes.Buffer
 	jsonl         *bytes.Buffer
 	jsonlPrograms *bytes.Buffer
+	lcov          *bytes.Buffer
+	cobertura     *bytes.Buffer
 }
 
 func generateReport(t *testing.T, target *targets.Target, test *Test) (*reports, error) {
@@ -392,10 +397,14 @@ func generateReport(t *testing.T, target *targets.Target, test *Test) (*reports,
 		csv:           new(bytes.Buffer),
 		jsonl:         new(bytes.Buffer),
 		jsonlPrograms: new(bytes.Buffer),
+		lcov:          new(bytes.Buffer),
+		cobertura:     new(bytes.Buffer),
 	}
 	assert.NoError(t, rg.DoFuncCover(res.csv, params))
 	assert.NoError(t, rg.DoCoverJSONL(res.jsonl, params))
 	assert.NoError(t, rg.DoCoverPrograms(res.jsonlPrograms, params))
+	assert.NoError(t, rg.DoLCOV(res.lcov, params))
+	assert.NoError(t, rg.DoCobertura(res.cobertura, params))
 	return res, nil
 }
 
@@ -424,6 +433,35 @@ func checkCSVReport(t *testing.T, CSVReport []byte) {
 	}
 }
 
+func checkLCOVReport(t *testing.T, report string) {
+	assert.Contains(t, report, "SF:")
+	assert.Contains(t, report, "FNDA:1,main\n")
+	assert.Contains(t, report, "DA:1,1\n")
+	assert.NotContains(t, report, "FN:0,")
+	assert.True(t, strings.HasSuffix(report, "end_of_record\n"))
+}
+
+func checkCoberturaReport(t *testing.T, report []byte) {
+	var res coberturaCoverage
+	if err := xml.Unmarshal(report, &res); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotZero(t, res.LinesCovered)
+	foundMain := false
+	for _, pkg := range res.Packages {
+		for _, class := range pkg.Classes {
+			for _, method := range class.Methods {
+				if method.Name == "main" {
+					foundMain = true
+					assert.Equal(t, "1.0000", method.LineRate)
+					assert.Equal(t, []coberturaLine{{Number: 1, Hits: 1}}, method.Lines)
+				}
+			}
+		}
+	}
+	assert.True(t, foundMain, "no main in the Cobertura report")
+}
+
 func checkJSONLReport(t *testing.T, gotBytes, wantBytes []byte) {
 	compacted := new(bytes.Buffer)
 	if err := json.Compact(compacted, wantBytes); err != nil {
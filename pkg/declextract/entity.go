@@ -43,11 +43,12 @@ type FunctionScope struct {
 	Arg int `json:"arg"`
 	// The set of case values for this scope.
 	// It's empt for the global scope for the default case scope.
-	Values    []string      `json:"values,omitempty"`
-	StartLine int           `json:"start_line,omitempty"`
-	EndLine   int           `json:"end_line,omitempty"`
-	Calls     []string      `json:"calls,omitempty"`
-	Facts     []*TypingFact `json:"facts,omitempty"`
+	Values     []string      `json:"values,omitempty"`
+	StartLine  int           `json:"start_line,omitempty"`
+	EndLine    int           `json:"end_line,omitempty"`
+	Calls      []string      `json:"calls,omitempty"`
+	UserCopies []*UserCopy   `json:"user_copies,omitempty"`
+	Facts      []*TypingFact `json:"facts,omitempty"`
 
 	fn            *Function
 	calls         []*Function
@@ -195,6 +196,18 @@ type BufferType struct {
 	IsNonTerminated bool `json:"is_non_terminated,omitempty"`
 }
 
+// UserCopy describes a copy_from_user/copy_to_user call of a constant size.
+type UserCopy struct {
+	// The user pointer.
+	Ptr    *TypingEntity `json:"ptr,omitempty"`
+	Size   int           `json:"size,omitempty"`
+	ToUser bool          `json:"to_user,omitempty"`
+	// Type passed to sizeof in the size argument, if any.
+	Type *Type `json:"type,omitempty"`
+
+	scope *FunctionScope
+}
+
 type TypingFact struct {
 	Src *TypingEntity `json:"src,omitempty"`
 	Dst *TypingEntity `json:"dst,omitempty"`
@@ -42,9 +42,12 @@ func (ctx *context) serializeFileOps() {
 			})
 		}
 		var ioctlCmds []string
+		ioctlArgs := make(map[string]*ioctlArg)
 		if fops.ioctl != nil {
 			ioctlCmds = ctx.inferCommandVariants(fops.Ioctl, fops.SourceFile, ioctlCmdArg)
 			for _, cmd := range ioctlCmds {
+				arg := ctx.inferIoctlArg(fops, cmd)
+				ioctlArgs[cmd] = arg
 				ctx.noteInterface(&Interface{
 					Type:             IfaceIoctl,
 					Name:             cmd,
@@ -52,28 +55,34 @@ func (ctx *context) serializeFileOps() {
 					Files:            []string{fops.ioctl.File},
 					Func:             fops.Ioctl,
 					AutoDescriptions: canGenerate,
+					ArgSource:        arg.source,
+					ArgSize:          arg.size,
 					scopeArg:         ioctlCmdArg,
 					scopeVal:         cmd,
 				})
 			}
 			if len(ioctlCmds) == 0 {
+				arg := ctx.inferIoctlArg(fops, "")
+				ioctlArgs[""] = arg
 				ctx.noteInterface(&Interface{
 					Type:             IfaceIoctl,
 					Name:             fops.Ioctl,
 					Files:            []string{fops.ioctl.File},
 					Func:             fops.Ioctl,
 					AutoDescriptions: canGenerate,
+					ArgSource:        arg.source,
+					ArgSize:          arg.size,
 				})
 			}
 		}
 		if len(files) == 0 {
 			continue // each unmapped entry means some code we don't know how to cover yet
 		}
-		ctx.createFops(fops, files, ioctlCmds)
+		ctx.createFops(fops, files, ioctlCmds, ioctlArgs)
 	}
 }
 
-func (ctx *context) createFops(fops *FileOps, files, ioctlCmds []string) {
+func (ctx *context) createFops(fops *FileOps, files, ioctlCmds []string, ioctlArgs map[string]*ioctlArg) {
 	name := ctx.uniqualize("fops name", fops.Name)
 	// If it has only open, then emit only openat that returns generic fd.
 	fdt := "fd"
@@ -104,42 +113,84 @@ func (ctx *context) createFops(fops *FileOps, files, ioctlCmds []string) {
 			" flags flags[mmap_flags], fd %v, offset fileoff)\n", suffix, fdt)
 	}
 	if fops.Ioctl != "" {
-		ctx.createIoctls(fops, ioctlCmds, suffix, fdt)
+		ctx.createIoctls(fops, ioctlCmds, ioctlArgs, suffix, fdt)
 	}
 	ctx.fmt("\n")
 }
 
-func (ctx *context) createIoctls(fops *FileOps, ioctlCmds []string, suffix, fdt string) {
-	const defaultArgType = "ptr[in, array[int8]]"
-	cmds := ctx.inferCommandVariants(fops.Ioctl, fops.SourceFile, ioctlCmdArg)
-	if len(cmds) == 0 {
+func (ctx *context) createIoctls(fops *FileOps, ioctlCmds []string, ioctlArgs map[string]*ioctlArg,
+	suffix, fdt string) {
+	if len(ioctlCmds) == 0 {
 		retType := ctx.inferReturnType(fops.Ioctl, fops.SourceFile, -1, "")
-		argType := ctx.inferArgType(fops.Ioctl, fops.SourceFile, ioctlArgArg, -1, "")
-		if argType == "" {
-			argType = defaultArgType
-		}
-		ctx.fmt("ioctl%v(fd %v, cmd intptr, arg %v) %v\n", suffix, fdt, argType, retType)
+		ctx.fmt("ioctl%v(fd %v, cmd intptr, arg %v) %v\n", suffix, fdt, ioctlArgs[""].typ, retType)
 		return
 	}
-	for _, cmd := range cmds {
-		argType := defaultArgType
-		if typ := ctx.ioctls[cmd]; typ != nil {
+	for _, cmd := range ioctlCmds {
+		retType := ctx.inferReturnType(fops.Ioctl, fops.SourceFile, ioctlCmdArg, cmd)
+		name := ctx.uniqualize("ioctl cmd", cmd)
+		ctx.fmt("ioctl%v_%v(fd %v, cmd const[%v], arg %v) %v\n",
+			autoSuffix, name, fdt, cmd, ioctlArgs[cmd].typ, retType)
+	}
+}
+
+type ioctlArg struct {
+	typ    string
+	source string
+	size   int
+}
+
+// inferIoctlArg infers type of the ioctl argument for the given command (or for all commands if cmd is empty).
+// The type is taken from the _IOW/_IOR/etc macro used to define the command, if possible.
+// Otherwise we look at copy_from_user/copy_to_user calls the argument flows to,
+// and then at other functions the argument flows to (e.g. if it's used as an fd).
+func (ctx *context) inferIoctlArg(fops *FileOps, cmd string) *ioctlArg {
+	scopeArg := ioctlCmdArg
+	if cmd == "" {
+		scopeArg = -1
+	}
+	if typ := ctx.ioctls[cmd]; typ != nil {
+		size := 0
+		if typ.Ptr != nil {
+			size = ctx.typeSize(typ.Ptr.Elem)
+		}
+		f := &Field{
+			Name: strings.ToLower(cmd),
+			Type: typ,
+		}
+		return &ioctlArg{ctx.fieldType(f, nil, "", false), ArgSourceMacro, size}
+	}
+	if uc := ctx.inferUserCopy(fops.Ioctl, fops.SourceFile, ioctlArgArg, scopeArg, cmd); uc != nil {
+		elem := fmt.Sprintf("array[int8, %v]", uc.size)
+		if uc.typ != nil {
 			f := &Field{
 				Name: strings.ToLower(cmd),
-				Type: typ,
-			}
-			argType = ctx.fieldType(f, nil, "", false)
-		} else {
-			argType = ctx.inferArgType(fops.Ioctl, fops.SourceFile, ioctlArgArg, ioctlCmdArg, cmd)
-			if argType == "" {
-				argType = defaultArgType
+				Type: uc.typ,
 			}
+			elem = ctx.fieldType(f, nil, "", true)
+		}
+		return &ioctlArg{fmt.Sprintf("ptr[%v, %v]", uc.dir, elem), ArgSourceUserCopy, uc.size}
+	}
+	if typ := ctx.inferArgType(fops.Ioctl, fops.SourceFile, ioctlArgArg, scopeArg, cmd); typ != "" {
+		return &ioctlArg{typ, ArgSourceDataFlow, 0}
+	}
+	return &ioctlArg{typ: "ptr[in, array[int8]]"}
+}
+
+// typeSize returns size of the type in bytes, or 0 if it's unknown.
+func (ctx *context) typeSize(t *Type) int {
+	switch {
+	case t.Int != nil:
+		return t.Int.ByteSize
+	case t.Array != nil && t.Array.IsConstSize:
+		return t.Array.MaxSize * ctx.typeSize(t.Array.Elem)
+	case t.Buffer != nil && t.Buffer.MinSize == t.Buffer.MaxSize:
+		return t.Buffer.MaxSize
+	case t.Struct != "":
+		if str := ctx.structs[strings.TrimSuffix(t.Struct, autoSuffix)+autoSuffix]; str != nil {
+			return str.ByteSize
 		}
-		retType := ctx.inferReturnType(fops.Ioctl, fops.SourceFile, ioctlCmdArg, cmd)
-		name := ctx.uniqualize("ioctl cmd", cmd)
-		ctx.fmt("ioctl%v_%v(fd %v, cmd const[%v], arg %v) %v\n",
-			autoSuffix, name, fdt, cmd, argType, retType)
 	}
+	return 0
 }
 
 // mapFopsToFiles maps file_operations to actual file names.
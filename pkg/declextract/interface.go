@@ -24,6 +24,12 @@ type Interface struct {
 	ReachableLOC       int
 	CoveredBlocks      int
 	TotalBlocks        int
+	// For ioctls: where the argument type comes from (one of ArgSource* consts, empty if unknown),
+	// and size of the memory the argument points to (0 if unknown).
+	ArgSource string
+	ArgSize   int
+	// Hand-written descriptions of the same ioctl command that contradict the inferred argument.
+	ManualMismatches []string
 
 	scopeArg int
 	scopeVal string
@@ -48,6 +54,10 @@ const (
 	AccessUser    = "user"
 	AccessNsAdmin = "ns_admin"
 	AccessAdmin   = "admin"
+
+	ArgSourceMacro    = "macro"    // _IOW/_IOR/etc macro used to define the command
+	ArgSourceUserCopy = "usercopy" // copy_from_user/copy_to_user the argument flows to
+	ArgSourceDataFlow = "dataflow" // other functions the argument flows to
 )
 
 func (ctx *context) noteInterface(iface *Interface) {
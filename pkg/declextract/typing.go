@@ -30,7 +30,8 @@ import (
 // Other potential improvements:
 // - Add more functions that consume/produce resources.
 // - Refine enum types. If we see an argument is used in bitops with an enum, it has that enum type.
-// - Infer pointer types when they flow to copy_from_user (sometimes they are declared as uint64).
+// - Infer pointer types when they flow to copy_from_user for syscall arguments and struct fields
+//   (currently this is done only for ioctl arguments).
 // - Infer that pointers are file names (they should flow to some known function for path resolution).
 // - Use SSA analysis to track flow via local variables better. Potentiall we can just rename on every next use
 //   and ignore backwards edges (it's unlikely that backwards edges are required for type inference).
@@ -107,6 +108,8 @@ type typingNode struct {
 	fn    *Function
 	arg   int
 	flows [2]map[*typingNode][]*FunctionScope
+	// copy_from_user/copy_to_user calls that use this node as the user pointer.
+	copies []*UserCopy
 }
 
 const (
@@ -128,6 +131,12 @@ func (ctx *context) processTypingFacts() {
 				src.flows[flowTo][dst] = append(src.flows[flowTo][dst], scope)
 				dst.flows[flowFrom][src] = append(dst.flows[flowFrom][src], scope)
 			}
+			for _, uc := range scope.UserCopies {
+				uc.scope = scope
+				if n := ctx.canonicalNode(fn, uc.Ptr); n != nil {
+					n.copies = append(n.copies, uc)
+				}
+			}
 		}
 	}
 }
@@ -213,6 +222,82 @@ func (ctx *context) inferFuncNode(name, file, node string, scopeArg int, scopeVa
 	return ctx.inferNodeType(fn.facts[node], scopeFnArgs, scopeVal, fmt.Sprintf("%v %v", name, node))
 }
 
+// userCopyType is the pointer type inferred from copy_from_user/copy_to_user calls.
+type userCopyType struct {
+	dir  string
+	size int
+	typ  *Type // nil if the copy size is not a sizeof
+}
+
+// inferUserCopy infers the type of the memory pointed to by the function argument
+// based on copy_from_user/copy_to_user calls the argument flows to.
+// If there are several copies of different sizes (e.g. a header is copied first),
+// the largest one is used. Direction is a union of directions of all copies.
+func (ctx *context) inferUserCopy(name, file string, arg, scopeArg int, scopeVal string) *userCopyType {
+	fn := ctx.findFunc(name, file)
+	if fn == nil {
+		return nil
+	}
+	start := fn.facts[fmt.Sprintf("arg%v", arg)]
+	if start == nil {
+		return nil
+	}
+	scopeFnArgs := ctx.inferArgFlow(fnArg{fn, scopeArg})
+	if scopeFnArgs == nil && scopeArg >= 0 {
+		// The command does not flow anywhere, but we still need to limit the walk to the command scope.
+		scopeFnArgs = map[fnArg]bool{{fn, scopeArg}: true}
+	}
+	// Breadth-first search, so that for copies of the same size we prefer closer ones.
+	var best *UserCopy
+	var in, out bool
+	visited := map[*typingNode]bool{start: true}
+	layer := []*typingNode{start}
+	for depth := 0; len(layer) != 0 && depth < maxTraversalDepth; depth++ {
+		var next []*typingNode
+		var candidates []*UserCopy
+		for _, n := range layer {
+			for _, uc := range n.copies {
+				if !relevantScope(scopeFnArgs, scopeVal, uc.scope) {
+					continue
+				}
+				in = in || !uc.ToUser
+				out = out || uc.ToUser
+				candidates = append(candidates, uc)
+			}
+			for e, scopes := range n.flows[flowTo] {
+				if !visited[e] && relevantScopes(scopeFnArgs, scopeVal, scopes) {
+					visited[e] = true
+					next = append(next, e)
+				}
+			}
+		}
+		// Sort candidates to make the result stable.
+		candidates = sortAndDedupSlice(candidates)
+		for _, uc := range candidates {
+			if best == nil || uc.Size > best.Size {
+				best = uc
+			}
+		}
+		layer = next
+	}
+	if best == nil {
+		return nil
+	}
+	res := &userCopyType{
+		dir:  "in",
+		size: best.Size,
+		typ:  best.Type,
+	}
+	if out {
+		res.dir = "out"
+		if in {
+			res.dir = "inout"
+		}
+	}
+	ctx.trace("inferred %v arg%v user copy\n  %v %v bytes", name, arg, res.dir, res.size)
+	return res
+}
+
 func (ctx *context) inferFieldType(structName, field string) string {
 	name := fmt.Sprintf("%v.%v", structName, field)
 	return ctx.inferNodeType(ctx.facts[name], nil, "", name)
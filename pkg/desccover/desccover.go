@@ -0,0 +1,250 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package desccover shows which parts of syscall descriptions are exercised by programs:
+// values of integer fields, flags that are set and union options that are chosen.
+// It allows to find flag values that are never used, union options that are never chosen
+// and fields that always have the same value (e.g. always zero).
+package desccover
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/syzkaller/prog"
+)
+
+// Stats accumulates description coverage over a set of programs.
+type Stats struct {
+	target *prog.Target
+	progs  int
+	fields map[string]*fieldStats
+	unions map[string]*unionStats
+}
+
+type fieldStats struct {
+	typ    prog.Type
+	count  int
+	values map[uint64]int  // at most maxValues distinct values are tracked
+	other  int             // number of values that did not fit into values
+	bits   uint64          // union of all values
+	flags  map[uint64]bool // flag values that were used as is (for flags fields)
+}
+
+type unionStats struct {
+	typ     *prog.UnionType
+	options []int
+}
+
+// Don't track more distinct values than this for each field.
+const maxValues = 16
+
+func NewStats(target *prog.Target) *Stats {
+	return &Stats{
+		target: target,
+		fields: make(map[string]*fieldStats),
+		unions: make(map[string]*unionStats),
+	}
+}
+
+// Add accounts all arguments of the program.
+// Syscall arguments are accounted per syscall, struct and union fields are accounted per type,
+// i.e. values of a struct field are aggregated over all syscalls that use the struct.
+func (s *Stats) Add(p *prog.Prog) {
+	s.progs++
+	for _, c := range p.Calls {
+		prog.ForeachArg(c, func(arg prog.Arg, ctx *prog.ArgCtx) {
+			if ctx.Field != nil {
+				s.addField(c.Meta.Name, ctx.Field, arg)
+			}
+			switch a := arg.(type) {
+			case *prog.GroupArg:
+				if typ, ok := a.Type().(*prog.StructType); ok {
+					for i, inner := range a.Inner {
+						s.addField(typ.Name(), &typ.Fields[i], inner)
+					}
+				}
+			case *prog.UnionArg:
+				s.addUnion(a)
+				typ := a.Type().(*prog.UnionType)
+				s.addField(typ.Name(), &typ.Fields[a.Index], a.Option)
+			}
+		})
+	}
+}
+
+func (s *Stats) addField(parent string, field *prog.Field, arg prog.Arg) {
+	a, ok := arg.(*prog.ConstArg)
+	if !ok {
+		return
+	}
+	switch field.Type.(type) {
+	case *prog.IntType, *prog.FlagsType:
+	default:
+		// Other const args either have fixed values (const, len, csum)
+		// or their values are not interesting (proc, resources).
+		return
+	}
+	key := parent + "." + field.Name
+	fs := s.fields[key]
+	if fs == nil {
+		fs = &fieldStats{
+			typ:    field.Type,
+			values: make(map[uint64]int),
+			flags:  make(map[uint64]bool),
+		}
+		s.fields[key] = fs
+	}
+	fs.count++
+	fs.bits |= a.Val
+	if flags, ok := field.Type.(*prog.FlagsType); ok {
+		for _, val := range flags.Vals {
+			if val == a.Val {
+				fs.flags[val] = true
+			}
+		}
+	}
+	if _, ok := fs.values[a.Val]; ok || len(fs.values) < maxValues {
+		fs.values[a.Val]++
+	} else {
+		fs.other++
+	}
+}
+
+func (s *Stats) addUnion(arg *prog.UnionArg) {
+	typ := arg.Type().(*prog.UnionType)
+	us := s.unions[typ.Name()]
+	if us == nil {
+		us = &unionStats{
+			typ:     typ,
+			options: make([]int, len(typ.Fields)),
+		}
+		s.unions[typ.Name()] = us
+	}
+	us.options[arg.Index]++
+}
+
+// Report is the summary of the collected stats.
+type Report struct {
+	Progs  int
+	Fields []Field
+	Unions []Union
+}
+
+type Field struct {
+	Name  string // syscall or struct name + field name, e.g. "open.flags" or "sockaddr_in.family"
+	Type  string
+	Count int
+	// Most frequent values first.
+	Values []Value
+	// Number of values that are not present in Values.
+	Other int
+	// Constant is set if the field was seen at least MinCount times and always had the same value.
+	Constant bool
+	// Flag values that were never set.
+	UnusedFlags []string
+}
+
+type Value struct {
+	Value uint64
+	Count int
+}
+
+type Union struct {
+	Name    string
+	Count   int
+	Options []Option
+	// Number of options that were never chosen.
+	Unused int
+}
+
+type Option struct {
+	Name  string
+	Count int
+}
+
+// MinCount is the number of samples after which a field that always has the same value is reported as constant.
+const MinCount = 10
+
+func (s *Stats) Report() *Report {
+	rep := &Report{Progs: s.progs}
+	for name, fs := range s.fields {
+		field := &Field{
+			Name:  name,
+			Type:  fs.typ.Name(),
+			Count: fs.count,
+			Other: fs.other,
+		}
+		for val, count := range fs.values {
+			field.Values = append(field.Values, Value{val, count})
+		}
+		sort.Slice(field.Values, func(i, j int) bool {
+			if field.Values[i].Count != field.Values[j].Count {
+				return field.Values[i].Count > field.Values[j].Count
+			}
+			return field.Values[i].Value < field.Values[j].Value
+		})
+		field.Constant = fs.count >= MinCount && len(fs.values) == 1
+		if flags, ok := fs.typ.(*prog.FlagsType); ok {
+			field.UnusedFlags = s.unusedFlags(flags, fs)
+		}
+		rep.Fields = append(rep.Fields, *field)
+	}
+	sort.Slice(rep.Fields, func(i, j int) bool {
+		return rep.Fields[i].Name < rep.Fields[j].Name
+	})
+	for name, us := range s.unions {
+		union := &Union{Name: name}
+		for i, count := range us.options {
+			union.Count += count
+			if count == 0 {
+				union.Unused++
+			}
+			union.Options = append(union.Options, Option{us.typ.Fields[i].Name, count})
+		}
+		rep.Unions = append(rep.Unions, *union)
+	}
+	sort.Slice(rep.Unions, func(i, j int) bool {
+		return rep.Unions[i].Name < rep.Unions[j].Name
+	})
+	return rep
+}
+
+func (s *Stats) unusedFlags(typ *prog.FlagsType, fs *fieldStats) []string {
+	names := make(map[uint64]string)
+	for _, name := range s.target.FlagsMap[typ.TypeName] {
+		if val, ok := s.target.ConstMap[name]; ok {
+			names[val] = name
+		}
+	}
+	var res []string
+	for _, val := range typ.Vals {
+		// Values of bitmask flags are combinations of the flags, so we can only check bits.
+		if fs.flags[val] || typ.BitMask && val != 0 && fs.bits&val == val {
+			continue
+		}
+		name := names[val]
+		if name == "" {
+			name = fmt.Sprintf("%#x", val)
+		}
+		res = append(res, name)
+	}
+	return res
+}
+
+// Problems returns the report with only the potential problems:
+// constant fields, fields with unused flags and unions with unused options.
+func (rep *Report) Problems() *Report {
+	res := &Report{Progs: rep.Progs}
+	for _, field := range rep.Fields {
+		if field.Constant || len(field.UnusedFlags) != 0 {
+			res.Fields = append(res.Fields, field)
+		}
+	}
+	for _, union := range rep.Unions {
+		if union.Unused != 0 {
+			res.Unions = append(res.Unions, union)
+		}
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package desccover
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	require.NoError(t, err)
+	stats := NewStats(target)
+	progs := []string{
+		`mutate_flags(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x1, 0x9)`,
+		`mutate_flags(&(0x7f0000000000)="2e2f66696c653000", 0x0, 0x0, 0x1)`,
+		`test$union0(&(0x7f0000000000)={0x1, @f2=0x2})`,
+		`test$union0(&(0x7f0000000000)={0x1, @f0=0x3})`,
+	}
+	for i := 0; i < MinCount; i++ {
+		progs = append(progs, `mutate5(&(0x7f0000000000)="2e2f66696c653000", 0xabababab)`)
+	}
+	for _, text := range progs {
+		p, err := target.Deserialize([]byte(text), prog.NonStrict)
+		require.NoError(t, err)
+		stats.Add(p)
+	}
+	rep := stats.Report()
+	assert.Equal(t, len(progs), rep.Progs)
+
+	fields := make(map[string]*Field)
+	for i := range rep.Fields {
+		fields[rep.Fields[i].Name] = &rep.Fields[i]
+	}
+	flags := fields["mutate_flags.flags"]
+	require.NotNil(t, flags)
+	assert.Equal(t, "bitmask_flags", flags.Type)
+	assert.Equal(t, 2, flags.Count)
+	assert.Equal(t, []Value{{0x1, 1}, {0x9, 1}}, flags.Values)
+	assert.Equal(t, []string{"0x10"}, flags.UnusedFlags)
+	assert.False(t, flags.Constant)
+
+	i1 := fields["mutate_flags.i1"]
+	require.NotNil(t, i1)
+	assert.Equal(t, []Value{{0x0, 2}}, i1.Values)
+	// Not enough samples to consider the field constant.
+	assert.False(t, i1.Constant)
+
+	open := fields["mutate5.flags"]
+	require.NotNil(t, open)
+	assert.True(t, open.Constant)
+	assert.Equal(t, []string{"0xcdcdcdcd"}, open.UnusedFlags)
+
+	// Struct fields are accounted per struct type.
+	assert.Equal(t, []Value{{0x1, 2}}, fields["syz_union0_struct.f"].Values)
+	assert.Equal(t, []Value{{0x2, 1}}, fields["syz_union0.f2"].Values)
+
+	require.Len(t, rep.Unions, 1)
+	union := rep.Unions[0]
+	assert.Equal(t, "syz_union0", union.Name)
+	assert.Equal(t, 2, union.Count)
+	assert.Equal(t, 1, union.Unused)
+	assert.Equal(t, []Option{{"f0", 1}, {"f1", 0}, {"f2", 1}}, union.Options)
+	for _, field := range rep.Fields {
+		assert.False(t, strings.HasPrefix(field.Name, "mutate5.filename"), field.Name)
+	}
+
+	var problems []string
+	for _, field := range rep.Problems().Fields {
+		problems = append(problems, field.Name)
+	}
+	assert.Equal(t, []string{"mutate5.flags", "mutate_flags.flags"}, problems)
+}
@@ -383,27 +383,30 @@ func (v ExecEnv) String() string {
 type ExecFlag uint64
 
 const (
-	ExecFlagCollectSignal ExecFlag = 1
-	ExecFlagCollectCover  ExecFlag = 2
-	ExecFlagDedupCover    ExecFlag = 4
-	ExecFlagCollectComps  ExecFlag = 8
-	ExecFlagThreaded      ExecFlag = 16
+	ExecFlagCollectSignal  ExecFlag = 1
+	ExecFlagCollectCover   ExecFlag = 2
+	ExecFlagDedupCover     ExecFlag = 4
+	ExecFlagCollectComps   ExecFlag = 8
+	ExecFlagThreaded       ExecFlag = 16
+	ExecFlagCollectCopyout ExecFlag = 32
 )
 
 var EnumNamesExecFlag = map[ExecFlag]string{
-	ExecFlagCollectSignal: "CollectSignal",
-	ExecFlagCollectCover:  "CollectCover",
-	ExecFlagDedupCover:    "DedupCover",
-	ExecFlagCollectComps:  "CollectComps",
-	ExecFlagThreaded:      "Threaded",
+	ExecFlagCollectSignal:  "CollectSignal",
+	ExecFlagCollectCover:   "CollectCover",
+	ExecFlagDedupCover:     "DedupCover",
+	ExecFlagCollectComps:   "CollectComps",
+	ExecFlagThreaded:       "Threaded",
+	ExecFlagCollectCopyout: "CollectCopyout",
 }
 
 var EnumValuesExecFlag = map[string]ExecFlag{
-	"CollectSignal": ExecFlagCollectSignal,
-	"CollectCover":  ExecFlagCollectCover,
-	"DedupCover":    ExecFlagDedupCover,
-	"CollectComps":  ExecFlagCollectComps,
-	"Threaded":      ExecFlagThreaded,
+	"CollectSignal":  ExecFlagCollectSignal,
+	"CollectCover":   ExecFlagCollectCover,
+	"DedupCover":     ExecFlagDedupCover,
+	"CollectComps":   ExecFlagCollectComps,
+	"Threaded":       ExecFlagThreaded,
+	"CollectCopyout": ExecFlagCollectCopyout,
 }
 
 func (v ExecFlag) String() string {
@@ -2546,11 +2549,12 @@ func ExecutingMessageRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 }
 
 type CallInfoRawT struct {
-	Flags  CallFlag          `json:"flags"`
-	Error  int32             `json:"error"`
-	Signal []uint64          `json:"signal"`
-	Cover  []uint64          `json:"cover"`
-	Comps  []*ComparisonRawT `json:"comps"`
+	Flags   CallFlag          `json:"flags"`
+	Error   int32             `json:"error"`
+	Signal  []uint64          `json:"signal"`
+	Cover   []uint64          `json:"cover"`
+	Comps   []*ComparisonRawT `json:"comps"`
+	Copyout []uint64          `json:"copyout"`
 }
 
 func (t *CallInfoRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
@@ -2584,12 +2588,22 @@ func (t *CallInfoRawT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 		}
 		compsOffset = builder.EndVector(compsLength)
 	}
+	copyoutOffset := flatbuffers.UOffsetT(0)
+	if t.Copyout != nil {
+		copyoutLength := len(t.Copyout)
+		CallInfoRawStartCopyoutVector(builder, copyoutLength)
+		for j := copyoutLength - 1; j >= 0; j-- {
+			builder.PrependUint64(t.Copyout[j])
+		}
+		copyoutOffset = builder.EndVector(copyoutLength)
+	}
 	CallInfoRawStart(builder)
 	CallInfoRawAddFlags(builder, t.Flags)
 	CallInfoRawAddError(builder, t.Error)
 	CallInfoRawAddSignal(builder, signalOffset)
 	CallInfoRawAddCover(builder, coverOffset)
 	CallInfoRawAddComps(builder, compsOffset)
+	CallInfoRawAddCopyout(builder, copyoutOffset)
 	return CallInfoRawEnd(builder)
 }
 
@@ -2613,6 +2627,11 @@ func (rcv *CallInfoRaw) UnPackTo(t *CallInfoRawT) {
 		rcv.Comps(&x, j)
 		t.Comps[j] = x.UnPack()
 	}
+	copyoutLength := rcv.CopyoutLength()
+	t.Copyout = make([]uint64, copyoutLength)
+	for j := 0; j < copyoutLength; j++ {
+		t.Copyout[j] = rcv.Copyout(j)
+	}
 }
 
 func (rcv *CallInfoRaw) UnPack() *CallInfoRawT {
@@ -2746,8 +2765,34 @@ func (rcv *CallInfoRaw) CompsLength() int {
 	return 0
 }
 
+func (rcv *CallInfoRaw) Copyout(j int) uint64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetUint64(a + flatbuffers.UOffsetT(j*8))
+	}
+	return 0
+}
+
+func (rcv *CallInfoRaw) CopyoutLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *CallInfoRaw) MutateCopyout(j int, n uint64) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(14))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateUint64(a+flatbuffers.UOffsetT(j*8), n)
+	}
+	return false
+}
+
 func CallInfoRawStart(builder *flatbuffers.Builder) {
-	builder.StartObject(5)
+	builder.StartObject(6)
 }
 func CallInfoRawAddFlags(builder *flatbuffers.Builder, flags CallFlag) {
 	builder.PrependByteSlot(0, byte(flags), 0)
@@ -2773,6 +2818,12 @@ func CallInfoRawAddComps(builder *flatbuffers.Builder, comps flatbuffers.UOffset
 func CallInfoRawStartCompsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(32, numElems, 8)
 }
+func CallInfoRawAddCopyout(builder *flatbuffers.Builder, copyout flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(5, flatbuffers.UOffsetT(copyout), 0)
+}
+func CallInfoRawStartCopyoutVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(8, numElems, 8)
+}
 func CallInfoRawEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -632,49 +632,34 @@ enum class ExecFlag : uint64_t {
   DedupCover = 4ULL,
   CollectComps = 8ULL,
   Threaded = 16ULL,
+  CollectCopyout = 32ULL,
   NONE = 0,
-  ANY = 31ULL
+  ANY = 63ULL
 };
 FLATBUFFERS_DEFINE_BITMASK_OPERATORS(ExecFlag, uint64_t)
 
-inline const ExecFlag (&EnumValuesExecFlag())[5] {
+inline const ExecFlag (&EnumValuesExecFlag())[6] {
   static const ExecFlag values[] = {
     ExecFlag::CollectSignal,
     ExecFlag::CollectCover,
     ExecFlag::DedupCover,
     ExecFlag::CollectComps,
-    ExecFlag::Threaded
+    ExecFlag::Threaded,
+    ExecFlag::CollectCopyout
   };
   return values;
 }
 
-inline const char * const *EnumNamesExecFlag() {
-  static const char * const names[17] = {
-    "CollectSignal",
-    "CollectCover",
-    "",
-    "DedupCover",
-    "",
-    "",
-    "",
-    "CollectComps",
-    "",
-    "",
-    "",
-    "",
-    "",
-    "",
-    "",
-    "Threaded",
-    nullptr
-  };
-  return names;
-}
-
 inline const char *EnumNameExecFlag(ExecFlag e) {
-  if (flatbuffers::IsOutRange(e, ExecFlag::CollectSignal, ExecFlag::Threaded)) return "";
-  const size_t index = static_cast<size_t>(e) - static_cast<size_t>(ExecFlag::CollectSignal);
-  return EnumNamesExecFlag()[index];
+  switch (e) {
+    case ExecFlag::CollectSignal: return "CollectSignal";
+    case ExecFlag::CollectCover: return "CollectCover";
+    case ExecFlag::DedupCover: return "DedupCover";
+    case ExecFlag::CollectComps: return "CollectComps";
+    case ExecFlag::Threaded: return "Threaded";
+    case ExecFlag::CollectCopyout: return "CollectCopyout";
+    default: return "";
+  }
 }
 
 enum class CallFlag : uint8_t {
@@ -2218,6 +2203,7 @@ struct CallInfoRawT : public flatbuffers::NativeTable {
   std::vector<uint64_t> signal{};
   std::vector<uint64_t> cover{};
   std::vector<rpc::ComparisonRaw> comps{};
+  std::vector<uint64_t> copyout{};
 };
 
 struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -2228,7 +2214,8 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_ERROR = 6,
     VT_SIGNAL = 8,
     VT_COVER = 10,
-    VT_COMPS = 12
+    VT_COMPS = 12,
+    VT_COPYOUT = 14
   };
   rpc::CallFlag flags() const {
     return static_cast<rpc::CallFlag>(GetField<uint8_t>(VT_FLAGS, 0));
@@ -2245,6 +2232,9 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   const flatbuffers::Vector<const rpc::ComparisonRaw *> *comps() const {
     return GetPointer<const flatbuffers::Vector<const rpc::ComparisonRaw *> *>(VT_COMPS);
   }
+  const flatbuffers::Vector<uint64_t> *copyout() const {
+    return GetPointer<const flatbuffers::Vector<uint64_t> *>(VT_COPYOUT);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<uint8_t>(verifier, VT_FLAGS, 1) &&
@@ -2255,6 +2245,8 @@ struct CallInfoRaw FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            verifier.VerifyVector(cover()) &&
            VerifyOffset(verifier, VT_COMPS) &&
            verifier.VerifyVector(comps()) &&
+           VerifyOffset(verifier, VT_COPYOUT) &&
+           verifier.VerifyVector(copyout()) &&
            verifier.EndTable();
   }
   CallInfoRawT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -2281,6 +2273,9 @@ struct CallInfoRawBuilder {
   void add_comps(flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps) {
     fbb_.AddOffset(CallInfoRaw::VT_COMPS, comps);
   }
+  void add_copyout(flatbuffers::Offset<flatbuffers::Vector<uint64_t>> copyout) {
+    fbb_.AddOffset(CallInfoRaw::VT_COPYOUT, copyout);
+  }
   explicit CallInfoRawBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -2298,8 +2293,10 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(
     int32_t error = 0,
     flatbuffers::Offset<flatbuffers::Vector<uint64_t>> signal = 0,
     flatbuffers::Offset<flatbuffers::Vector<uint64_t>> cover = 0,
-    flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps = 0) {
+    flatbuffers::Offset<flatbuffers::Vector<const rpc::ComparisonRaw *>> comps = 0,
+    flatbuffers::Offset<flatbuffers::Vector<uint64_t>> copyout = 0) {
   CallInfoRawBuilder builder_(_fbb);
+  builder_.add_copyout(copyout);
   builder_.add_comps(comps);
   builder_.add_cover(cover);
   builder_.add_signal(signal);
@@ -2314,17 +2311,20 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRawDirect(
     int32_t error = 0,
     const std::vector<uint64_t> *signal = nullptr,
     const std::vector<uint64_t> *cover = nullptr,
-    const std::vector<rpc::ComparisonRaw> *comps = nullptr) {
+    const std::vector<rpc::ComparisonRaw> *comps = nullptr,
+    const std::vector<uint64_t> *copyout = nullptr) {
   auto signal__ = signal ? _fbb.CreateVector<uint64_t>(*signal) : 0;
   auto cover__ = cover ? _fbb.CreateVector<uint64_t>(*cover) : 0;
   auto comps__ = comps ? _fbb.CreateVectorOfStructs<rpc::ComparisonRaw>(*comps) : 0;
+  auto copyout__ = copyout ? _fbb.CreateVector<uint64_t>(*copyout) : 0;
   return rpc::CreateCallInfoRaw(
       _fbb,
       flags,
       error,
       signal__,
       cover__,
-      comps__);
+      comps__,
+      copyout__);
 }
 
 flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(flatbuffers::FlatBufferBuilder &_fbb, const CallInfoRawT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
@@ -3501,6 +3501,7 @@ inline void CallInfoRaw::UnPackTo(CallInfoRawT *_o, const flatbuffers::resolver_
   { auto _e = signal(); if (_e) { _o->signal.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->signal[_i] = _e->Get(_i); } } }
   { auto _e = cover(); if (_e) { _o->cover.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->cover[_i] = _e->Get(_i); } } }
   { auto _e = comps(); if (_e) { _o->comps.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->comps[_i] = *_e->Get(_i); } } }
+  { auto _e = copyout(); if (_e) { _o->copyout.resize(_e->size()); for (flatbuffers::uoffset_t _i = 0; _i < _e->size(); _i++) { _o->copyout[_i] = _e->Get(_i); } } }
 }
 
 inline flatbuffers::Offset<CallInfoRaw> CallInfoRaw::Pack(flatbuffers::FlatBufferBuilder &_fbb, const CallInfoRawT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3516,13 +3517,15 @@ inline flatbuffers::Offset<CallInfoRaw> CreateCallInfoRaw(flatbuffers::FlatBuffe
   auto _signal = _o->signal.size() ? _fbb.CreateVector(_o->signal) : 0;
   auto _cover = _o->cover.size() ? _fbb.CreateVector(_o->cover) : 0;
   auto _comps = _o->comps.size() ? _fbb.CreateVectorOfStructs(_o->comps) : 0;
+  auto _copyout = _o->copyout.size() ? _fbb.CreateVector(_o->copyout) : 0;
   return rpc::CreateCallInfoRaw(
       _fbb,
       _flags,
       _error,
       _signal,
       _cover,
-      _comps);
+      _comps,
+      _copyout);
 }
 
 inline ProgInfoRawT::ProgInfoRawT(const ProgInfoRawT &o)
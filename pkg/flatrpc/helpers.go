@@ -70,6 +70,7 @@ func (ci *CallInfo) clone() *CallInfo {
 	ret.Signal = slices.Clone(ret.Signal)
 	ret.Cover = slices.Clone(ret.Cover)
 	ret.Comps = slices.Clone(ret.Comps)
+	ret.Copyout = slices.Clone(ret.Copyout)
 	return &ret
 }
 
@@ -243,15 +243,18 @@ func (fuzzer *Fuzzer) handleCallInfo(req *queue.Request, info *flatrpc.CallInfo,
 	if info == nil || info.Flags&flatrpc.CallFlagCoverageOverflow == 0 {
 		return
 	}
-	syscallIdx := len(fuzzer.Syscalls) - 1
+	syscallIdx, syscallName := len(fuzzer.Syscalls)-1, "extra"
 	if call != -1 {
 		syscallIdx = req.Prog.Calls[call].Meta.ID
+		syscallName = req.Prog.Calls[call].Meta.Name
 	}
 	stat := &fuzzer.Syscalls[syscallIdx]
 	if req.ExecOpts.ExecFlags&flatrpc.ExecFlagCollectComps != 0 {
 		stat.CompsOverflows.Add(1)
+		fuzzer.statCoverOverflows.With(syscallName, "comps").Add(1)
 	} else {
 		stat.CoverOverflows.Add(1)
+		fuzzer.statCoverOverflows.With(syscallName, "cover").Add(1)
 	}
 }
 
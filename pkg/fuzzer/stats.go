@@ -33,6 +33,7 @@ type Stats struct {
 	statExecHint            *stat.Val
 	statExecSeed            *stat.Val
 	statExecCollide         *stat.Val
+	statCoverOverflows      *stat.Family
 }
 
 type SyscallStats struct {
@@ -80,5 +81,7 @@ func newStats(target *prog.Target) Stats {
 			stat.Rate{}, stat.StackedGraph("exec")),
 		statExecCollide: stat.New("exec collide", "Executions of programs in collide mode",
 			stat.Rate{}, stat.StackedGraph("exec")),
+		statCoverOverflows: stat.NewFamily("cover overflows", "Coverage/comparisons buffer overflows per syscall",
+			[]string{"syscall", "buffer"}, stat.Rate{}),
 	}
 }
@@ -0,0 +1,106 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	key := func(s string) string { return s }
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		page, err := Paginate(items, key, cursor, 2)
+		assert.NoError(t, err)
+		got = append(got, page.Items...)
+		if page.NextCursor == "" {
+			assert.Equal(t, 2, pages)
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, items, got)
+
+	// The cursor stays valid after items are added and removed.
+	page, err := Paginate(items, key, "", 2)
+	assert.NoError(t, err)
+	page, err = Paginate([]string{"a", "aa", "c", "d"}, key, page.NextCursor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, page.Items)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = Paginate(nil, key, "", 0)
+	assert.NoError(t, err)
+	assert.NotNil(t, page.Items)
+
+	_, err = Paginate(items, key, "!!!", 2)
+	assert.Error(t, err)
+}
+
+func TestParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?limit=10&repro=true&since=2024-05-01&until=2024-05-02T10:00:00Z&title=foo.*", nil)
+	p := NewParams(r)
+	assert.Equal(t, 10, p.Int("limit"))
+	assert.Equal(t, true, *p.Bool("repro"))
+	assert.Nil(t, p.Bool("missing"))
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), p.Time("since"))
+	assert.Equal(t, time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), p.Time("until"))
+	assert.True(t, p.Regexp("title").MatchString("foobar"))
+	assert.NoError(t, p.Err())
+
+	r = httptest.NewRequest("GET", "/?limit=x&title=(", nil)
+	p = NewParams(r)
+	p.Int("limit")
+	p.Regexp("title")
+	assert.EqualError(t, p.Err(), `bad limit parameter "x": strconv.Atoi: parsing "x": invalid syntax`)
+}
+
+type testItem struct {
+	Name     string            `json:"name"`
+	Time     time.Time         `json:"time"`
+	Data     []byte            `json:"data,omitempty"`
+	Children []*testItem       `json:"children"`
+	Attrs    map[string]uint64 `json:"attrs"`
+	Ignored  int               `json:"-"`
+	private  int
+	testEmbedded
+}
+
+type testEmbedded struct {
+	Extra float64
+}
+
+func TestSpec(t *testing.T) {
+	spec := NewSpec("test", "1")
+	spec.Get("/items", "list items", PageParams, Page[testItem]{})
+	data, err := json.Marshal(spec)
+	assert.NoError(t, err)
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal(data, &got))
+
+	schemas := spec.Components.Schemas
+	assert.Contains(t, schemas, "Page_testItem")
+	assert.Contains(t, schemas, "Error")
+	item := schemas["testItem"]
+	assert.NotNil(t, item)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, item.Properties["time"])
+	assert.Equal(t, &Schema{Type: "string", Format: "byte"}, item.Properties["data"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/testItem"}},
+		item.Properties["children"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer"}},
+		item.Properties["attrs"])
+	assert.Equal(t, &Schema{Type: "number"}, item.Properties["Extra"])
+	assert.NotContains(t, item.Properties, "Ignored")
+	assert.NotContains(t, item.Properties, "private")
+	params := spec.Paths["/items"].Get.Parameters
+	assert.Len(t, params, 2)
+	assert.Equal(t, "query", params[0].In)
+}
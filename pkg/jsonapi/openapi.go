@@ -0,0 +1,220 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package jsonapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Spec is an OpenAPI 3.0 document. Response schemas are generated from Go types
+// (using the same field names as encoding/json).
+type Spec struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       SpecInfo              `json:"info"`
+	Paths      map[string]*SpecPath  `json:"paths"`
+	Components SpecComponents        `json:"components"`
+	types      map[reflect.Type]bool `json:"-"`
+}
+
+type SpecInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type SpecPath struct {
+	Get *SpecOperation `json:"get,omitempty"`
+}
+
+type SpecOperation struct {
+	Summary    string                   `json:"summary,omitempty"`
+	Parameters []SpecParameter          `json:"parameters,omitempty"`
+	Responses  map[string]*SpecResponse `json:"responses"`
+}
+
+type SpecParameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type SpecResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]*SpecContent `json:"content,omitempty"`
+}
+
+type SpecContent struct {
+	Schema *Schema `json:"schema"`
+}
+
+type SpecComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Param is a parameter of an API endpoint.
+type Param struct {
+	Name        string
+	In          string // query (default) or path
+	Type        string // string, integer, boolean
+	Description string
+	Required    bool
+}
+
+func NewSpec(title, version string) *Spec {
+	return &Spec{
+		OpenAPI: "3.0.3",
+		Info: SpecInfo{
+			Title:   title,
+			Version: version,
+		},
+		Paths: make(map[string]*SpecPath),
+		Components: SpecComponents{
+			Schemas: make(map[string]*Schema),
+		},
+		types: make(map[reflect.Type]bool),
+	}
+}
+
+// Get adds a GET endpoint that returns a JSON-encoded value of the response type.
+func (spec *Spec) Get(path, summary string, params []Param, response any) {
+	op := &SpecOperation{
+		Summary: summary,
+		Responses: map[string]*SpecResponse{
+			"200": {
+				Description: "OK",
+				Content: map[string]*SpecContent{
+					"application/json": {Schema: spec.schema(reflect.TypeOf(response))},
+				},
+			},
+			"default": {
+				Description: "error",
+				Content: map[string]*SpecContent{
+					"application/json": {Schema: spec.schema(reflect.TypeOf(Error{}))},
+				},
+			},
+		},
+	}
+	for _, param := range params {
+		in := param.In
+		if in == "" {
+			in = "query"
+		}
+		op.Parameters = append(op.Parameters, SpecParameter{
+			Name:        param.Name,
+			In:          in,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: param.Type},
+		})
+	}
+	spec.Paths[path] = &SpecPath{Get: op}
+}
+
+func (spec *Spec) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, spec)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (spec *Spec) schema(typ reflect.Type) *Schema {
+	nullable := false
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+		nullable = true
+	}
+	switch {
+	case typ == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case typ.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		return &Schema{Type: "integer", Nullable: nullable}
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		return &Schema{Type: "number", Nullable: nullable}
+	case typ.Kind() == reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		return &Schema{Type: "string", Format: "byte", Nullable: nullable}
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		return &Schema{Type: "array", Items: spec.schema(typ.Elem())}
+	case typ.Kind() == reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: spec.schema(typ.Elem())}
+	case typ.Kind() == reflect.Struct && typ.Name() == "":
+		return spec.structSchema(typ)
+	case typ.Kind() == reflect.Struct:
+		name := schemaName(typ)
+		if !spec.types[typ] {
+			// Register the name before recursing, so that recursive types terminate.
+			spec.types[typ] = true
+			spec.Components.Schemas[name] = spec.structSchema(typ)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// Interfaces and other types that we can't describe.
+		return &Schema{}
+	}
+}
+
+func (spec *Spec) structSchema(typ reflect.Type) *Schema {
+	res := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if tagName, _, _ := strings.Cut(tag, ","); tagName != "" {
+				name = tagName
+			}
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for name, prop := range spec.structSchema(embedded).Properties {
+					res.Properties[name] = prop
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		res.Properties[name] = spec.schema(field.Type)
+	}
+	return res
+}
+
+var schemaPackageRe = regexp.MustCompile(`[\w./-]*\.`)
+
+// schemaName returns type name suitable for use in component references.
+// Package paths are stripped from instantiated generic types (e.g. Page[pkg/foo.Bar] becomes Page_Bar).
+func schemaName(typ reflect.Type) string {
+	name := schemaPackageRe.ReplaceAllString(typ.Name(), "")
+	return strings.NewReplacer("[", "_", "]", "", ",", "_", "*", "").Replace(name)
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package jsonapi contains common parts of the read-only JSON APIs served by syz-manager and the dashboard:
+// cursor-based pagination, request parameter parsing, error responses and OpenAPI schema generation.
+package jsonapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Page is a single page of a list response.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor needs to be passed as the cursor parameter to fetch the next page.
+	// It's empty for the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Paginate returns limit items that follow the cursor.
+// Items must be sorted by key and keys must be unique. Since the cursor refers to the last returned key
+// rather than to an index, pages stay consistent if items are added or removed between requests.
+func Paginate[T any](items []T, key func(T) string, cursor string, limit int) (*Page[T], error) {
+	start := 0
+	if cursor != "" {
+		last, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("bad cursor %q", cursor)
+		}
+		start = sort.Search(len(items), func(i int) bool {
+			return key(items[i]) > string(last)
+		})
+	}
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	end := min(start+limit, len(items))
+	page := &Page[T]{
+		Items: items[start:end],
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if end < len(items) {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(key(items[end-1])))
+	}
+	return page, nil
+}
+
+// Params simplifies parsing of request parameters. Parsing functions return zero values
+// for missing parameters, the first parsing error is returned by Err.
+type Params struct {
+	r   *http.Request
+	err error
+}
+
+func NewParams(r *http.Request) *Params {
+	return &Params{r: r}
+}
+
+func (p *Params) Err() error {
+	return p.err
+}
+
+func (p *Params) String(name string) string {
+	return p.r.FormValue(name)
+}
+
+func (p *Params) Int(name string) int {
+	val := p.r.FormValue(name)
+	if val == "" {
+		return 0
+	}
+	res, err := strconv.Atoi(val)
+	if err != nil {
+		p.fail(name, val, err)
+	}
+	return res
+}
+
+// Bool returns nil if the parameter is not present.
+func (p *Params) Bool(name string) *bool {
+	val := p.r.FormValue(name)
+	if val == "" {
+		return nil
+	}
+	res, err := strconv.ParseBool(val)
+	if err != nil {
+		p.fail(name, val, err)
+		return nil
+	}
+	return &res
+}
+
+// Time accepts RFC 3339 timestamps and dates in the 2006-01-02 format.
+func (p *Params) Time(name string) time.Time {
+	val := p.r.FormValue(name)
+	if val == "" {
+		return time.Time{}
+	}
+	res, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		res, err = time.Parse(time.DateOnly, val)
+	}
+	if err != nil {
+		p.fail(name, val, err)
+	}
+	return res
+}
+
+// Regexp returns nil if the parameter is not present.
+func (p *Params) Regexp(name string) *regexp.Regexp {
+	val := p.r.FormValue(name)
+	if val == "" {
+		return nil
+	}
+	res, err := regexp.Compile(val)
+	if err != nil {
+		p.fail(name, val, err)
+	}
+	return res
+}
+
+func (p *Params) fail(name, val string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("bad %v parameter %q: %w", name, val, err)
+	}
+}
+
+// PageParams describes the parameters used by Paginate for the OpenAPI schema.
+var PageParams = []Param{
+	{Name: "cursor", Type: "string", Description: "next_cursor value from the previous page"},
+	{Name: "limit", Type: "integer", Description: fmt.Sprintf("max number of items (default %v, max %v)",
+		DefaultLimit, MaxLimit)},
+}
+
+type Error struct {
+	Error string `json:"error"`
+}
+
+// WriteJSON writes the value as an indented JSON response.
+func WriteJSON(w http.ResponseWriter, v any) {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func WriteError(w http.ResponseWriter, code int, err error) {
+	data, _ := json.Marshal(Error{Error: err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
@@ -19,6 +19,46 @@ import (
 // If maxPredRuns is non-zero, minimization will stop after the specified number of runs.
 func (kconf *KConfig) Minimize(base, full *ConfigFile, pred func(*ConfigFile) (bool, error),
 	maxSteps int, dt debugtracer.DebugTracer) (*ConfigFile, error) {
+	res, err := kconf.minimize(base, full, pred, maxSteps, dt)
+	if err != nil {
+		return nil, err
+	}
+	return res.Config, nil
+}
+
+// BisectResult is the result of config bisection.
+type BisectResult struct {
+	// Config is the base config with Enabled configs (and all non-tristate configs from the full config).
+	Config *ConfigFile
+	// Culprits is the minimal set of configs enabled in the full config, but not in the base one,
+	// that make the predicate true.
+	Culprits []string
+	// Enabled is Culprits plus their dependencies that are also missing in the base config.
+	Enabled []string
+}
+
+// Bisect identifies the configs that are enabled in full, but not in base, and whose enabling
+// makes the predicate true. Unlike Minimize, it requires the predicate to be false for base
+// and true for full, and does not limit the number of predicate runs.
+func (kconf *KConfig) Bisect(base, full *ConfigFile, pred func(*ConfigFile) (bool, error),
+	dt debugtracer.DebugTracer) (*BisectResult, error) {
+	dt.Log("kconfig bisection: checking the full config")
+	if ok, err := pred(full); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("the predicate does not hold for the full config")
+	}
+	dt.Log("kconfig bisection: checking the base config")
+	if ok, err := pred(base); err != nil {
+		return nil, err
+	} else if ok {
+		return nil, fmt.Errorf("the predicate already holds for the base config")
+	}
+	return kconf.minimize(base, full, pred, 0, dt)
+}
+
+func (kconf *KConfig) minimize(base, full *ConfigFile, pred func(*ConfigFile) (bool, error),
+	maxSteps int, dt debugtracer.DebugTracer) (*BisectResult, error) {
 	diff, other := kconf.missingLeafConfigs(base, full)
 	dt.Log("kconfig minimization: base=%v full=%v leaves diff=%v", len(base.Configs), len(full.Configs), len(diff))
 
@@ -61,7 +101,11 @@ func (kconf *KConfig) Minimize(base, full *ConfigFile, pred func(*ConfigFile) (b
 		dt.Log("minimized to %d configs; suspects: %v", len(result), suspects)
 		kconf.writeSuspects(dt, suspects)
 	}
-	return config, nil
+	return &BisectResult{
+		Config:   config,
+		Culprits: result,
+		Enabled:  suspects,
+	}, nil
 }
 
 func (kconf *KConfig) missingConfigs(base, full *ConfigFile) (tristate []string, other []*Config) {
@@ -132,3 +132,65 @@ CONFIG_D=y
 		})
 	}
 }
+
+func TestBisect(t *testing.T) {
+	const (
+		kconfig = `
+mainmenu "test"
+config A
+config B
+config C
+config D
+
+menuconfig NET_FEATURE
+	bool "Network feature"
+
+config NET_DRIVER
+	tristate "Network driver"
+	depends on NET_FEATURE
+`
+		baseConfig = `
+CONFIG_A=y
+`
+		fullConfig = `
+CONFIG_A=y
+CONFIG_B=y
+CONFIG_C=y
+CONFIG_D=y
+CONFIG_NET_FEATURE=y
+CONFIG_NET_DRIVER=y
+`
+	)
+	kconf, err := ParseData(targets.Get("linux", "amd64"), []byte(kconfig), "kconf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := ParseConfigData([]byte(baseConfig), "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := ParseConfigData([]byte(fullConfig), "full")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := kconf.Bisect(base, full, func(cf *ConfigFile) (bool, error) {
+		return cf.Value("NET_DRIVER") == Yes && cf.Value("D") == Yes, nil
+	}, &debugtracer.TestTracer{T: t})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"D", "NET_DRIVER"}, res.Culprits)
+	assert.Equal(t, []string{"D", "NET_DRIVER", "NET_FEATURE"}, res.Enabled)
+	assert.Equal(t, No, res.Config.Value("B"))
+	assert.Equal(t, Yes, res.Config.Value("NET_FEATURE"))
+
+	// The predicate must be false for the base config and true for the full one.
+	_, err = kconf.Bisect(base, full, func(cf *ConfigFile) (bool, error) {
+		return true, nil
+	}, &debugtracer.TestTracer{T: t})
+	assert.Error(t, err)
+	_, err = kconf.Bisect(base, full, func(cf *ConfigFile) (bool, error) {
+		return false, nil
+	}, &debugtracer.TestTracer{T: t})
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/subsystem"
+	_ "github.com/google/syzkaller/pkg/subsystem/lists"
+)
+
+// Subsystems with fewer coverage points are too small to be worth a focus area,
+// and their coverage ratio is too noisy.
+const autoFocusMinPCs = 100
+
+// SubsystemCover is the coverage of a single kernel subsystem.
+type SubsystemCover struct {
+	Subsystem string
+	Covered   int
+	Total     int
+	pcs       map[uint64]struct{} // coverage points in the form reported by KCOV
+}
+
+func (sc SubsystemCover) Ratio() float64 {
+	if sc.Total == 0 {
+		return 0
+	}
+	return float64(sc.Covered) / float64(sc.Total)
+}
+
+// AutoFocusArea is a focus area that was automatically created for an under-covered subsystem.
+type AutoFocusArea struct {
+	SubsystemCover
+	Weight float64
+}
+
+func (area AutoFocusArea) FocusArea() corpus.FocusArea {
+	return corpus.FocusArea{
+		Name:     area.Subsystem,
+		CoverPCs: area.pcs,
+		Weight:   area.Weight,
+	}
+}
+
+// AutoFocus periodically directs fuzzing towards the least covered subsystems
+// (see mgrconfig.Experimental.AutoFocus).
+type AutoFocus struct {
+	cfg  *mgrconfig.Config
+	list []*subsystem.Subsystem
+
+	mu         sync.Mutex
+	subsystems []*SubsystemCover // lazily built from the report generator
+}
+
+func NewAutoFocus(cfg *mgrconfig.Config) (*AutoFocus, error) {
+	list := subsystem.GetList(cfg.TargetOS)
+	if len(list) == 0 {
+		return nil, fmt.Errorf("there's no subsystem list for %v", cfg.TargetOS)
+	}
+	return &AutoFocus{cfg: cfg, list: list}, nil
+}
+
+// Update recalculates the per-subsystem coverage and installs the resulting focus areas into the corpus.
+// The automatic areas in total get the same weight as the configured focus areas
+// (or as the rest of the corpus, if there are none).
+func (af *AutoFocus) Update(rg *cover.ReportGenerator, corpusObj *corpus.Corpus) []AutoFocusArea {
+	af.mu.Lock()
+	defer af.mu.Unlock()
+	if af.subsystems == nil {
+		af.subsystems = subsystemPCs(rg, af.cfg, af.list)
+		log.Logf(0, "auto focus: %v subsystems have coverage points", len(af.subsystems))
+	}
+	subsystems := SubsystemsCoverage(af.subsystems, corpusObj.Cover())
+	totalWeight := 0.0
+	for _, area := range af.cfg.Experimental.FocusAreas {
+		totalWeight += area.Weight
+	}
+	if totalWeight == 0 {
+		totalWeight = 1.0
+	}
+	areas := ChooseAutoFocusAreas(subsystems, af.cfg.Experimental.AutoFocusSubsystems, totalWeight)
+	var focusAreas []corpus.FocusArea
+	for _, area := range areas {
+		log.Logf(1, "auto focus: %v: %v/%v PCs, weight %.3f",
+			area.Subsystem, area.Covered, area.Total, area.Weight)
+		focusAreas = append(focusAreas, area.FocusArea())
+	}
+	corpusObj.SetAutoFocusAreas(focusAreas)
+	return areas
+}
+
+// subsystemPCs attributes coverage points of the kernel to subsystems by the source file path.
+func subsystemPCs(rg *cover.ReportGenerator, cfg *mgrconfig.Config,
+	list []*subsystem.Subsystem) []*SubsystemCover {
+	matcher := subsystem.MakePathMatcher(list)
+	bySubsystem := make(map[*subsystem.Subsystem]*SubsystemCover)
+	for _, unit := range rg.Units {
+		if len(unit.PCs) == 0 {
+			continue
+		}
+		for _, s := range matcher.Match(unit.Name) {
+			sc := bySubsystem[s]
+			if sc == nil {
+				sc = &SubsystemCover{
+					Subsystem: s.Name,
+					pcs:       make(map[uint64]struct{}),
+				}
+				bySubsystem[s] = sc
+			}
+			for _, pc := range unit.PCs {
+				sc.pcs[backend.NextInstructionPC(cfg.SysTarget, cfg.Type, pc)] = struct{}{}
+			}
+		}
+	}
+	var ret []*SubsystemCover
+	for _, sc := range bySubsystem {
+		sc.Total = len(sc.pcs)
+		ret = append(ret, sc)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Subsystem < ret[j].Subsystem
+	})
+	return ret
+}
+
+// SubsystemsCoverage calculates how much of each subsystem is covered by corpusCover.
+func SubsystemsCoverage(subsystems []*SubsystemCover, corpusCover []uint64) []SubsystemCover {
+	var ret []SubsystemCover
+	for _, sc := range subsystems {
+		res := *sc
+		res.Covered = 0
+		for _, pc := range corpusCover {
+			if _, ok := sc.pcs[pc]; ok {
+				res.Covered++
+			}
+		}
+		ret = append(ret, res)
+	}
+	return ret
+}
+
+// ChooseAutoFocusAreas selects up to count least covered subsystems that are reachable,
+// i.e. have at least some coverage. The less a subsystem is covered, the bigger weight it gets,
+// the sum of all weights is totalWeight.
+func ChooseAutoFocusAreas(subsystems []SubsystemCover, count int, totalWeight float64) []AutoFocusArea {
+	var candidates []SubsystemCover
+	for _, sc := range subsystems {
+		if sc.Covered == 0 || sc.Covered == sc.Total || sc.Total < autoFocusMinPCs {
+			continue
+		}
+		candidates = append(candidates, sc)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ri, rj := candidates[i].Ratio(), candidates[j].Ratio()
+		if ri != rj {
+			return ri < rj
+		}
+		return candidates[i].Subsystem < candidates[j].Subsystem
+	})
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+	sum := 0.0
+	for _, sc := range candidates {
+		sum += 1 - sc.Ratio()
+	}
+	var ret []AutoFocusArea
+	for _, sc := range candidates {
+		ret = append(ret, AutoFocusArea{
+			SubsystemCover: sc,
+			Weight:         totalWeight * (1 - sc.Ratio()) / sum,
+		})
+	}
+	return ret
+}
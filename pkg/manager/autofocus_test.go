@@ -0,0 +1,89 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/cover/backend"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/subsystem"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoFocusAreas(t *testing.T) {
+	cfg := &mgrconfig.Config{
+		Type: "qemu",
+		Derived: mgrconfig.Derived{
+			SysTarget: targets.Get(targets.Linux, targets.AMD64),
+		},
+	}
+	makeUnit := func(name string, from, count uint64) *backend.CompileUnit {
+		unit := &backend.CompileUnit{ObjectUnit: backend.ObjectUnit{Name: name}}
+		for pc := from; pc < from+count; pc++ {
+			unit.PCs = append(unit.PCs, pc)
+		}
+		return unit
+	}
+	rg := &cover.ReportGenerator{
+		Impl: &backend.Impl{
+			Units: []*backend.CompileUnit{
+				makeUnit("net/socket.c", 0x1000, 200),
+				makeUnit("fs/open.c", 0x2000, 300),
+				makeUnit("mm/slab.c", 0x3000, 100),
+				makeUnit("drivers/tiny.c", 0x4000, 10),
+			},
+		},
+	}
+	list := []*subsystem.Subsystem{
+		{Name: "net", PathRules: []subsystem.PathRule{{IncludeRegexp: "^net/"}}},
+		{Name: "fs", PathRules: []subsystem.PathRule{{IncludeRegexp: "^fs/"}}},
+		{Name: "mm", PathRules: []subsystem.PathRule{{IncludeRegexp: "^mm/"}}},
+		{Name: "tiny", PathRules: []subsystem.PathRule{{IncludeRegexp: "^drivers/"}}},
+	}
+	subsystems := subsystemPCs(rg, cfg, list)
+	require.Len(t, subsystems, 4)
+
+	// KCOV reports the PC of the next instruction.
+	var corpusCover []uint64
+	addCover := func(from, count uint64) {
+		for pc := from; pc < from+count; pc++ {
+			corpusCover = append(corpusCover, pc+5)
+		}
+	}
+	addCover(0x1000, 100)
+	addCover(0x2000, 30)
+	addCover(0x4000, 10)
+	coverage := SubsystemsCoverage(subsystems, corpusCover)
+	got := map[string][2]int{}
+	for _, sc := range coverage {
+		got[sc.Subsystem] = [2]int{sc.Covered, sc.Total}
+	}
+	assert.Equal(t, map[string][2]int{
+		"fs":   {30, 300},
+		"mm":   {0, 100},
+		"net":  {100, 200},
+		"tiny": {10, 10},
+	}, got)
+
+	// mm is not reachable, tiny is too small.
+	areas := ChooseAutoFocusAreas(coverage, 2, 1.0)
+	require.Len(t, areas, 2)
+	assert.Equal(t, "fs", areas[0].Subsystem)
+	assert.InDelta(t, 0.9/1.4, areas[0].Weight, 1e-9)
+	assert.Equal(t, "net", areas[1].Subsystem)
+	assert.InDelta(t, 0.5/1.4, areas[1].Weight, 1e-9)
+	focus := areas[1].FocusArea()
+	assert.Equal(t, "net", focus.Name)
+	assert.Len(t, focus.CoverPCs, 200)
+	assert.Contains(t, focus.CoverPCs, uint64(0x1000+5))
+
+	areas = ChooseAutoFocusAreas(coverage, 1, 2.0)
+	require.Len(t, areas, 1)
+	assert.Equal(t, "fs", areas[0].Subsystem)
+	assert.InDelta(t, 2.0, areas[0].Weight, 1e-9)
+}
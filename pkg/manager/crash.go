@@ -30,6 +30,8 @@ type CrashStore struct {
 const reproFileName = "repro.prog"
 const cReproFileName = "repro.cprog"
 const straceFileName = "strace.log"
+const triageFileName = "triage"
+const guiltyFileName = "guilty"
 
 const MaxReproAttempts = 3
 
@@ -89,10 +91,29 @@ func (cs *CrashStore) SaveCrash(crash *Crash) (bool, error) {
 	writeOrRemove("tag", []byte(cs.Tag))
 	writeOrRemove("report", crash.Report.Report)
 	writeOrRemove("machineInfo", crash.MachineInfo)
-
+	if crash.GuiltyFile != "" {
+		osutil.WriteFile(filepath.Join(dir, guiltyFileName), []byte(crash.GuiltyFile))
+	}
+	if err := cs.saveTriage(dir, crash); err != nil {
+		return false, err
+	}
 	return first, nil
 }
 
+// saveTriage persists the result of the triage rules, the most recent crash wins.
+func (cs *CrashStore) saveTriage(dir string, crash *Crash) error {
+	name := filepath.Join(dir, triageFileName)
+	if len(crash.TriageRules) == 0 {
+		os.Remove(name)
+		return nil
+	}
+	text := fmt.Sprintf("%v\n%v\n", crash.Priority, strings.Join(crash.TriageRules, "\n"))
+	if err := osutil.WriteFile(name, []byte(text)); err != nil {
+		return fmt.Errorf("failed to write triage info: %w", err)
+	}
+	return nil
+}
+
 func (cs *CrashStore) HasRepro(title string) bool {
 	return osutil.IsExist(filepath.Join(cs.path(title), reproFileName))
 }
@@ -217,7 +238,11 @@ type BugInfo struct {
 	HasCRepro     bool
 	StraceFile    string // relative to the workdir
 	ReproAttempts int
-	Crashes       []*CrashInfo
+	// Priority and names of the triage rules that matched the last crash.
+	Priority    int
+	TriageRules []string
+	GuiltyFile  string // guilty file of the last crash that had one
+	Crashes     []*CrashInfo
 }
 
 func (cs *CrashStore) BugInfo(id string, full bool) (*BugInfo, error) {
@@ -254,6 +279,11 @@ func (cs *CrashStore) BugInfo(id string, full bool) (*BugInfo, error) {
 			ret.HasCRepro = true
 		} else if f == straceFileName {
 			ret.StraceFile = filepath.Join(dir, f)
+		} else if f == triageFileName {
+			ret.Priority, ret.TriageRules = readTriage(filepath.Join(dir, f))
+		} else if f == guiltyFileName {
+			guilty, _ := os.ReadFile(filepath.Join(dir, f))
+			ret.GuiltyFile = string(guilty)
 		} else if strings.HasPrefix(f, "repro") {
 			ret.ReproAttempts++
 		}
@@ -308,6 +338,19 @@ func (cs *CrashStore) BugList() ([]*BugInfo, error) {
 	return ret, nil
 }
 
+func readTriage(file string) (int, []string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, nil
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	priority, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return 0, nil
+	}
+	return priority, lines[1:]
+}
+
 func crashHash(title string) string {
 	sig := hash.Hash([]byte(title))
 	return sig.String()
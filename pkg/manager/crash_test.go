@@ -62,6 +62,35 @@ func TestEmptyCrashList(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCrashTriage(t *testing.T) {
+	crashStore := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 10,
+	}
+	_, err := crashStore.SaveCrash(&Crash{Report: &report.Report{
+		Title:       "Title A",
+		Output:      []byte("ABCD"),
+		Priority:    10,
+		TriageRules: []string{"rule A", "rule B"},
+	}})
+	assert.NoError(t, err)
+	info, err := crashStore.BugInfo(crashHash("Title A"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, info.Priority)
+	assert.Equal(t, []string{"rule A", "rule B"}, info.TriageRules)
+
+	// The rules no longer match.
+	_, err = crashStore.SaveCrash(&Crash{Report: &report.Report{
+		Title:  "Title A",
+		Output: []byte("ABCD"),
+	}})
+	assert.NoError(t, err)
+	info, err = crashStore.BugInfo(crashHash("Title A"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, info.Priority)
+	assert.Empty(t, info.TriageRules)
+}
+
 func TestMaxCrashLogs(t *testing.T) {
 	crashStore := &CrashStore{
 		BaseDir:      t.TempDir(),
@@ -20,24 +20,26 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/syzkaller/pkg/corpus"
 	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/desccover"
 	"github.com/google/syzkaller/pkg/fuzzer"
 	"github.com/google/syzkaller/pkg/html/pages"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/pkg/subsystem"
 	"github.com/google/syzkaller/pkg/vcs"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/vm"
 	"github.com/google/syzkaller/vm/dispatcher"
 	"github.com/gorilla/handlers"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type CoverageInfo struct {
@@ -62,10 +64,13 @@ type HTTPServer struct {
 	Fuzzer          atomic.Pointer[fuzzer.Fuzzer]
 	Cover           atomic.Pointer[CoverageInfo]
 	EnabledSyscalls atomic.Value // map[*prog.Syscall]bool
+	AutoFocus       atomic.Value // []AutoFocusArea
 
 	// Internal state.
-	expertMode bool
-	paused     bool
+	expertMode     bool
+	paused         bool
+	subsystemsOnce sync.Once
+	subsystems     *subsystem.PathMatcher
 }
 
 func (serv *HTTPServer) Serve(ctx context.Context) error {
@@ -78,23 +83,29 @@ func (serv *HTTPServer) Serve(ctx context.Context) error {
 	handle := func(pattern string, handler func(http.ResponseWriter, *http.Request)) {
 		http.Handle(pattern, handlers.CompressHandler(http.HandlerFunc(handler)))
 	}
+	var metricLabels []stat.PrometheusLabel
+	if serv.Cfg.Name != "" {
+		metricLabels = append(metricLabels, stat.PrometheusLabel{Name: "manager", Value: serv.Cfg.Name})
+	}
 	// keep-sorted start
 	handle("/", serv.httpMain)
 	handle("/action", serv.httpAction)
 	handle("/addcandidate", serv.httpAddCandidate)
+	handle("/callcover", serv.httpCallCover)
 	handle("/config", serv.httpConfig)
 	handle("/corpus", serv.httpCorpus)
 	handle("/corpus.db", serv.httpDownloadCorpus)
 	handle("/cover", serv.httpCover)
 	handle("/coverprogs", serv.httpPrograms)
 	handle("/debuginput", serv.httpDebugInput)
+	handle("/desccover", serv.httpDescCover)
 	handle("/file", serv.httpFile)
 	handle("/filecover", serv.httpFileCover)
 	handle("/filterpcs", serv.httpFilterPCs)
 	handle("/funccover", serv.httpFuncCover)
 	handle("/input", serv.httpInput)
 	handle("/jobs", serv.httpJobs)
-	handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}).ServeHTTP)
+	handle("/metrics", stat.PrometheusHandler(metricLabels...).ServeHTTP)
 	handle("/modulecover", serv.httpModuleCover)
 	handle("/modules", serv.modulesInfo)
 	handle("/prio", serv.httpPrio)
@@ -110,6 +121,11 @@ func (serv *HTTPServer) Serve(ctx context.Context) error {
 		handle("/crash", serv.httpCrash)
 		handle("/report", serv.httpReport)
 	}
+	serv.serveAPI(handle)
+	if dir := serv.Cfg.AssetStorage.LocalDir(); dir != "" {
+		// Assets are already compressed, so don't use handle() here.
+		http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir(dir))))
+	}
 	// Browsers like to request this, without special handler this goes to / handler.
 	handle("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
 
@@ -172,9 +188,33 @@ func (serv *HTTPServer) httpMain(w http.ResponseWriter, r *http.Request) {
 	if serv.DiffStore != nil {
 		data.PatchedOnly, data.AffectsBoth, data.InProgress = serv.collectDiffCrashes()
 	}
+	data.AutoFocus = serv.collectAutoFocus()
 	executeTemplate(w, mainTemplate, data)
 }
 
+func (serv *HTTPServer) collectAutoFocus() []UIAutoFocusArea {
+	areas, _ := serv.AutoFocus.Load().([]AutoFocusArea)
+	progs := make(map[string]int)
+	if corpus := serv.Corpus.Load(); corpus != nil {
+		corpusAreas, counts := corpus.AutoFocusAreas()
+		for i, area := range corpusAreas {
+			progs[area.Name] = counts[i]
+		}
+	}
+	var ret []UIAutoFocusArea
+	for _, area := range areas {
+		ret = append(ret, UIAutoFocusArea{
+			Subsystem: area.Subsystem,
+			Covered:   area.Covered,
+			Total:     area.Total,
+			Percent:   area.Ratio() * 100,
+			Weight:    area.Weight,
+			Programs:  progs[area.Subsystem],
+		})
+	}
+	return ret
+}
+
 func (serv *HTTPServer) httpConfig(w http.ResponseWriter, r *http.Request) {
 	serv.jsonPage(w, r, "config", serv.Cfg)
 }
@@ -260,6 +300,76 @@ func (serv *HTTPServer) httpSyscalls(w http.ResponseWriter, r *http.Request) {
 	executeTemplate(w, syscallsTemplate, data)
 }
 
+// httpCallCover shows the coverage that each syscall uniquely contributes to the corpus.
+func (serv *HTTPServer) httpCallCover(w http.ResponseWriter, r *http.Request) {
+	if !serv.Cfg.Cover {
+		http.Error(w, "coverage is not enabled", http.StatusInternalServerError)
+		return
+	}
+	coverInfo := serv.Cover.Load()
+	corpusObj := serv.Corpus.Load()
+	syscallsObj := serv.EnabledSyscalls.Load()
+	if coverInfo == nil || corpusObj == nil || syscallsObj == nil {
+		http.Error(w, "coverage is not ready, please try again later after fuzzer started",
+			http.StatusInternalServerError)
+		return
+	}
+	rg, err := coverInfo.ReportGenerator.Get()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to generate coverage profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	calls := make(map[string][]uint64)
+	for call, cc := range corpusObj.CallCover() {
+		calls[call] = CoverToPCs(serv.Cfg, cc.Cover.Serialize())
+	}
+	var enabled []string
+	for call := range syscallsObj.(map[*prog.Syscall]bool) {
+		enabled = append(enabled, call.Name)
+	}
+	attr := rg.CallAttribution(calls, enabled)
+	if r.FormValue("json") == "1" {
+		w.Header().Set("Content-Type", ctApplicationJSON)
+		if err := json.NewEncoder(w).Encode(attr); err != nil {
+			http.Error(w, fmt.Sprintf("failed to encode json: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+	executeTemplate(w, callCoverTemplate, &UICallCoverData{
+		UIPageHeader: serv.pageHeader(r, "syscall coverage attribution"),
+		Attribution:  *attr,
+	})
+}
+
+func (serv *HTTPServer) httpDescCover(w http.ResponseWriter, r *http.Request) {
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		http.Error(w, "the corpus information is not yet available", http.StatusInternalServerError)
+		return
+	}
+	stats := desccover.NewStats(serv.Cfg.Target)
+	for _, inp := range corpus.Items() {
+		stats.Add(inp.Prog)
+	}
+	rep := stats.Report()
+	all := r.FormValue("all") == "1"
+	if !all {
+		rep = rep.Problems()
+	}
+	if r.FormValue("json") == "1" {
+		w.Header().Set("Content-Type", ctApplicationJSON)
+		if err := json.NewEncoder(w).Encode(rep); err != nil {
+			http.Error(w, fmt.Sprintf("failed to encode json: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+	executeTemplate(w, descCoverTemplate, &UIDescCoverData{
+		UIPageHeader: serv.pageHeader(r, "description coverage"),
+		All:          all,
+		Report:       *rep,
+	})
+}
+
 func (serv *HTTPServer) httpStats(w http.ResponseWriter, r *http.Request) {
 	html, err := pages.StatsHTML()
 	if err != nil {
@@ -364,6 +474,9 @@ func makeUICrashType(info *BugInfo, startTime time.Time, repros map[string]bool)
 		Count:       len(info.Crashes),
 		Triaged:     triaged,
 		Strace:      info.StraceFile,
+		Priority:    info.Priority,
+		TriageRules: info.TriageRules,
+		Impact:      report.TitlesToImpact(info.Title),
 		Crashes:     crashes,
 	}
 }
@@ -446,6 +559,8 @@ const (
 	DoFilterPCs
 	DoCoverJSONL
 	DoCoverPrograms
+	DoLCOV
+	DoCobertura
 )
 
 func (serv *HTTPServer) httpCover(w http.ResponseWriter, r *http.Request) {
@@ -457,6 +572,14 @@ func (serv *HTTPServer) httpCover(w http.ResponseWriter, r *http.Request) {
 		serv.httpCoverCover(w, r, DoCoverJSONL)
 		return
 	}
+	if r.FormValue("lcov") == "1" {
+		serv.httpCoverCover(w, r, DoLCOV)
+		return
+	}
+	if r.FormValue("cobertura") == "1" {
+		serv.httpCoverCover(w, r, DoCobertura)
+		return
+	}
 	serv.httpCoverCover(w, r, DoHTML)
 }
 
@@ -490,6 +613,7 @@ func (serv *HTTPServer) httpModuleCover(w http.ResponseWriter, r *http.Request)
 
 const ctTextPlain = "text/plain; charset=utf-8"
 const ctApplicationJSON = "application/json"
+const ctApplicationXML = "application/xml"
 
 func (serv *HTTPServer) httpCoverCover(w http.ResponseWriter, r *http.Request, funcFlag int) {
 	if !serv.Cfg.Cover {
@@ -592,6 +716,8 @@ func (serv *HTTPServer) httpCoverCover(w http.ResponseWriter, r *http.Request, f
 		DoFilterPCs:      {rg.DoFilterPCs, ctTextPlain},
 		DoCoverJSONL:     {rg.DoCoverJSONL, ctApplicationJSON},
 		DoCoverPrograms:  {rg.DoCoverPrograms, ctApplicationJSON},
+		DoLCOV:           {rg.DoLCOV, ctTextPlain},
+		DoCobertura:      {rg.DoCobertura, ctApplicationXML},
 	}
 
 	if ct := flagToFunc[funcFlag].contentType; ct != "" {
@@ -988,9 +1114,19 @@ type UISummaryData struct {
 	PatchedOnly *UIDiffTable
 	AffectsBoth *UIDiffTable
 	InProgress  *UIDiffTable
+	AutoFocus   []UIAutoFocusArea
 	Log         string
 }
 
+type UIAutoFocusArea struct {
+	Subsystem string
+	Covered   int
+	Total     int
+	Percent   float64
+	Weight    float64
+	Programs  int
+}
+
 type UIDiffTable struct {
 	Title string
 	List  []UIDiffBug
@@ -1029,6 +1165,9 @@ type UICrashType struct {
 	Count       int
 	Triaged     string
 	Strace      string
+	Priority    int
+	TriageRules []string
+	Impact      int // -1 if unknown
 	Crashes     []UICrash
 }
 
@@ -1133,6 +1272,17 @@ type UIPrio struct {
 	Prio int32
 }
 
+type UICallCoverData struct {
+	UIPageHeader
+	cover.Attribution
+}
+
+type UIDescCoverData struct {
+	UIPageHeader
+	All bool
+	desccover.Report
+}
+
 type UIFallbackCoverData struct {
 	UIPageHeader
 	Calls []UIFallbackCall
@@ -1180,6 +1330,8 @@ var (
 	crashTemplate         = createPage("crash", UICrashPage{})
 	corpusTemplate        = createPage("corpus", UICorpusPage{})
 	prioTemplate          = createPage("prio", UIPrioData{})
+	callCoverTemplate     = createPage("call_cover", UICallCoverData{})
+	descCoverTemplate     = createPage("desc_cover", UIDescCoverData{})
 	fallbackCoverTemplate = createPage("fallback_cover", UIFallbackCoverData{})
 	rawCoverTemplate      = createPage("raw_cover", UIRawCoverPage{})
 	jobListTemplate       = createPage("job_list", UIJobList{})
@@ -0,0 +1,379 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/jsonapi"
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/pkg/subsystem"
+)
+
+// The JSON API is versioned: incompatible changes need a new path prefix,
+// while new fields and parameters may be added to the existing version.
+const apiPrefix = "/api/v1"
+
+type APICrash struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	FirstTime     time.Time `json:"first_time"`
+	LastTime      time.Time `json:"last_time"`
+	NumCrashes    int       `json:"num_crashes"`
+	HasRepro      bool      `json:"has_repro"`
+	HasCRepro     bool      `json:"has_c_repro"`
+	ReproAttempts int       `json:"repro_attempts"`
+	Priority      int       `json:"priority,omitempty"`
+	TriageRules   []string  `json:"triage_rules,omitempty"`
+}
+
+type APICrashDetails struct {
+	APICrash
+	Crashes []APICrashLog `json:"crashes"`
+	Repro   string        `json:"repro,omitempty"`
+	CRepro  string        `json:"c_repro,omitempty"`
+}
+
+type APICrashLog struct {
+	Time   time.Time `json:"time"`
+	Tag    string    `json:"tag,omitempty"`
+	Log    string    `json:"log"`
+	Report string    `json:"report,omitempty"`
+}
+
+type APIProgram struct {
+	Sig     string   `json:"sig"`
+	Call    string   `json:"call"`
+	Calls   []string `json:"calls"`
+	Signal  int      `json:"signal"`
+	Cover   int      `json:"cover"`
+	Updates int      `json:"updates"`
+}
+
+type APIProgramDetails struct {
+	APIProgram
+	Prog string `json:"prog"`
+}
+
+type APIStats struct {
+	Values []APIStatValue  `json:"values"`
+	Series []APIStatSeries `json:"series"`
+}
+
+type APIStatValue struct {
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+	Value int    `json:"value"`
+	Text  string `json:"text"`
+}
+
+// APIStatSeries is a time series for one stats graph.
+// Each point contains seconds since the manager start and one value per line.
+type APIStatSeries struct {
+	Title  string         `json:"title"`
+	Lines  []string       `json:"lines"`
+	Points []APIStatPoint `json:"points"`
+}
+
+type APIStatPoint struct {
+	Seconds int       `json:"seconds"`
+	Values  []float64 `json:"values"`
+}
+
+var crashFilterParams = []jsonapi.Param{
+	{Name: "title", Type: "string", Description: "regexp that crash titles must match"},
+	{Name: "since", Type: "string", Description: "only crashes that happened after this time (RFC 3339 or date)"},
+	{Name: "until", Type: "string", Description: "only crashes that first happened before this time"},
+	{Name: "has_repro", Type: "boolean", Description: "only crashes with (true) or without (false) a reproducer"},
+	{Name: "subsystem", Type: "string", Description: "only crashes whose guilty file belongs to the kernel subsystem"},
+}
+
+func (serv *HTTPServer) apiSpec() *jsonapi.Spec {
+	spec := jsonapi.NewSpec("syz-manager", "v1")
+	spec.Get(apiPrefix+"/crashes", "list crashes, most recent first",
+		slices.Concat(crashFilterParams, jsonapi.PageParams), jsonapi.Page[APICrash]{})
+	spec.Get(apiPrefix+"/crash", "crash details", []jsonapi.Param{
+		{Name: "id", Type: "string", Required: true},
+	}, APICrashDetails{})
+	spec.Get(apiPrefix+"/corpus", "list corpus programs", slices.Concat([]jsonapi.Param{
+		{Name: "call", Type: "string", Description: "regexp that the call that gave new signal must match"},
+	}, jsonapi.PageParams), jsonapi.Page[APIProgram]{})
+	spec.Get(apiPrefix+"/program", "corpus program details", []jsonapi.Param{
+		{Name: "sig", Type: "string", Required: true},
+	}, APIProgramDetails{})
+	spec.Get(apiPrefix+"/stats", "current stat values and their time series", []jsonapi.Param{
+		{Name: "name", Type: "string", Description: "regexp that stat names and graph titles must match"},
+	}, APIStats{})
+	return spec
+}
+
+func (serv *HTTPServer) serveAPI(handle func(pattern string, handler func(http.ResponseWriter, *http.Request))) {
+	handle(apiPrefix+"/openapi.json", serv.apiSpec().ServeHTTP)
+	handle(apiPrefix+"/corpus", serv.apiCorpus)
+	handle(apiPrefix+"/program", serv.apiProgram)
+	handle(apiPrefix+"/stats", serv.apiStats)
+	if serv.CrashStore != nil {
+		handle(apiPrefix+"/crashes", serv.apiCrashes)
+		handle(apiPrefix+"/crash", serv.apiCrash)
+	}
+}
+
+func (serv *HTTPServer) apiCrashes(w http.ResponseWriter, r *http.Request) {
+	params := jsonapi.NewParams(r)
+	title := params.Regexp("title")
+	since, until := params.Time("since"), params.Time("until")
+	hasRepro := params.Bool("has_repro")
+	subsystemName := params.String("subsystem")
+	cursor, limit := params.String("cursor"), params.Int("limit")
+	if err := params.Err(); err != nil {
+		jsonapi.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	matcher := serv.subsystemMatcher()
+	if subsystemName != "" && matcher == nil {
+		jsonapi.WriteError(w, http.StatusBadRequest,
+			fmt.Errorf("no subsystems are known for %v", serv.Cfg.TargetOS))
+		return
+	}
+	bugs, err := serv.CrashStore.BugList()
+	if err != nil {
+		jsonapi.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	var crashes []APICrash
+	for _, bug := range bugs {
+		if title != nil && !title.MatchString(bug.Title) ||
+			!since.IsZero() && bug.LastTime.Before(since) ||
+			!until.IsZero() && bug.FirstTime.After(until) ||
+			hasRepro != nil && *hasRepro != bug.HasRepro ||
+			subsystemName != "" && !inSubsystem(matcher, bug.GuiltyFile, subsystemName) {
+			continue
+		}
+		crashes = append(crashes, makeAPICrash(bug))
+	}
+	sort.Slice(crashes, func(i, j int) bool {
+		return crashOrderKey(crashes[i]) < crashOrderKey(crashes[j])
+	})
+	page, err := jsonapi.Paginate(crashes, crashOrderKey, cursor, limit)
+	if err != nil {
+		jsonapi.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	jsonapi.WriteJSON(w, page)
+}
+
+// subsystemMatcher returns the matcher of the kernel subsystems of the target OS,
+// or nil if no subsystems are known for it.
+func (serv *HTTPServer) subsystemMatcher() *subsystem.PathMatcher {
+	serv.subsystemsOnce.Do(func() {
+		if serv.Cfg == nil {
+			return
+		}
+		if list := subsystem.GetList(serv.Cfg.TargetOS); len(list) != 0 {
+			serv.subsystems = subsystem.MakePathMatcher(list)
+		}
+	})
+	return serv.subsystems
+}
+
+// inSubsystem checks whether the file belongs to the named subsystem or to one of its children
+// (the same way the triage rules match subsystems).
+func inSubsystem(matcher *subsystem.PathMatcher, file, name string) bool {
+	if file == "" {
+		return false
+	}
+	for _, s := range matcher.Match(file) {
+		if s.Name == name {
+			return true
+		}
+		for parent := range s.ReachableParents() {
+			if parent.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// crashOrderKey sorts crashes by the last crash time (most recent first).
+func crashOrderKey(crash APICrash) string {
+	return fmt.Sprintf("%016x-%v", ^uint64(crash.LastTime.UnixNano()), crash.ID)
+}
+
+func makeAPICrash(bug *BugInfo) APICrash {
+	return APICrash{
+		ID:            bug.ID,
+		Title:         bug.Title,
+		FirstTime:     bug.FirstTime,
+		LastTime:      bug.LastTime,
+		NumCrashes:    len(bug.Crashes),
+		HasRepro:      bug.HasRepro,
+		HasCRepro:     bug.HasCRepro,
+		ReproAttempts: bug.ReproAttempts,
+		Priority:      bug.Priority,
+		TriageRules:   bug.TriageRules,
+	}
+}
+
+func (serv *HTTPServer) apiCrash(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("id")
+	if !isValidCrashID(id) {
+		jsonapi.WriteError(w, http.StatusBadRequest, fmt.Errorf("bad crash id %q", id))
+		return
+	}
+	bug, err := serv.CrashStore.BugInfo(id, true)
+	if errors.Is(err, os.ErrNotExist) {
+		jsonapi.WriteError(w, http.StatusNotFound, fmt.Errorf("no crash %q", id))
+		return
+	} else if err != nil {
+		jsonapi.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	res := &APICrashDetails{
+		APICrash: makeAPICrash(bug),
+		Crashes:  []APICrashLog{},
+	}
+	for _, crash := range bug.Crashes {
+		res.Crashes = append(res.Crashes, APICrashLog{
+			Time:   crash.Time,
+			Tag:    crash.Tag,
+			Log:    crash.Log,
+			Report: crash.Report,
+		})
+	}
+	if report, err := serv.CrashStore.Report(id); err == nil {
+		res.Repro = string(report.Prog)
+		res.CRepro = string(report.CProg)
+	}
+	jsonapi.WriteJSON(w, res)
+}
+
+// isValidCrashID prevents path traversal via crash IDs (they are hex hashes of titles).
+func isValidCrashID(id string) bool {
+	if id == "" || filepath.Base(id) != id {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (serv *HTTPServer) apiCorpus(w http.ResponseWriter, r *http.Request) {
+	params := jsonapi.NewParams(r)
+	call := params.Regexp("call")
+	cursor, limit := params.String("cursor"), params.Int("limit")
+	if err := params.Err(); err != nil {
+		jsonapi.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		jsonapi.WriteError(w, http.StatusServiceUnavailable,
+			errors.New("the corpus information is not yet available"))
+		return
+	}
+	var progs []APIProgram
+	for _, item := range corpus.Items() {
+		if call != nil && !call.MatchString(item.StringCall()) {
+			continue
+		}
+		progs = append(progs, makeAPIProgram(item))
+	}
+	sort.Slice(progs, func(i, j int) bool {
+		return progs[i].Sig < progs[j].Sig
+	})
+	page, err := jsonapi.Paginate(progs, func(p APIProgram) string { return p.Sig }, cursor, limit)
+	if err != nil {
+		jsonapi.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	jsonapi.WriteJSON(w, page)
+}
+
+func makeAPIProgram(item *corpus.Item) APIProgram {
+	res := APIProgram{
+		Sig:     item.Sig,
+		Call:    item.StringCall(),
+		Calls:   []string{},
+		Signal:  item.Signal.Len(),
+		Cover:   len(item.Cover),
+		Updates: len(item.Updates),
+	}
+	for _, call := range item.Prog.Calls {
+		res.Calls = append(res.Calls, call.Meta.Name)
+	}
+	return res
+}
+
+func (serv *HTTPServer) apiProgram(w http.ResponseWriter, r *http.Request) {
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		jsonapi.WriteError(w, http.StatusServiceUnavailable,
+			errors.New("the corpus information is not yet available"))
+		return
+	}
+	sig := r.FormValue("sig")
+	item := corpus.Item(sig)
+	if item == nil {
+		jsonapi.WriteError(w, http.StatusNotFound, fmt.Errorf("no program %q", sig))
+		return
+	}
+	jsonapi.WriteJSON(w, &APIProgramDetails{
+		APIProgram: makeAPIProgram(item),
+		Prog:       string(item.Prog.Serialize()),
+	})
+}
+
+func (serv *HTTPServer) apiStats(w http.ResponseWriter, r *http.Request) {
+	params := jsonapi.NewParams(r)
+	name := params.Regexp("name")
+	if err := params.Err(); err != nil {
+		jsonapi.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	res := &APIStats{
+		Values: []APIStatValue{},
+		Series: []APIStatSeries{},
+	}
+	for _, v := range stat.Collect(stat.All) {
+		if name != nil && !name.MatchString(v.Name) {
+			continue
+		}
+		res.Values = append(res.Values, APIStatValue{
+			Name:  v.Name,
+			Desc:  v.Desc,
+			Value: v.V,
+			Text:  v.Value,
+		})
+	}
+	for _, graph := range stat.RenderGraphs() {
+		if name != nil && !name.MatchString(graph.Title) {
+			continue
+		}
+		series := APIStatSeries{
+			Title:  graph.Title,
+			Lines:  graph.Lines,
+			Points: []APIStatPoint{},
+		}
+		for _, point := range graph.Points {
+			series.Points = append(series.Points, APIStatPoint{
+				Seconds: point.X,
+				Values:  point.Y,
+			})
+		}
+		res.Series = append(res.Series, series)
+	}
+	jsonapi.WriteJSON(w, res)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/jsonapi"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICrashes(t *testing.T) {
+	serv := &HTTPServer{
+		Cfg: &mgrconfig.Config{Derived: mgrconfig.Derived{TargetOS: targets.Linux}},
+		CrashStore: &CrashStore{
+			BaseDir:      t.TempDir(),
+			MaxCrashLogs: 10,
+		},
+	}
+	for title, guilty := range map[string]string{
+		"KASAN: use-after-free in foo": "fs/ext4/super.c",
+		"WARNING in bar":               "net/core/dev.c",
+		"KASAN: double-free in baz":    "",
+	} {
+		_, err := serv.CrashStore.SaveCrash(&Crash{Report: &report.Report{
+			Title:      title,
+			Output:     []byte("output"),
+			Report:     []byte("report"),
+			GuiltyFile: guilty,
+		}})
+		assert.NoError(t, err)
+	}
+
+	listCrashes := func(query string) jsonapi.Page[APICrash] {
+		return apiGet[jsonapi.Page[APICrash]](t, serv.apiCrashes, "/api/v1/crashes?"+query, http.StatusOK)
+	}
+	page := listCrashes("title=KASAN&limit=1")
+	assert.Len(t, page.Items, 1)
+	assert.NotEmpty(t, page.NextCursor)
+	first := page.Items[0]
+	page = listCrashes("title=KASAN&limit=1&cursor=" + page.NextCursor)
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+	assert.NotEqual(t, first.ID, page.Items[0].ID)
+	assert.Regexp(t, "^KASAN", page.Items[0].Title)
+
+	page = listCrashes("has_repro=true")
+	assert.Empty(t, page.Items)
+	page = listCrashes("since=2000-01-01")
+	assert.Len(t, page.Items, 3)
+	page = listCrashes("until=2000-01-01")
+	assert.Empty(t, page.Items)
+	page = listCrashes("subsystem=ext4")
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "KASAN: use-after-free in foo", page.Items[0].Title)
+	// Crashes in child subsystems also match the parent subsystem.
+	page = listCrashes("subsystem=fs")
+	assert.Len(t, page.Items, 1)
+	page = listCrashes("subsystem=net")
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "WARNING in bar", page.Items[0].Title)
+
+	apiErr := apiGet[jsonapi.Error](t, serv.apiCrashes, "/api/v1/crashes?title=(", http.StatusBadRequest)
+	assert.Contains(t, apiErr.Error, "bad title parameter")
+
+	details := apiGet[APICrashDetails](t, serv.apiCrash, "/api/v1/crash?id="+first.ID, http.StatusOK)
+	assert.Equal(t, first.Title, details.Title)
+	assert.Len(t, details.Crashes, 1)
+	assert.NotEmpty(t, details.Crashes[0].Report)
+	apiGet[jsonapi.Error](t, serv.apiCrash, "/api/v1/crash?id=../../etc", http.StatusBadRequest)
+	apiGet[jsonapi.Error](t, serv.apiCrash, "/api/v1/crash?id=abcd", http.StatusNotFound)
+}
+
+func TestAPISpec(t *testing.T) {
+	serv := &HTTPServer{}
+	spec := apiGet[map[string]any](t, serv.apiSpec().ServeHTTP, "/api/v1/openapi.json", http.StatusOK)
+	paths := spec["paths"].(map[string]any)
+	for _, path := range []string{"/crashes", "/crash", "/corpus", "/program", "/stats"} {
+		assert.Contains(t, paths, apiPrefix+path)
+	}
+}
+
+func apiGet[T any](t *testing.T, handler http.HandlerFunc, url string, code int) T {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", url, nil))
+	assert.Equal(t, code, w.Code, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var res T
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
@@ -45,6 +45,11 @@ func (c *Crash) FullTitle() string {
 	panic("the crash is expected to have a report")
 }
 
+// ImpactScore estimates the severity of the crash, see report.TitlesToImpact.
+func (c *Crash) ImpactScore() int {
+	return report.TitlesToImpact(c.Report.Title, c.Report.AltTitles...)
+}
+
 type ReproManagerView interface {
 	RunRepro(ctx context.Context, crash *Crash) *ReproResult
 	NeedRepro(crash *Crash) bool
@@ -153,7 +158,12 @@ func (r *ReproLoop) popCrash() *Crash {
 		if new.FromHub != base.FromHub {
 			return !new.FromHub
 		}
-		return false
+		// Then, serve crashes prioritized by the triage rules.
+		if new.Priority != base.Priority {
+			return new.Priority > base.Priority
+		}
+		// Memory corruptions are more important than e.g. WARNINGs.
+		return new.ImpactScore() > base.ImpactScore()
 	}
 
 	idx := -1
@@ -96,6 +96,35 @@ func TestReproOrder(t *testing.T) {
 	}
 }
 
+func TestReproImpactOrder(t *testing.T) {
+	mock := &reproMgrMock{
+		run: make(chan runCallback),
+	}
+	obj := NewReproLoop(mock, 1, false)
+
+	// The right order is A B C D.
+	crashes := []*Crash{
+		{Report: &report.Report{Title: "A", Priority: 1}},
+		{Report: &report.Report{Title: "KASAN: slab-use-after-free Write in foo"}},
+		{Report: &report.Report{Title: "KASAN: slab-out-of-bounds Read in foo"}},
+		{Report: &report.Report{Title: "WARNING in foo"}},
+	}
+	obj.Enqueue(crashes[3])
+	obj.Enqueue(crashes[2])
+	obj.Enqueue(crashes[1])
+	obj.Enqueue(crashes[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go obj.Loop(ctx)
+
+	for i := 0; i < len(crashes); i++ {
+		called := <-mock.run
+		assert.Equal(t, crashes[i], called.crash)
+		called.ret <- &ReproResult{}
+	}
+}
+
 func TestReproRWRace(t *testing.T) {
 	var reproProgExist atomic.Bool
 	mock := &reproMgrMock{
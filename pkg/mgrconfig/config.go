@@ -118,6 +118,11 @@ type Config struct {
 	// Maximum number of logs to store per crash (default: 100).
 	MaxCrashLogs int `json:"max_crash_logs"`
 
+	// How long (in hours) to keep the history of stats graphs across manager restarts (default: 168).
+	// The history is periodically saved to workdir/stats-history.json.
+	// 0 disables saving of the history.
+	StatsRetention int `json:"stats_retention"`
+
 	// Type of sandbox to use during fuzzing:
 	// "none": test under root;
 	//      don't do anything special beyond resource sandboxing,
@@ -157,6 +162,13 @@ type Config struct {
 	// Reproduce, localize and minimize crashers (default: true).
 	Reproduce bool `json:"reproduce"`
 
+	// Crash type (e.g. "KCSAN-DATARACE") that sets the minimal impact of the crashes that are
+	// reproduced locally. Crashes that are less severe according to impactOrder
+	// in pkg/report/crash/impact.go are recorded, but not reproduced. The type must be listed
+	// in impactOrder. Crash types that are not listed there (e.g. plain WARNING) are considered
+	// the least severe ones. By default all crashes are reproduced.
+	ReproMinImpact string `json:"repro_min_impact,omitempty"`
+
 	// The number of VMs that are reserved to only perform fuzzing and nothing else.
 	// Can be helpful e.g. to ensure that the pool of fuzzing VMs is never exhausted and
 	// the manager continues fuzzing no matter how many new bugs are encountered.
@@ -186,6 +198,14 @@ type Config struct {
 	// If this list is not empty and none of the regexps match a bug, it's suppressed.
 	// Regexps are matched against bug title, guilty file and maintainer emails.
 	Interests []string `json:"interests,omitempty"`
+	// Path to a JSON file with a list of crash triage rules (optional).
+	// Each rule matches crashes by title regexp, crash type, guilty file, report frames
+	// and kernel subsystem, and can suppress or re-title them, assign priority,
+	// disable reproduction, or notify additional emails. For example:
+	//	[{"name": "net", "subsystem": "net", "priority": 10, "email_addrs": ["me@example.com"]},
+	//	 {"title": "^WARNING in (.*)", "retitle": "WARN: $1", "skip_repro": true}]
+	// See TriageRule in pkg/report/triage.go for details.
+	TriageRules string `json:"triage_rules,omitempty"`
 
 	// Path to the strace binary compiled for the target architecture.
 	// If set, for each reproducer syzkaller will run it once more under strace and save
@@ -255,6 +275,15 @@ type Experimental struct {
 	// with an empty Filter, but non-empty weight.
 	// E.g. "focus_areas": [ {"filter": {"files": ["^net"]}, "weight": 10.0}, {"weight": 1.0"} ].
 	FocusAreas []FocusArea `json:"focus_areas,omitempty"`
+
+	// AutoFocus makes the manager periodically compute per-subsystem coverage and
+	// automatically focus fuzzing on the least covered subsystems that are reachable
+	// (i.e. have at least some coverage). Focus areas are re-weighted as the coverage grows
+	// and are shown on the main manager page. Requires a subsystem list for the target OS.
+	AutoFocus bool `json:"auto_focus"`
+
+	// The number of subsystems to automatically focus on (default: 3).
+	AutoFocusSubsystems int `json:"auto_focus_subsystems"`
 }
 
 type FocusArea struct {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	_ "github.com/google/syzkaller/sys" // most mgrconfig users want targets too
@@ -44,6 +45,9 @@ type Derived struct {
 	VMLess bool
 
 	LocalModules []*vminfo.KernelModule
+
+	// Impact score of the ReproMinImpact crash type, -1 if it's not set.
+	ReproMinImpactScore int
 }
 
 func LoadData(data []byte) (*Config, error) {
@@ -98,13 +102,15 @@ func defaultValues() *Config {
 		Sandbox:        "none",
 		RPC:            ":0",
 		MaxCrashLogs:   100,
+		StatsRetention: 7 * 24,
 		Procs:          6,
 		PreserveCorpus: true,
 		RunFsck:        true,
 		Experimental: Experimental{
-			RemoteCover:      true,
-			CoverEdges:       true,
-			DescriptionsMode: manualDescriptions,
+			RemoteCover:         true,
+			CoverEdges:          true,
+			DescriptionsMode:    manualDescriptions,
+			AutoFocusSubsystems: 3,
 		},
 	}
 }
@@ -176,6 +182,9 @@ func Complete(cfg *Config) error {
 	if cfg.Procs < 1 || cfg.Procs > prog.MaxPids {
 		return fmt.Errorf("bad config param procs: '%v', want [1, %v]", cfg.Procs, prog.MaxPids)
 	}
+	if cfg.StatsRetention < 0 {
+		return fmt.Errorf("bad config param stats_retention: '%v', want >= 0", cfg.StatsRetention)
+	}
 	switch cfg.Sandbox {
 	case "none", "setuid", "namespace", "android":
 	default:
@@ -193,6 +202,14 @@ func Complete(cfg *Config) error {
 	if cfg.FuzzingVMs < 0 {
 		return fmt.Errorf("fuzzing_vms cannot be less than 0")
 	}
+	cfg.ReproMinImpactScore = -1
+	if cfg.ReproMinImpact != "" {
+		cfg.ReproMinImpactScore = crash.Type(cfg.ReproMinImpact).Impact()
+		if cfg.ReproMinImpactScore < 0 {
+			return fmt.Errorf("bad config param repro_min_impact: %q is not a ranked crash type",
+				cfg.ReproMinImpact)
+		}
+	}
 
 	var err error
 	cfg.Syscalls, err = ParseEnabledSyscalls(cfg.Target, cfg.EnabledSyscalls, cfg.DisabledSyscalls,
@@ -381,6 +398,14 @@ func (cfg *Config) completeFocusAreas() error {
 			seenEmptyFilter = true
 		}
 	}
+	if cfg.Experimental.AutoFocus {
+		if !cfg.Cover {
+			return fmt.Errorf("auto_focus requires coverage")
+		}
+		if cfg.Experimental.AutoFocusSubsystems <= 0 {
+			return fmt.Errorf("auto_focus_subsystems must be positive")
+		}
+	}
 	if !cfg.CovFilter.Empty() {
 		if len(cfg.Experimental.FocusAreas) > 0 {
 			return fmt.Errorf("you cannot use both cov_filter and focus_areas")
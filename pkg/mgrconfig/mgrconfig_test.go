@@ -4,6 +4,7 @@
 package mgrconfig_test
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -66,3 +67,40 @@ func TestMatchSyscall(t *testing.T) {
 		}
 	}
 }
+
+func TestReproMinImpact(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "qemu.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		typ   string
+		score int
+		err   bool
+	}{
+		{typ: "", score: -1},
+		{typ: "HANG", score: 1},
+		// Not ranked in the impact order.
+		{typ: "WARNING", err: true},
+		{typ: "NOT-A-TYPE", err: true},
+	}
+	for _, test := range tests {
+		patched, err := config.PatchJSON(data, map[string]interface{}{"repro_min_impact": test.typ})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadData(patched)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: loaded the config with an unranked crash type", test.typ)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", test.typ, err)
+		}
+		if cfg.ReproMinImpactScore != test.score {
+			t.Errorf("%q: got score %v, want %v", test.typ, cfg.ReproMinImpactScore, test.score)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package patchtest tests kernel patches against reproducers locally:
+// it builds the base and the patched kernels, runs the reproducer several times on each,
+// and gives a verdict on whether the patch fixes the bug.
+// Unlike syz-ci patch testing, it does not need the dashboard or syz-cluster.
+package patchtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/build"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+type Config struct {
+	Manager *mgrconfig.Config
+	// If SyzkallerRepo is set, syzkaller is checked out at SyzkallerCommit and rebuilt
+	// before testing. Otherwise, the existing build in Manager.Syzkaller is used.
+	SyzkallerRepo   string
+	SyzkallerCommit string
+	// Kernel config used if the request does not specify one.
+	KernelConfig []byte
+	Compiler     string
+	Make         string
+	Linker       string
+	Ccache       string
+	Userspace    string
+	Cmdline      string
+	Sysctl       string
+	BuildCPUs    int
+}
+
+// DefaultRuns is the default number of reproducer runs on each kernel.
+const DefaultRuns = 3
+
+type Request struct {
+	// If KernelRepo is empty, the kernel checkout in Manager.KernelSrc is used as is.
+	KernelRepo   string
+	KernelBranch string
+	// If KernelCommit is empty, the head of KernelBranch is tested.
+	KernelCommit string
+	KernelConfig []byte
+	Patch        []byte
+	ReproSyz     []byte
+	ReproC       []byte
+	ReproOpts    []byte
+	// Number of reproducer runs on each kernel (DefaultRuns if 0).
+	Runs int
+	// Don't test the base kernel (then the verdict can't be VerdictNoRepro).
+	SkipBase bool
+}
+
+func (req *Request) Validate() error {
+	if len(req.Patch) == 0 {
+		return fmt.Errorf("no patch")
+	}
+	if len(req.ReproSyz) == 0 && len(req.ReproC) == 0 {
+		return fmt.Errorf("no reproducer")
+	}
+	if req.KernelRepo != "" && req.KernelBranch == "" && req.KernelCommit == "" {
+		return fmt.Errorf("kernel repo requires a branch or a commit")
+	}
+	if req.Runs < 0 {
+		return fmt.Errorf("negative number of runs")
+	}
+	return nil
+}
+
+type Verdict string
+
+const (
+	// The base kernel crashes, but the patched one does not.
+	VerdictFixed Verdict = "fixed"
+	// The patched kernel still crashes.
+	VerdictNotFixed Verdict = "not fixed"
+	// The base kernel does not crash, so the result of the patched kernel testing means nothing.
+	VerdictNoRepro Verdict = "no repro"
+	// Failed to apply the patch or to build a kernel.
+	VerdictBuildError Verdict = "build error"
+	// Too many runs failed with boot/test errors to give a verdict.
+	VerdictTestError Verdict = "test error"
+)
+
+type Result struct {
+	Verdict Verdict
+	Base    *KernelResult `json:",omitempty"`
+	Patched *KernelResult
+}
+
+// KernelResult is the result of testing of one kernel build.
+type KernelResult struct {
+	Commit      string
+	CommitTitle string
+	BuildError  string `json:",omitempty"`
+	Runs        int
+	Crashes     int
+	// Runs that failed with boot, infra or other test errors.
+	Errors      int
+	CrashTitles []string `json:",omitempty"`
+	// Report of the first crash.
+	CrashReport []byte   `json:",omitempty"`
+	TestErrors  []string `json:",omitempty"`
+}
+
+// Runner executes patch testing requests.
+type Runner interface {
+	Run(req *Request, logf func(msg string, args ...any)) (*Result, error)
+}
+
+type Tester struct {
+	cfg  *Config
+	repo vcs.Repo
+	inst instance.Env
+	// Whether syzkaller was already built.
+	syzBuilt bool
+}
+
+func NewTester(cfg *Config) (*Tester, error) {
+	mgrcfg := cfg.Manager
+	repo, err := vcs.NewRepo(mgrcfg.TargetOS, mgrcfg.Type, mgrcfg.KernelSrc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kernel repo: %w", err)
+	}
+	inst, err := instance.NewEnv(mgrcfg, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tester{
+		cfg:  cfg,
+		repo: repo,
+		inst: inst,
+	}, nil
+}
+
+// Run builds and tests the base and the patched kernels.
+// Returned errors mean problems with the testing setup, problems with the tested kernels
+// are reported in the result.
+func (t *Tester) Run(req *Request, logf func(msg string, args ...any)) (*Result, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if t.cfg.SyzkallerRepo != "" && !t.syzBuilt {
+		logf("building syzkaller on %v", t.cfg.SyzkallerCommit)
+		if _, err := t.inst.BuildSyzkaller(t.cfg.SyzkallerRepo, t.cfg.SyzkallerCommit); err != nil {
+			return nil, err
+		}
+		t.syzBuilt = true
+	}
+	com, err := t.checkout(req)
+	if err != nil {
+		return nil, err
+	}
+	logf("testing on commit %v %v", com.Hash, com.Title)
+	res := new(Result)
+	if !req.SkipBase {
+		logf("testing the base kernel")
+		if res.Base, err = t.testKernel(req, com, false, logf); err != nil {
+			return nil, err
+		}
+	}
+	logf("testing the patched kernel")
+	if res.Patched, err = t.testKernel(req, com, true, logf); err != nil {
+		return nil, err
+	}
+	res.Verdict = verdict(res.Base, res.Patched)
+	logf("verdict: %v", res.Verdict)
+	return res, nil
+}
+
+func (t *Tester) checkout(req *Request) (*vcs.Commit, error) {
+	switch {
+	case req.KernelRepo == "" && req.KernelCommit == "":
+		return t.repo.Commit(vcs.HEAD)
+	case req.KernelRepo == "":
+		return t.repo.SwitchCommit(req.KernelCommit)
+	case req.KernelCommit == "":
+		return t.repo.CheckoutBranch(req.KernelRepo, req.KernelBranch)
+	default:
+		return t.repo.CheckoutCommit(req.KernelRepo, req.KernelCommit)
+	}
+}
+
+func (t *Tester) testKernel(req *Request, com *vcs.Commit, patched bool,
+	logf func(msg string, args ...any)) (*KernelResult, error) {
+	res := &KernelResult{
+		Commit:      com.Hash,
+		CommitTitle: com.Title,
+	}
+	// Revert the patch applied for the previous build.
+	if _, err := t.repo.SwitchCommit(com.Hash); err != nil {
+		return nil, err
+	}
+	kernelConfig := req.KernelConfig
+	if len(kernelConfig) == 0 {
+		kernelConfig = t.cfg.KernelConfig
+	}
+	buildCfg := &instance.BuildKernelConfig{
+		CompilerBin:  t.cfg.Compiler,
+		MakeBin:      t.cfg.Make,
+		LinkerBin:    t.cfg.Linker,
+		CcacheBin:    t.cfg.Ccache,
+		UserspaceDir: t.cfg.Userspace,
+		CmdlineFile:  t.cfg.Cmdline,
+		SysctlFile:   t.cfg.Sysctl,
+		KernelConfig: kernelConfig,
+		BuildCPUs:    t.cfg.BuildCPUs,
+	}
+	if err := t.inst.CleanKernel(buildCfg); err != nil {
+		return nil, fmt.Errorf("kernel clean failed: %w", err)
+	}
+	if patched {
+		if err := vcs.Patch(t.cfg.Manager.KernelSrc, req.Patch); err != nil {
+			res.BuildError = err.Error()
+			return res, nil
+		}
+	}
+	logf("building the kernel")
+	if _, _, err := t.inst.BuildKernel(buildCfg); err != nil {
+		res.BuildError = buildErrorText(err)
+		logf("%v", res.BuildError)
+		return res, nil
+	}
+	runs := req.Runs
+	if runs == 0 {
+		runs = DefaultRuns
+	}
+	logf("running the reproducer %v times", runs)
+	results, err := t.inst.Test(runs, req.ReproSyz, req.ReproOpts, req.ReproC)
+	if err != nil {
+		return nil, err
+	}
+	res.add(results)
+	logf("%v runs: %v crashes %v, %v errors", res.Runs, res.Crashes, res.CrashTitles, res.Errors)
+	return res, nil
+}
+
+func buildErrorText(err error) string {
+	var verr *osutil.VerboseError
+	var kerr *build.KernelError
+	switch {
+	case errors.As(err, &kerr):
+		return fmt.Sprintf("%s\n\n%s", kerr.Report, kerr.Output)
+	case errors.As(err, &verr):
+		return fmt.Sprintf("%v\n\n%s", verr.Title, verr.Output)
+	default:
+		return err.Error()
+	}
+}
+
+func (res *KernelResult) add(results []instance.EnvTestResult) {
+	titles := make(map[string]bool)
+	for _, r := range results {
+		res.Runs++
+		if r.Error == nil {
+			continue
+		}
+		var crashError *instance.CrashError
+		if errors.As(r.Error, &crashError) {
+			res.Crashes++
+			titles[crashError.Report.Title] = true
+			if len(res.CrashReport) == 0 {
+				res.CrashReport = crashError.Report.Report
+			}
+			continue
+		}
+		res.Errors++
+		res.TestErrors = append(res.TestErrors, r.Error.Error())
+	}
+	for title := range titles {
+		res.CrashTitles = append(res.CrashTitles, title)
+	}
+	sort.Strings(res.CrashTitles)
+}
+
+func (res *KernelResult) verdict() Verdict {
+	switch {
+	case res.BuildError != "":
+		return VerdictBuildError
+	case res.Crashes != 0:
+		return VerdictNotFixed
+	case res.Errors*2 > res.Runs:
+		// If most of the runs failed, we can't say the kernel does not crash.
+		return VerdictTestError
+	default:
+		return VerdictFixed
+	}
+}
+
+func verdict(base, patched *KernelResult) Verdict {
+	if base != nil {
+		switch base.verdict() {
+		case VerdictBuildError:
+			return VerdictBuildError
+		case VerdictTestError:
+			return VerdictTestError
+		case VerdictFixed:
+			return VerdictNoRepro
+		}
+	}
+	return patched.verdict()
+}
+
+// ReadRequestFiles reads the reproducer from the crash dir (repro.prog, repro.cprog, repro.opts)
+// and the patch file into a request.
+func ReadRequestFiles(req *Request, reproDir, patchFile string) error {
+	var err error
+	if req.Patch, err = os.ReadFile(patchFile); err != nil {
+		return err
+	}
+	for file, dst := range map[string]*[]byte{
+		"repro.prog":  &req.ReproSyz,
+		"repro.cprog": &req.ReproC,
+		"repro.opts":  &req.ReproOpts,
+	} {
+		file = filepath.Join(reproDir, file)
+		if !osutil.IsExist(file) {
+			continue
+		}
+		if *dst, err = os.ReadFile(file); err != nil {
+			return err
+		}
+	}
+	return req.Validate()
+}
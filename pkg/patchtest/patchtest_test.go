@@ -0,0 +1,181 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package patchtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerdict(t *testing.T) {
+	crash := &instance.CrashError{Report: &report.Report{Title: "KASAN: use-after-free in foo"}}
+	boot := &instance.TestError{Boot: true, Title: "kernel doesn't boot"}
+	kernel := func(errs ...error) *KernelResult {
+		res := new(KernelResult)
+		var results []instance.EnvTestResult
+		for _, err := range errs {
+			results = append(results, instance.EnvTestResult{Error: err})
+		}
+		res.add(results)
+		return res
+	}
+	tests := []struct {
+		base    *KernelResult
+		patched *KernelResult
+		verdict Verdict
+	}{
+		{kernel(crash, nil, crash), kernel(nil, nil, nil), VerdictFixed},
+		{kernel(crash, nil, crash), kernel(nil, crash, nil), VerdictNotFixed},
+		{kernel(nil, nil, nil), kernel(nil, nil, nil), VerdictNoRepro},
+		{nil, kernel(nil, nil, nil), VerdictFixed},
+		{kernel(crash), kernel(boot, boot, nil), VerdictTestError},
+		{kernel(crash), kernel(boot, nil, nil), VerdictFixed},
+		{kernel(crash), &KernelResult{BuildError: "patch does not apply"}, VerdictBuildError},
+		{&KernelResult{BuildError: "broken"}, kernel(nil), VerdictBuildError},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(t, test.verdict, verdict(test.base, test.patched))
+		})
+	}
+	res := kernel(crash, boot, crash, nil)
+	assert.Equal(t, 4, res.Runs)
+	assert.Equal(t, 2, res.Crashes)
+	assert.Equal(t, 1, res.Errors)
+	assert.Equal(t, []string{"KASAN: use-after-free in foo"}, res.CrashTitles)
+}
+
+type testRunner struct {
+	results chan *Result
+}
+
+func (r *testRunner) Run(req *Request, logf func(msg string, args ...any)) (*Result, error) {
+	logf("testing %s", req.Patch)
+	res := <-r.results
+	if res == nil {
+		return nil, fmt.Errorf("runner failed")
+	}
+	return res, nil
+}
+
+func TestService(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	assert.NoError(t, err)
+	runner := &testRunner{results: make(chan *Result)}
+	service, err := NewService(runner, storage)
+	assert.NoError(t, err)
+	stop := make(chan struct{})
+	defer close(stop)
+	go service.Loop(stop)
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	submit := func(req *Request) (*Test, int) {
+		data, err := json.Marshal(req)
+		assert.NoError(t, err)
+		resp, err := http.Post(server.URL+"/api/tests", "application/json", bytes.NewReader(data))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		test := new(Test)
+		if resp.StatusCode == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(test))
+		}
+		return test, resp.StatusCode
+	}
+	get := func(id string) (*Test, int) {
+		resp, err := http.Get(server.URL + "/api/tests/" + id)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		test := new(Test)
+		if resp.StatusCode == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(test))
+		}
+		return test, resp.StatusCode
+	}
+	waitStatus := func(id string, status Status) *Test {
+		for i := 0; i < 1000; i++ {
+			test, _ := get(id)
+			if test.Status == status {
+				return test
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("test %v did not reach status %v", id, status)
+		return nil
+	}
+
+	_, code := submit(&Request{Patch: []byte("patch")})
+	assert.Equal(t, http.StatusBadRequest, code)
+	_, code = get("nonexistent")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	test1, code := submit(&Request{Patch: []byte("patch1"), ReproC: []byte("repro")})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusQueued, test1.Status)
+	test2, _ := submit(&Request{Patch: []byte("patch2"), ReproSyz: []byte("repro")})
+
+	waitStatus(test1.ID, StatusRunning)
+	runner.results <- &Result{Verdict: VerdictFixed, Patched: &KernelResult{Runs: 3}}
+	test := waitStatus(test1.ID, StatusFinished)
+	assert.Equal(t, VerdictFixed, test.Result.Verdict)
+	assert.Contains(t, test.Log, "testing patch1")
+
+	waitStatus(test2.ID, StatusRunning)
+	runner.results <- nil
+	test = waitStatus(test2.ID, StatusFailed)
+	assert.Equal(t, "runner failed", test.Error)
+
+	tests, err := storage.List()
+	assert.NoError(t, err)
+	assert.Len(t, tests, 2)
+	assert.Equal(t, test1.ID, tests[0].ID)
+}
+
+func TestServiceRestart(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	assert.NoError(t, err)
+	for i, status := range []Status{StatusQueued, StatusRunning, StatusFinished} {
+		assert.NoError(t, storage.Save(&Test{
+			ID:      fmt.Sprint(i),
+			Request: &Request{Patch: []byte("patch"), ReproC: []byte("repro")},
+			Status:  status,
+			Created: time.Now(),
+		}))
+	}
+	service, err := NewService(&testRunner{}, storage)
+	assert.NoError(t, err)
+	assert.Len(t, service.queue, 2)
+}
+
+func TestServiceQueueLimit(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	assert.NoError(t, err)
+	// More unfinished tests than the queue limit must not block the service creation.
+	for i := 0; i <= maxQueuedTests; i++ {
+		assert.NoError(t, storage.Save(&Test{
+			ID:      fmt.Sprint(i),
+			Request: &Request{Patch: []byte("patch"), ReproC: []byte("repro")},
+			Status:  StatusQueued,
+			Created: time.Now(),
+		}))
+	}
+	service, err := NewService(&testRunner{}, storage)
+	assert.NoError(t, err)
+	assert.Len(t, service.queue, maxQueuedTests+1)
+	// The rejected test must not be saved.
+	_, err = service.Submit(&Request{Patch: []byte("patch"), ReproC: []byte("repro")})
+	assert.Error(t, err)
+	tests, err := storage.List()
+	assert.NoError(t, err)
+	assert.Len(t, tests, maxQueuedTests+1)
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package patchtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+)
+
+// Service executes patch testing requests one by one and serves the HTTP API:
+//
+//	POST /api/tests      - submit a Request, returns the created Test
+//	GET  /api/tests      - list all tests (without logs)
+//	GET  /api/tests/<id> - get a test with its result and log
+type Service struct {
+	runner  Runner
+	storage Storage
+	// Protects queue and the storage writes.
+	mu    sync.Mutex
+	queue []string
+	// Receives a value when a test is added to the empty queue.
+	wakeup chan struct{}
+}
+
+// Submit rejects new tests if there are already that many tests in the queue.
+const maxQueuedTests = 1000
+
+func NewService(runner Runner, storage Storage) (*Service, error) {
+	s := &Service{
+		runner:  runner,
+		storage: storage,
+		wakeup:  make(chan struct{}, 1),
+	}
+	// Re-queue tests that were not finished before restart.
+	tests, err := storage.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, test := range tests {
+		if test.Status == StatusQueued || test.Status == StatusRunning {
+			s.queue = append(s.queue, test.ID)
+		}
+	}
+	return s, nil
+}
+
+func (s *Service) Submit(req *Request) (*Test, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	test := &Test{
+		ID:      hex.EncodeToString(id),
+		Request: req,
+		Status:  StatusQueued,
+		Created: time.Now(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Check the limit before saving the test, otherwise a rejected test would run after a restart.
+	if len(s.queue) >= maxQueuedTests {
+		return nil, fmt.Errorf("too many queued tests")
+	}
+	if err := s.storage.Save(test); err != nil {
+		return nil, err
+	}
+	s.queue = append(s.queue, test.ID)
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+	return test, nil
+}
+
+// Loop processes queued tests until stop is closed.
+func (s *Service) Loop(stop <-chan struct{}) {
+	for {
+		id := s.next()
+		if id == "" {
+			select {
+			case <-stop:
+				return
+			case <-s.wakeup:
+			}
+			continue
+		}
+		if err := s.process(id); err != nil {
+			log.Errorf("patch test %v: %v", id, err)
+		}
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
+}
+
+func (s *Service) next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return ""
+	}
+	id := s.queue[0]
+	s.queue = s.queue[1:]
+	return id
+}
+
+func (s *Service) process(id string) error {
+	test, err := s.storage.Load(id)
+	if err != nil {
+		return err
+	}
+	var logBuf strings.Builder
+	var logMu sync.Mutex
+	logf := func(msg string, args ...any) {
+		line := fmt.Sprintf(msg, args...)
+		log.Logf(0, "patch test %v: %v", id, line)
+		logMu.Lock()
+		fmt.Fprintf(&logBuf, "%v: %v\n", time.Now().Format(time.DateTime), line)
+		logMu.Unlock()
+	}
+	test.Status = StatusRunning
+	test.Started = time.Now()
+	if err := s.save(test); err != nil {
+		return err
+	}
+	res, err := s.runner.Run(test.Request, logf)
+	test.Finished = time.Now()
+	test.Result = res
+	if err != nil {
+		test.Status = StatusFailed
+		test.Error = err.Error()
+		logf("failed: %v", err)
+	} else {
+		test.Status = StatusFinished
+	}
+	logMu.Lock()
+	test.Log = logBuf.String()
+	logMu.Unlock()
+	return s.save(test)
+}
+
+func (s *Service) save(test *Test) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.storage.Save(test)
+}
+
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tests"), "/")
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		req := new(Request)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, fmt.Sprintf("failed to parse request: %v", err), http.StatusBadRequest)
+			return
+		}
+		test, err := s.Submit(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, test)
+	case r.Method == http.MethodGet && path == "":
+		tests, err := s.storage.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, test := range tests {
+			test.Log = ""
+		}
+		writeJSON(w, tests)
+	case r.Method == http.MethodGet:
+		test, err := s.storage.Load(path)
+		if errors.Is(err, ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, test)
+	default:
+		http.Error(w, "unsupported request", http.StatusBadRequest)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package patchtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+type Status string
+
+const (
+	StatusQueued   Status = "queued"
+	StatusRunning  Status = "running"
+	StatusFinished Status = "finished"
+	StatusFailed   Status = "failed"
+)
+
+// Test is a patch testing request along with its state and result.
+type Test struct {
+	ID       string
+	Request  *Request
+	Status   Status
+	Created  time.Time
+	Started  time.Time `json:",omitempty"`
+	Finished time.Time `json:",omitempty"`
+	// Error is set for StatusFailed.
+	Error  string  `json:",omitempty"`
+	Result *Result `json:",omitempty"`
+	Log    string  `json:",omitempty"`
+}
+
+var ErrNotFound = errors.New("test not found")
+
+// Storage persists tests.
+type Storage interface {
+	Save(test *Test) error
+	// Load returns ErrNotFound if there is no such test.
+	Load(id string) (*Test, error)
+	// List returns all tests ordered by creation time.
+	List() ([]*Test, error)
+}
+
+// FileStorage stores each test as a JSON file in a directory.
+type FileStorage struct {
+	dir string
+}
+
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+func (fs *FileStorage) Save(test *Test) error {
+	data, err := json.MarshalIndent(test, "", "\t")
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFileAtomically(fs.file(test.ID), data)
+}
+
+func (fs *FileStorage) Load(id string) (*Test, error) {
+	if id == "" || strings.ContainsAny(id, "/\\.") {
+		return nil, ErrNotFound
+	}
+	data, err := os.ReadFile(fs.file(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	test := new(Test)
+	if err := json.Unmarshal(data, test); err != nil {
+		return nil, fmt.Errorf("failed to parse test %v: %w", id, err)
+	}
+	return test, nil
+}
+
+func (fs *FileStorage) List() ([]*Test, error) {
+	files, err := filepath.Glob(filepath.Join(fs.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var tests []*Test
+	for _, file := range files {
+		test, err := fs.Load(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Created.Before(tests[j].Created)
+	})
+	return tests, nil
+}
+
+func (fs *FileStorage) file(id string) string {
+	return filepath.Join(fs.dir, id+".json")
+}
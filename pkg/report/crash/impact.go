@@ -0,0 +1,55 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package crash
+
+// impactOrder represent an ordering of bug impact severity. The earlier
+// entries are considered more severe.
+var impactOrder = []Type{
+	// Highest Priority (Direct Memory Corruption - Write)
+	KASANUseAfterFreeWrite,
+	KASANWrite,
+	// High Priority (Memory Corruption)
+	KASANInvalidFree,
+	KFENCEInvalidFree,
+	KFENCEMemoryCorruption,
+	KASANUseAfterFreeRead,
+	KMSANUseAfterFreeRead,
+	KASANRead,
+	KFENCERead,
+	MemorySafetyUBSAN, // array-index-out-of-bounds, at least Read.
+	KCSANAssert,
+	RefcountWARNING, // we had a few UAFs in the past
+	KASANNullPtrDerefWrite,
+	KASANNullPtrDerefRead,
+	NullPtrDerefBUG,
+	// Medium Priority (Infoleaks, Uninitialized Memory, Corruptions)
+	KMSANInfoLeak,
+	MemorySafetyBUG,
+	KMSANUninitValue,
+	// Medium Priority (Concurrency and Severe Instability)
+	KCSANDataRace,
+	AtomicSleep, // high potential for system-wide deadlocks
+	LockdepBug,  // indicates potential deadlocks and hangs
+	// Lower-Medium Priority (Denial of Service and General Bugs)
+	MemoryLeak, // a form of DoS
+	RustPanic,  // checked arithmetic and bounds, the kernel stops before corrupting memory
+	DoS,
+	Hang,
+	// Unknown types shouldn't be mentioned here. If bug goes to Unknown it means we need better parsing/processing.
+	// You can find them at the end of the scored list on the bug enumeration pages.
+	// KMSANUnknown
+	// KASANUnknown
+	// KCSANUnknown
+}
+
+// Impact returns the impact score of the crash type. A higher score indicates a more severe impact.
+// -1 means the type is not ranked in impactOrder.
+func (t Type) Impact() int {
+	for i, typ := range impactOrder {
+		if t == typ {
+			return len(impactOrder) - i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,20 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package crash
+
+import (
+	"testing"
+)
+
+func TestImpact(t *testing.T) {
+	if got := Hang.Impact(); got != 1 {
+		t.Errorf("%q.Impact() = %d, want 1", Hang, got)
+	}
+	if got := Warning.Impact(); got != -1 {
+		t.Errorf("%q.Impact() = %d, want -1", Warning, got)
+	}
+	if KASANWrite.Impact() <= KCSANDataRace.Impact() {
+		t.Errorf("KASAN writes must have higher impact than data races")
+	}
+}
@@ -42,6 +42,7 @@ const (
 	MemorySafetyUBSAN       = Type("MEMORY_SAFETY_UBSAN")
 	NullPtrDerefBUG         = Type("NULL-POINTER-DEREFERENCE")
 	RefcountWARNING         = Type("REFCOUNT_WARNING")
+	RustPanic               = Type("RUST_PANIC")
 	UBSAN                   = Type("UBSAN")
 	Warning                 = Type("WARNING")
 	// keep-sorted end
@@ -4,7 +4,6 @@
 package report
 
 import (
-	"slices"
 	"testing"
 
 	"github.com/google/syzkaller/pkg/report/crash"
@@ -50,6 +49,6 @@ func TestTitlesToImpact2(t *testing.T) {
 	if got == 1 { // lowest priority we can think about (crash.Hang)
 		t.Errorf("report.TitlesToImpact(%q, %q) = %d, want %d",
 			testHangTitle, testKASANInvalidFreeTitle,
-			got, len(impactOrder)-slices.Index(impactOrder, crash.KASANInvalidFree))
+			got, crash.KASANInvalidFree.Impact())
 	}
 }
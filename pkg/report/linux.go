@@ -108,6 +108,7 @@ func ctorLinux(cfg *config) (reporterImpl, []string, error) {
 		regexp.MustCompile(`^net/core/sock.c`),
 		regexp.MustCompile(`^net/core/skbuff.c`),
 		regexp.MustCompile(`^fs/proc/generic.c`),
+		regexp.MustCompile(`^rust/`),                  // Rust kernel crate and helpers, the bug is in their users.
 		regexp.MustCompile(`^trusty/`),                // Trusty sources are not in linux kernel tree.
 		regexp.MustCompile(`^drivers/usb/core/urb.c`), // WARNING in urb.c usually means a bug in a driver
 	}
@@ -1438,6 +1439,26 @@ func warningStackFmt(skip ...string) *stackFmt {
 	}
 }
 
+// rustPanicPrefix matches the beginning of a Rust panic message, which is either printed
+// on the line following the panic location, or quoted on the same line in older versions.
+const rustPanicPrefix = "rust_kernel: panicked at (?:'|[^\n]*\n)"
+
+func rustPanicStackFmt() *stackFmt {
+	return &stackFmt{
+		parts: []*regexp.Regexp{
+			linuxCallTrace,
+			parseStackTrace,
+		},
+		skip: []string{
+			"rust_begin_unwind",
+			// Frames of the Rust core library (unwrap_failed, slice_end_index_len_fail, etc)
+			// only report the failure and are never guilty.
+			"^<?core::",
+			"^rust_helper_",
+		},
+	}
+}
+
 // nolint: lll
 var linuxOopses = append([]*oops{
 	{
@@ -2268,19 +2289,36 @@ var linuxOopses = append([]*oops{
 	{
 		[]byte("rust_kernel: panicked"),
 		[]oopsFormat{
+			// Panic messages of the Rust core library contain lengths and indices,
+			// strip them from titles so that the same bugs are deduplicated.
+			{
+				title: compile(rustPanicPrefix + "index out of bounds:"),
+				fmt:   "index out of bounds in %[1]v",
+				stack: rustPanicStackFmt(),
+			},
+			{
+				title: compile(rustPanicPrefix + "(?:range (?:start|end) index|slice index starts at) "),
+				fmt:   "slice index out of range in %[1]v",
+				stack: rustPanicStackFmt(),
+			},
+			{
+				title: compile(rustPanicPrefix + "called `Result::unwrap\\(\\)` on an `Err` value"),
+				fmt:   "called `Result::unwrap()` on an `Err` value in %[1]v",
+				stack: rustPanicStackFmt(),
+			},
+			{
+				// Older Rust versions print the message on the same line:
+				// rust_kernel: panicked at 'message', file.rs:1:2
+				title:  compile("rust_kernel: panicked at '"),
+				report: compile("rust_kernel: panicked at '(.+?)', [^\n]*\\.rs:[0-9]+"),
+				fmt:    "%[1]v in %[2]v",
+				stack:  rustPanicStackFmt(),
+			},
 			{
 				title:  compile("rust_kernel: panicked"),
 				report: compile("rust_kernel: panicked at [^\n]*?\n(.+?)\n"),
 				fmt:    "%[1]v in %[2]v",
-				stack: &stackFmt{
-					parts: []*regexp.Regexp{
-						linuxCallTrace,
-						parseStackTrace,
-					},
-					skip: []string{
-						regexp.QuoteMeta(`__rustc::rust_begin_unwind`),
-					},
-				},
+				stack:  rustPanicStackFmt(),
 			},
 		},
 		[]*regexp.Regexp{},
@@ -37,6 +37,7 @@ type Reporter struct {
 	impl         reporterImpl
 	suppressions []*regexp.Regexp
 	interests    []*regexp.Regexp
+	triage       *triageRules
 }
 
 type Report struct {
@@ -72,6 +73,14 @@ type Report struct {
 	MachineInfo []byte
 	// If the crash happened in the context of the syz-executor process, Executor will hold more info.
 	Executor *ExecutorInfo
+	// Names of the triage rules that matched the report (filled in by Symbolize).
+	TriageRules []string
+	// Priority assigned by the triage rules, higher values are more important.
+	Priority int
+	// SkipRepro is set if the triage rules asked to never reproduce the crash.
+	SkipRepro bool
+	// Emails to notify about the crash as requested by the triage rules.
+	EmailAddrs []string
 	// reportPrefixLen is length of additional prefix lines that we added before actual crash report.
 	reportPrefixLen int
 	// symbolized is set if the report is symbolized.
@@ -145,6 +154,16 @@ func NewReporter(cfg *mgrconfig.Config) (*Reporter, error) {
 		suppressions: supps,
 		interests:    interests,
 	}
+	if cfg.TriageRules != "" {
+		rules, err := LoadTriageRules(cfg.TriageRules)
+		if err != nil {
+			return nil, err
+		}
+		reporter.triage, err = compileTriageRules(rules, cfg.TargetOS)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return reporter, nil
 }
 
@@ -228,13 +247,16 @@ func (reporter *Reporter) Symbolize(rep *Report) error {
 		panic("Symbolize is called twice")
 	}
 	rep.symbolized = true
-	if err := reporter.impl.Symbolize(rep); err != nil {
-		return err
-	}
-	if !reporter.isInteresting(rep) {
+	err := reporter.impl.Symbolize(rep)
+	if err == nil && !reporter.isInteresting(rep) {
 		rep.Suppressed = true
 	}
-	return nil
+	// The rules that match only the title, the type or the report text
+	// must take effect even if symbolization has failed.
+	if reporter.triage != nil {
+		reporter.triage.apply(rep)
+	}
+	return err
 }
 
 func (reporter *Reporter) isInteresting(rep *Report) bool {
@@ -858,10 +880,10 @@ func Truncate(log []byte, begin, end int) []byte {
 }
 
 var (
-	filenameRe    = regexp.MustCompile(`([a-zA-Z0-9_\-\./]*[a-zA-Z0-9_\-]+\.(c|h)):[0-9]+`)
+	filenameRe    = regexp.MustCompile(`([a-zA-Z0-9_\-\./]*[a-zA-Z0-9_\-]+\.(c|h|rs)):[0-9]+`)
 	reportFrameRe = regexp.MustCompile(`.* in ((?:<[a-zA-Z0-9_: ]+>)?[a-zA-Z0-9_:]+)`)
-	// Matches a slash followed by at least one directory nesting before .c/.h file.
-	deeperPathRe = regexp.MustCompile(`^/[a-zA-Z0-9_\-\./]+/[a-zA-Z0-9_\-]+\.(c|h)$`)
+	// Matches a slash followed by at least one directory nesting before .c/.h/.rs file.
+	deeperPathRe = regexp.MustCompile(`^/[a-zA-Z0-9_\-\./]+/[a-zA-Z0-9_\-]+\.(c|h|rs)$`)
 )
 
 // These are produced by syzkaller itself.
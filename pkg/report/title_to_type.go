@@ -273,6 +273,27 @@ var titleToType = []struct {
 		},
 		crashType: crash.DoS,
 	},
+	{
+		// Panics of the Rust core library checks, see rust_kernel oopses in linux.go.
+		includePrefixes: []string{
+			// keep-sorted start
+			"attempt to add with overflow",
+			"attempt to calculate the remainder with",
+			"attempt to divide by zero",
+			"attempt to divide with overflow",
+			"attempt to multiply with overflow",
+			"attempt to negate with overflow",
+			"attempt to shift left with overflow",
+			"attempt to shift right with overflow",
+			"attempt to subtract with overflow",
+			"called `Option::unwrap()` on a `None` value",
+			"called `Result::unwrap()` on an `Err` value",
+			"index out of bounds in",
+			"slice index out of range in",
+			// keep-sorted end
+		},
+		crashType: crash.RustPanic,
+	},
 	{
 		includePrefixes: []string{"unexpected kernel reboot"},
 		crashType:       crash.UnexpectedReboot,
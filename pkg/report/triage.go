@@ -0,0 +1,183 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/pkg/subsystem"
+	_ "github.com/google/syzkaller/pkg/subsystem/lists"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// TriageRule is a user-defined crash signature together with the actions
+// that must be taken for the crashes that match it.
+// All non-empty match conditions must hold for the rule to match.
+// Rules are evaluated in order, all matching rules are applied.
+// Title conditions are matched against the current title, so if a rule
+// retitles the crash, the subsequent rules see (and may retitle again) the new title.
+type TriageRule struct {
+	// Name identifies the rule in the manager UI.
+	Name string `json:"name"`
+
+	// Regexp matched against the crash title.
+	Title string `json:"title,omitempty"`
+	// Crash type as listed in pkg/report/crash, e.g. "KASAN-USE-AFTER-FREE-WRITE".
+	Type string `json:"type,omitempty"`
+	// Regexp matched against the guilty file.
+	GuiltyFile string `json:"guilty_file,omitempty"`
+	// Regexps that must all match the report text
+	// (e.g. names of functions that must be present in the stack trace).
+	Frames []string `json:"frames,omitempty"`
+	// Name of the kernel subsystem (see pkg/subsystem) the guilty file must belong to.
+	Subsystem string `json:"subsystem,omitempty"`
+
+	// Don't save the crash, but still reboot the VM.
+	Suppress bool `json:"suppress,omitempty"`
+	// New title for the crash. May refer to the capture groups
+	// of the Title regexp as $1, ${name}, etc.
+	Retitle string `json:"retitle,omitempty"`
+	// Priority of the crash, higher values are more important.
+	Priority int `json:"priority,omitempty"`
+	// Record the crash, but never try to reproduce it.
+	SkipRepro bool `json:"skip_repro,omitempty"`
+	// Emails that must be notified about the crash.
+	EmailAddrs []string `json:"email_addrs,omitempty"`
+}
+
+type triageRule struct {
+	*TriageRule
+	title      *regexp.Regexp
+	guiltyFile *regexp.Regexp
+	frames     []*regexp.Regexp
+	subsystem  *subsystem.Subsystem
+}
+
+type triageRules struct {
+	rules   []*triageRule
+	matcher *subsystem.PathMatcher
+}
+
+// LoadTriageRules reads the list of rules in JSON format from the file.
+func LoadTriageRules(file string) ([]*TriageRule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read triage rules: %w", err)
+	}
+	var rules []*TriageRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse triage rules %v: %w", file, err)
+	}
+	return rules, nil
+}
+
+func compileTriageRules(rules []*TriageRule, targetOS string) (*triageRules, error) {
+	ret := &triageRules{}
+	var subsystems *subsystem.Service
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule#%v", i)
+		}
+		compiled := &triageRule{TriageRule: rule}
+		var err error
+		if compiled.title, err = compileOptional(rule.Title); err != nil {
+			return nil, fmt.Errorf("triage rule %v: %w", rule.Name, err)
+		}
+		if compiled.guiltyFile, err = compileOptional(rule.GuiltyFile); err != nil {
+			return nil, fmt.Errorf("triage rule %v: %w", rule.Name, err)
+		}
+		if compiled.frames, err = compileRegexps(rule.Frames); err != nil {
+			return nil, fmt.Errorf("triage rule %v: %w", rule.Name, err)
+		}
+		if rule.Retitle != "" && compiled.title == nil {
+			return nil, fmt.Errorf("triage rule %v: retitle requires a title regexp", rule.Name)
+		}
+		if rule.Subsystem != "" {
+			if subsystems == nil {
+				list := subsystem.GetList(targetOS)
+				if len(list) == 0 {
+					return nil, fmt.Errorf("triage rule %v: no subsystems are known for %v", rule.Name, targetOS)
+				}
+				subsystems = subsystem.MustMakeService(list)
+				ret.matcher = subsystem.MakePathMatcher(list)
+			}
+			compiled.subsystem = subsystems.ByName(rule.Subsystem)
+			if compiled.subsystem == nil {
+				return nil, fmt.Errorf("triage rule %v: unknown subsystem %q", rule.Name, rule.Subsystem)
+			}
+		}
+		ret.rules = append(ret.rules, compiled)
+	}
+	return ret, nil
+}
+
+func compileOptional(re string) (*regexp.Regexp, error) {
+	if re == "" {
+		return nil, nil
+	}
+	return regexp.Compile(re)
+}
+
+// apply evaluates the rules against the report and updates it accordingly.
+// If the report could not be symbolized, the guilty file and subsystem conditions do not match.
+func (tr *triageRules) apply(rep *Report) {
+	var subsystems []*subsystem.Subsystem
+	if tr.matcher != nil && rep.GuiltyFile != "" {
+		subsystems = tr.matcher.Match(rep.GuiltyFile)
+	}
+	for _, rule := range tr.rules {
+		if !rule.match(rep, subsystems) {
+			continue
+		}
+		rep.TriageRules = append(rep.TriageRules, rule.Name)
+		rep.Suppressed = rep.Suppressed || rule.Suppress
+		rep.SkipRepro = rep.SkipRepro || rule.SkipRepro
+		rep.Priority = max(rep.Priority, rule.Priority)
+		if rule.Retitle != "" {
+			match := rule.title.FindStringSubmatchIndex(rep.Title)
+			newTitle := string(rule.title.ExpandString(nil, rule.Retitle, rep.Title, match))
+			if newTitle != rep.Title {
+				// Keep the original title as an alternative one for deduplication.
+				rep.AltTitles = append(rep.AltTitles, rep.Title)
+				rep.Title = newTitle
+			}
+		}
+		rep.EmailAddrs = append(rep.EmailAddrs, rule.EmailAddrs...)
+		rep.Recipients = append(rep.Recipients, vcs.NewRecipients(rule.EmailAddrs, vcs.To)...)
+	}
+}
+
+func (rule *triageRule) match(rep *Report, subsystems []*subsystem.Subsystem) bool {
+	if rule.title != nil && !rule.title.MatchString(rep.Title) {
+		return false
+	}
+	if rule.Type != "" && crash.Type(rule.Type) != rep.Type {
+		return false
+	}
+	if rule.guiltyFile != nil && (rep.GuiltyFile == "" || !rule.guiltyFile.MatchString(rep.GuiltyFile)) {
+		return false
+	}
+	for _, frame := range rule.frames {
+		if !frame.Match(rep.Report) {
+			return false
+		}
+	}
+	if rule.subsystem != nil {
+		found := false
+		for _, s := range subsystems {
+			if _, isParent := s.ReachableParents()[rule.subsystem]; s == rule.subsystem || isParent {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/pkg/vcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriageRules(t *testing.T) {
+	rules, err := compileTriageRules([]*TriageRule{
+		{
+			Name:      "ext4",
+			Subsystem: "fs",
+			Priority:  5,
+		},
+		{
+			Name:       "warnings",
+			Title:      "^WARNING in (.*)",
+			Retitle:    "WARN in $1",
+			SkipRepro:  true,
+			EmailAddrs: []string{"me@example.com"},
+		},
+		{
+			Type:     string(crash.KASANUseAfterFreeWrite),
+			Priority: 10,
+		},
+		{
+			Name:     "noisy",
+			Frames:   []string{"noisy_func"},
+			Suppress: true,
+		},
+	}, "linux")
+	require.NoError(t, err)
+
+	rep := &Report{
+		Title:      "WARNING in ext4_fill_super",
+		Type:       crash.Warning,
+		GuiltyFile: "fs/ext4/super.c",
+		Report:     []byte("WARNING: CPU: 0 PID: 1 at fs/ext4/super.c:123 ext4_fill_super+0x1/0x2\n"),
+	}
+	rules.apply(rep)
+	assert.Equal(t, &Report{
+		Title:       "WARN in ext4_fill_super",
+		AltTitles:   []string{"WARNING in ext4_fill_super"},
+		Type:        crash.Warning,
+		GuiltyFile:  "fs/ext4/super.c",
+		Report:      rep.Report,
+		TriageRules: []string{"ext4", "warnings"},
+		Priority:    5,
+		SkipRepro:   true,
+		EmailAddrs:  []string{"me@example.com"},
+		Recipients:  vcs.NewRecipients([]string{"me@example.com"}, vcs.To),
+	}, rep)
+
+	rep = &Report{
+		Title:      "KASAN: slab-use-after-free Write in noisy_func",
+		Type:       crash.KASANUseAfterFreeWrite,
+		GuiltyFile: "net/core/dev.c",
+		Report:     []byte("BUG: KASAN: slab-use-after-free in noisy_func+0x1/0x2\n"),
+	}
+	rules.apply(rep)
+	assert.Equal(t, []string{"rule#2", "noisy"}, rep.TriageRules)
+	assert.Equal(t, 10, rep.Priority)
+	assert.True(t, rep.Suppressed)
+	assert.False(t, rep.SkipRepro)
+}
+
+func TestTriageRulesErrors(t *testing.T) {
+	for _, rule := range []*TriageRule{
+		{Title: "("},
+		{GuiltyFile: "["},
+		{Frames: []string{"foo", "*"}},
+		{Retitle: "foo"},
+		{Subsystem: "no-such-subsystem"},
+	} {
+		_, err := compileTriageRules([]*TriageRule{rule}, "linux")
+		assert.Error(t, err, "rule: %+v", rule)
+	}
+	_, err := compileTriageRules([]*TriageRule{{Subsystem: "net"}}, "test")
+	assert.Error(t, err)
+}
+
+func TestTriageRulesChainedRetitle(t *testing.T) {
+	rules, err := compileTriageRules([]*TriageRule{
+		{Title: "^WARNING in (.*)", Retitle: "WARN in $1"},
+		{Title: "^WARN in (.*)_fill_super", Retitle: "WARN in $1 mount"},
+		{Title: "^WARNING", Priority: 1},
+	}, "linux")
+	require.NoError(t, err)
+	rep := &Report{Title: "WARNING in ext4_fill_super"}
+	rules.apply(rep)
+	assert.Equal(t, "WARN in ext4 mount", rep.Title)
+	assert.Equal(t, []string{"WARNING in ext4_fill_super", "WARN in ext4_fill_super"}, rep.AltTitles)
+	assert.Equal(t, []string{"rule#0", "rule#1"}, rep.TriageRules)
+	assert.Equal(t, 0, rep.Priority)
+}
+
+type failingSymbolizer struct {
+	reporterImpl
+}
+
+func (failingSymbolizer) Symbolize(rep *Report) error {
+	return errors.New("no symbols")
+}
+
+func TestTriageRulesSymbolizeError(t *testing.T) {
+	rules, err := compileTriageRules([]*TriageRule{
+		{Title: "^WARNING in (.*)", Retitle: "WARN in $1", SkipRepro: true},
+		{Frames: []string{"noisy_func"}, Suppress: true},
+	}, "linux")
+	require.NoError(t, err)
+	reporter := &Reporter{impl: failingSymbolizer{}, triage: rules}
+	rep := &Report{
+		Title:  "WARNING in noisy_func",
+		Report: []byte("WARNING: CPU: 0 PID: 1 at noisy_func+0x1/0x2\n"),
+	}
+	assert.Error(t, reporter.Symbolize(rep))
+	assert.Equal(t, "WARN in noisy_func", rep.Title)
+	assert.Equal(t, []string{"rule#0", "rule#1"}, rep.TriageRules)
+	assert.True(t, rep.SkipRepro)
+	assert.True(t, rep.Suppressed)
+}
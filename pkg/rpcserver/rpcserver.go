@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -112,6 +113,7 @@ type server struct {
 
 type Stats struct {
 	StatExecs      *stat.Val
+	StatVMExecs    *stat.Family
 	StatNumFuzzing *stat.Val
 	StatVMRestarts *stat.Val
 	StatModules    *stat.Val
@@ -121,16 +123,23 @@ func NewStats() Stats {
 	return NewNamedStats("")
 }
 
+// NewNamedStats creates stats for one of several managers running in the same process
+// (e.g. the base and the new kernel of the diff fuzzer). The name is shown on the web interface
+// and is exported to Prometheus as the manager label.
 func NewNamedStats(name string) Stats {
 	suffix, linkSuffix := "", ""
+	var labels []any
 	if name != "" {
 		suffix = " [" + name + "]"
 		linkSuffix = "?pool=" + url.QueryEscape(name)
+		labels = append(labels, stat.PrometheusLabel{Name: "manager", Value: name})
 	}
 	return Stats{
 		StatExecs: stat.New("exec total"+suffix, "Total test program executions",
-			stat.Console, stat.Rate{}, stat.Prometheus("syz_exec_total"+name),
+			append([]any{stat.Console, stat.Rate{}, stat.Prometheus("syz_exec_total")}, labels...)...,
 		),
+		StatVMExecs: stat.NewFamily("vm exec total"+suffix, "Test program executions per VM",
+			[]string{"vm"}, append([]any{stat.Rate{}, stat.Prometheus("syz_vm_exec_total")}, labels...)...),
 		StatNumFuzzing: stat.New("fuzzing VMs"+suffix,
 			"Number of VMs that are currently fuzzing", stat.Graph("fuzzing VMs"),
 			stat.Link("/vms"+linkSuffix),
@@ -548,11 +557,12 @@ func (serv *server) CreateInstance(id int, injectExec chan<- bool, updInfo dispa
 		executing:     make(map[int64]bool),
 		hanged:        make(map[int64]bool),
 		// Executor may report proc IDs that are larger than serv.cfg.Procs.
-		lastExec: MakeLastExecuting(prog.MaxPids, 6),
-		stats:    serv.runnerStats,
-		procs:    serv.cfg.Procs,
-		updInfo:  updInfo,
-		resultCh: make(chan error, 1),
+		lastExec:  MakeLastExecuting(prog.MaxPids, 6),
+		stats:     serv.runnerStats,
+		statExecs: serv.StatVMExecs.With(strconv.Itoa(id)),
+		procs:     serv.cfg.Procs,
+		updInfo:   updInfo,
+		resultCh:  make(chan error, 1),
 	}
 	serv.mu.Lock()
 	defer serv.mu.Unlock()
@@ -35,6 +35,7 @@ type Runner struct {
 	debugTimeouts bool
 	sysTarget     *targets.Target
 	stats         *runnerStats
+	statExecs     *stat.Val
 	finished      chan bool
 	injectExec    chan<- bool
 	infoc         chan chan []byte
@@ -360,6 +361,7 @@ func (runner *Runner) handleExecutingMessage(msg *flatrpc.ExecutingMessage) erro
 		return fmt.Errorf("got bad proc id %v", proc)
 	}
 	runner.stats.statExecs.Add(1)
+	runner.statExecs.Add(1)
 	if msg.Try == 0 {
 		if msg.WaitDuration != 0 {
 			runner.stats.statNoExecRequests.Add(1)
@@ -435,6 +435,48 @@ func testCover1(t *testing.T, ctx context.Context, target *prog.Target, test Cov
 	assert.ElementsMatch(t, test.Comps, call.Comps)
 }
 
+func TestCopyout(t *testing.T) {
+	// End-to-end test for collection of the values copied out by the calls.
+	// The first call copies out 2 resources from the overlay struct, the second one uses them.
+	t.Parallel()
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sysTarget := targets.Get(targets.TestOS, targets.TestArch64); sysTarget.BrokenCompiler != "" {
+		t.Skipf("skipping due to broken compiler:\n%v", sysTarget.BrokenCompiler)
+	}
+	p, err := target.Deserialize([]byte(`
+syz_compare(&AUTO="1111111122222222", AUTO, &AUTO=@overlay0={0x11111111, 0x22222222, <r0=>0x0, <r1=>0x0}, AUTO)
+overlay_uses(0x0, 0x0, r0, r1)
+`), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := csource.BuildExecutor(t, target, "../../")
+	source := queue.Plain()
+	ctx := startRPCServer(t, target, executor, source, rpcParams{})
+	for _, flags := range []flatrpc.ExecFlag{0, flatrpc.ExecFlagCollectCopyout} {
+		req := &queue.Request{
+			Prog: p,
+			ExecOpts: flatrpc.ExecOpts{
+				EnvFlags:  flatrpc.ExecEnvSandboxNone,
+				ExecFlags: flags,
+			},
+		}
+		source.Submit(req)
+		res := req.Wait(ctx)
+		if res.Err != nil || res.Info == nil || len(res.Info.Calls) != 2 || res.Info.Calls[0] == nil {
+			t.Fatalf("program execution failed: status=%v err=%v\n%s", res.Status, res.Err, res.Output)
+		}
+		want := []uint64{}
+		if flags != 0 {
+			want = []uint64{0x11111111, 0x22222222}
+		}
+		assert.Equal(t, want, res.Info.Calls[0].Copyout, "flags: %v", flags)
+	}
+}
+
 func makeCover64(pcs ...uint64) []byte {
 	w := new(bytes.Buffer)
 	binary.Write(w, binary.NativeEndian, uint64(len(pcs)))
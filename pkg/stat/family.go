@@ -0,0 +1,102 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Family is a set of metrics that differ only in label values (e.g. per-syscall or per-VM metrics).
+// Family metrics are only exported to Prometheus, they are not shown in the web interface.
+//
+//	statFoo := stat.NewFamily("metric name", "metric description", []string{"syscall"}, stat.Rate{})
+//	statFoo.With("open").Add(1)
+type Family struct {
+	name   string
+	desc   string
+	labels []string
+	opts   []any
+	metric string
+	rate   bool
+	consts []PrometheusLabel
+
+	mu   sync.Mutex
+	vals map[string]*familyVal
+}
+
+type familyVal struct {
+	labels []string
+	val    *Val
+}
+
+func NewFamily(name, desc string, labels []string, opts ...any) *Family {
+	return global.NewFamily(name, desc, labels, opts...)
+}
+
+func (s *set) NewFamily(name, desc string, labels []string, opts ...any) *Family {
+	if len(labels) == 0 {
+		panic(fmt.Sprintf("stat family %v has no labels", name))
+	}
+	for _, o := range opts {
+		if _, ok := o.(func() int); ok {
+			panic(fmt.Sprintf("stat family %v can't be in external mode", name))
+		}
+	}
+	proto := newVal(name, desc, opts)
+	f := &Family{
+		name:   name,
+		desc:   desc,
+		labels: labels,
+		opts:   opts,
+		metric: proto.metric,
+		rate:   proto.rate,
+		consts: proto.labels,
+		vals:   make(map[string]*familyVal),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.families[name] = f
+	return f
+}
+
+// With returns the metric for the given label values (in the order of labels passed to NewFamily).
+func (f *Family) With(values ...string) *Val {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("stat family %v: got %v label values, want %v", f.name, len(values), len(f.labels)))
+	}
+	key := strings.Join(values, "\x00")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fv := f.vals[key]
+	if fv == nil {
+		fv = &familyVal{
+			labels: values,
+			val:    newVal(f.name, f.desc, f.opts),
+		}
+		f.vals[key] = fv
+	}
+	return fv.val
+}
+
+func (f *Family) sortedVals() []*familyVal {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var res []*familyVal
+	for _, fv := range f.vals {
+		res = append(res, fv)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].labels, res[j].labels
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return res
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/VividCortex/gohistogram"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Graph history can be persisted across process restarts with SaveHistory/LoadHistory.
+// Only complete history points are saved, and metric values themselves are not restored
+// (they start from zero after restart as before), so the restored graphs continue
+// from the last saved point and the downtime is not shown on the graphs.
+
+type historyFile struct {
+	Size   int
+	Pos    int
+	Scale  int
+	Graphs []historyGraph
+}
+
+type historyGraph struct {
+	Title   string
+	Level   Level
+	Stacked bool
+	Lines   []historyLine
+}
+
+type historyLine struct {
+	Name string
+	Desc string
+	Rate bool
+	Dist bool
+	Data []float64 `json:",omitempty"`
+	// Histograms can't be serialized, so we save only the quantiles that are shown on graphs.
+	Hist [][]float64 `json:",omitempty"`
+}
+
+var histQuantiles = []float64{0.1, 0.5, 0.9}
+
+// SaveHistory atomically writes the current graph history to the file.
+func SaveHistory(file string) error {
+	return global.saveHistory(file)
+}
+
+// LoadHistory restores graph history previously saved with SaveHistory.
+// Points older than retention are dropped (0 means no limit).
+func LoadHistory(file string, retention time.Duration) error {
+	return global.loadHistory(file, retention)
+}
+
+// ReadHistory returns graphs from the history file saved with SaveHistory.
+func ReadHistory(file string) ([]UIGraph, error) {
+	hf, err := readHistoryFile(file)
+	if err != nil {
+		return nil, err
+	}
+	s := newSet(hf.Size, false)
+	if err := s.restoreHistory(hf, 0); err != nil {
+		return nil, err
+	}
+	return s.RenderGraphs(), nil
+}
+
+func (s *set) saveHistory(file string) error {
+	s.mu.Lock()
+	hf := &historyFile{
+		Size:  s.historySize,
+		Pos:   s.historyPos,
+		Scale: s.historyScale,
+	}
+	for title, graph := range s.graphs {
+		hg := historyGraph{
+			Title:   title,
+			Level:   graph.level,
+			Stacked: graph.stacked,
+		}
+		var lines []*line
+		for _, ln := range graph.lines {
+			lines = append(lines, ln)
+		}
+		sort.Slice(lines, func(i, j int) bool {
+			return lines[i].order < lines[j].order
+		})
+		for _, ln := range lines {
+			hl := historyLine{
+				Name: ln.name,
+				Desc: ln.desc,
+				Rate: ln.rate,
+			}
+			if ln.hist == nil {
+				hl.Data = append([]float64{}, ln.data[:s.historyPos]...)
+			} else {
+				hl.Dist = true
+				hl.Hist = make([][]float64, s.historyPos)
+				for i, hist := range ln.hist[:s.historyPos] {
+					if hist == nil {
+						continue
+					}
+					for _, q := range histQuantiles {
+						hl.Hist[i] = append(hl.Hist[i], hist.Quantile(q))
+					}
+				}
+			}
+			hg.Lines = append(hg.Lines, hl)
+		}
+		hf.Graphs = append(hf.Graphs, hg)
+	}
+	s.mu.Unlock()
+	sort.Slice(hf.Graphs, func(i, j int) bool {
+		return hf.Graphs[i].Title < hf.Graphs[j].Title
+	})
+	data, err := json.Marshal(hf)
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFileAtomically(file, data)
+}
+
+func (s *set) loadHistory(file string, retention time.Duration) error {
+	hf, err := readHistoryFile(file)
+	if err != nil {
+		return err
+	}
+	return s.restoreHistory(hf, retention)
+}
+
+func readHistoryFile(file string) (*historyFile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	hf := new(historyFile)
+	if err := json.Unmarshal(data, hf); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", file, err)
+	}
+	if hf.Scale <= 0 || hf.Pos < 0 || hf.Pos > hf.Size {
+		return nil, fmt.Errorf("corrupted history file %v", file)
+	}
+	return hf, nil
+}
+
+func (s *set) restoreHistory(hf *historyFile, retention time.Duration) error {
+	if hf.Size != s.historySize {
+		return fmt.Errorf("history size mismatch: %v vs %v", hf.Size, s.historySize)
+	}
+	drop := 0
+	if retention > 0 {
+		keep := int(retention / (time.Duration(hf.Scale) * tickPeriod))
+		drop = max(0, hf.Pos-keep)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.historyPos = hf.Pos - drop
+	s.historyScale = hf.Scale
+	s.historyTicks = 0
+	for _, hg := range hf.Graphs {
+		g := s.graphs[hg.Title]
+		if g == nil {
+			g = &graph{
+				lines: make(map[string]*line),
+			}
+			s.graphs[hg.Title] = g
+		}
+		g.level = max(g.level, hg.Level)
+		g.stacked = g.stacked || hg.Stacked
+		for _, hl := range hg.Lines {
+			ln := &line{
+				name:  hl.Name,
+				desc:  hl.Desc,
+				order: s.nextOrder.Add(1),
+				rate:  hl.Rate,
+			}
+			if hl.Dist {
+				if len(hl.Hist) != hf.Pos {
+					return fmt.Errorf("corrupted history for %v", hl.Name)
+				}
+				ln.hist = make([]*gohistogram.NumericHistogram, s.historySize)
+				for i, quantiles := range hl.Hist[drop:] {
+					if len(quantiles) == 0 {
+						continue
+					}
+					// A histogram with exactly these values has the same 10/50/90% quantiles.
+					ln.hist[i] = gohistogram.NewHistogram(histogramBuckets)
+					for _, v := range quantiles {
+						ln.hist[i].Add(v)
+					}
+				}
+			} else {
+				if len(hl.Data) != hf.Pos {
+					return fmt.Errorf("corrupted history for %v", hl.Name)
+				}
+				ln.data = make([]float64, s.historySize)
+				copy(ln.data, hl.Data[drop:])
+			}
+			g.lines[hl.Name] = ln
+		}
+	}
+	return nil
+}
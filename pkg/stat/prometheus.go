@@ -0,0 +1,184 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusHandler serves all metrics (and the standard Go runtime metrics) in the Prometheus text
+// or OpenMetrics format depending on the Accept request header.
+//
+// Metrics are exported under the name given with the Prometheus option, or under "syz_" + metric name
+// with all non-alphanumeric characters replaced with '_' (and "_total" appended for counters).
+// Rate metrics are exported as counters, Distribution metrics as histograms, and all other metrics as gauges.
+// The labels are added to all metrics that don't have a label with the same name
+// (e.g. the manager name, while the diff fuzzer per-pool metrics carry their own pool name).
+func PrometheusHandler(labels ...PrometheusLabel) http.Handler {
+	return global.prometheusHandler(labels, prometheus.DefaultGatherer)
+}
+
+func (s *set) prometheusHandler(labels []PrometheusLabel, gatherers ...prometheus.Gatherer) http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collector{s, labels})
+	return promhttp.HandlerFor(append(prometheus.Gatherers{reg}, gatherers...), promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+}
+
+// exportBuckets are upper bounds of exported histogram buckets: 1, 4, 16, ..., 4^15 (~1e9).
+var exportBuckets = prometheus.ExponentialBuckets(1, 4, 16)
+
+// collector is an unchecked prometheus.Collector: the set of metrics is not known in advance.
+type collector struct {
+	s      *set
+	labels []PrometheusLabel
+}
+
+func (c collector) Describe(chan<- *prometheus.Desc) {}
+
+func (c collector) Collect(ch chan<- prometheus.Metric) {
+	c.s.mu.Lock()
+	var vals []*Val
+	for _, v := range c.s.vals {
+		vals = append(vals, v)
+	}
+	var families []*Family
+	for _, f := range c.s.families {
+		families = append(families, f)
+	}
+	c.s.mu.Unlock()
+	sort.Slice(vals, func(i, j int) bool {
+		return vals[i].name < vals[j].name
+	})
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+	// Names are derived from free-form metric names, so they can collide.
+	// Duplicates would fail the whole scrape, so we export only the first one.
+	exported := make(exportedMetrics)
+	for _, v := range vals {
+		name := metricName(v.name, v.metric, v.rate)
+		constLabels := c.constLabels(v.labels, nil)
+		if !exported.add(name, constLabels, nil) {
+			continue
+		}
+		desc := prometheus.NewDesc(name, v.desc, nil, constLabels)
+		if m, err := v.prometheusMetric(desc, nil); err == nil {
+			ch <- m
+		}
+	}
+	for _, f := range families {
+		name := metricName(f.name, f.metric, f.rate)
+		var labels []string
+		for _, label := range f.labels {
+			labels = append(labels, sanitizeMetricName(label))
+		}
+		constLabels := c.constLabels(f.consts, labels)
+		if !exported.add(name, constLabels, labels) {
+			continue
+		}
+		desc := prometheus.NewDesc(name, f.desc, labels, constLabels)
+		for _, fv := range f.sortedVals() {
+			if m, err := fv.val.prometheusMetric(desc, fv.labels); err == nil {
+				ch <- m
+			}
+		}
+	}
+}
+
+func (c collector) constLabels(own []PrometheusLabel, variable []string) prometheus.Labels {
+	res := make(prometheus.Labels)
+	for _, label := range c.labels {
+		if !slices.Contains(variable, label.Name) {
+			res[label.Name] = label.Value
+		}
+	}
+	for _, label := range own {
+		res[label.Name] = label.Value
+	}
+	return res
+}
+
+// exportedMetrics maps metric names to the label names and the set of the exported constant label values.
+type exportedMetrics map[string]*exportedMetric
+
+type exportedMetric struct {
+	labels string
+	values map[string]bool
+}
+
+// add returns false if the metric can't be exported: metrics with the same name must have
+// the same label names, and must differ in the constant label values.
+func (e exportedMetrics) add(name string, constLabels prometheus.Labels, variable []string) bool {
+	var names, values []string
+	for label, value := range constLabels {
+		names = append(names, label)
+		values = append(values, label+"="+value)
+	}
+	names = append(names, variable...)
+	sort.Strings(names)
+	sort.Strings(values)
+	labels, key := strings.Join(names, ","), strings.Join(values, ",")
+	m := e[name]
+	if m == nil {
+		m = &exportedMetric{labels: labels, values: make(map[string]bool)}
+		e[name] = m
+	}
+	if m.labels != labels || m.values[key] {
+		return false
+	}
+	m.values[key] = true
+	return true
+}
+
+func (v *Val) prometheusMetric(desc *prometheus.Desc, labels []string) (prometheus.Metric, error) {
+	if v.hist {
+		v.histMu.Lock()
+		defer v.histMu.Unlock()
+		buckets := make(map[float64]uint64)
+		total := uint64(0)
+		for i, bound := range exportBuckets {
+			if v.histBuckets != nil {
+				total += v.histBuckets[i]
+			}
+			buckets[bound] = total
+		}
+		return prometheus.NewConstHistogram(desc, v.histCount, v.histSum, buckets, labels...)
+	}
+	typ := prometheus.GaugeValue
+	if v.rate {
+		typ = prometheus.CounterValue
+	}
+	return prometheus.NewConstMetric(desc, typ, float64(v.Val()), labels...)
+}
+
+func metricName(name, metric string, counter bool) string {
+	if metric != "" {
+		return sanitizeMetricName(metric)
+	}
+	name = "syz_" + sanitizeMetricName(name)
+	// OpenMetrics requires counter names to end with _total.
+	if counter && !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+	return name
+}
+
+var nonMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+func sanitizeMetricName(name string) string {
+	name = strings.Trim(nonMetricChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
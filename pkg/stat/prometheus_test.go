@@ -0,0 +1,75 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var flagUpdate = flag.Bool("update", false, "update golden files")
+
+func TestPrometheus(t *testing.T) {
+	set := newSet(4, false)
+	set.New("exec total", "Total executions", Rate{}, Prometheus("syz_exec_total")).Add(10)
+	set.New("corpus", "Corpus size", func() int { return 42 })
+	set.New("crashes [pool-1]", "Crashes in a pool").Add(3)
+	// Collides with the metric above, must not break the scrape.
+	set.New("crashes (pool 1)", "Crashes in a pool").Add(4)
+	latency := set.New("latency", "Request latency (ms)", Distribution{})
+	for _, v := range []int{0, 1, 3, 10, 100, 1000, 1 << 40} {
+		latency.Add(v)
+	}
+	execs := set.NewFamily("vm execs", "Executions per VM", []string{"vm"}, Rate{})
+	execs.With("1").Add(5)
+	execs.With("0").Add(2)
+	execs.With("1").Add(1)
+	overflows := set.NewFamily("cover overflows", "Coverage overflows", []string{"syscall", "buffer"},
+		Rate{}, Prometheus("syz_cover_overflows_total"))
+	overflows.With("open", "cover").Add(1)
+	overflows.With("read", "comps").Add(2)
+	times := set.NewFamily("exec time", "Execution time per syscall", []string{"syscall"}, Distribution{})
+	times.With("open").Add(7)
+	// Per-pool metrics are exported as one metric with the manager label.
+	set.New("pool execs [base]", "Pool executions", Rate{}, Prometheus("syz_pool_exec_total"),
+		PrometheusLabel{Name: "manager", Value: "base"}).Add(1)
+	set.New("pool execs [new]", "Pool executions", Rate{}, Prometheus("syz_pool_exec_total"),
+		PrometheusLabel{Name: "manager", Value: "new"}).Add(2)
+	// The variable label takes precedence over the handler label.
+	syncs := set.NewFamily("hub syncs", "Syncs per manager", []string{"manager"}, Rate{})
+	syncs.With("ci-1").Add(1)
+	// Family metrics are not shown in the UI.
+	assert.Len(t, set.Collect(All), 7)
+	assert.Panics(t, func() { execs.With("0", "1") })
+	assert.Panics(t, func() { set.NewFamily("bad", "desc", []string{"vm"}, func() int { return 0 }) })
+
+	for _, test := range []struct {
+		accept string
+		golden string
+	}{
+		{"text/plain", "prometheus.golden"},
+		{"application/openmetrics-text", "openmetrics.golden"},
+	} {
+		t.Run(test.golden, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			req.Header.Set("Accept", test.accept)
+			w := httptest.NewRecorder()
+			set.prometheusHandler([]PrometheusLabel{{Name: "manager", Value: "ci"}}).ServeHTTP(w, req)
+			assert.Equal(t, 200, w.Code)
+			golden := filepath.Join("testdata", test.golden)
+			if *flagUpdate {
+				assert.NoError(t, os.MkdirAll("testdata", 0755))
+				assert.NoError(t, os.WriteFile(golden, w.Body.Bytes(), 0644))
+			}
+			want, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), w.Body.String())
+		})
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/VividCortex/gohistogram"
-	"github.com/prometheus/client_golang/prometheus"
 )
 
 // This file provides prometheus/streamz style metrics (Val type) for instrumenting code for monitoring.
@@ -54,6 +53,7 @@ var global = newSet(256, true)
 type set struct {
 	mu           sync.Mutex
 	vals         map[string]*Val
+	families     map[string]*Family
 	graphs       map[string]*graph
 	nextOrder    atomic.Uint64
 	totalTicks   int
@@ -86,6 +86,7 @@ const (
 func newSet(histSize int, tick bool) *set {
 	s := &set{
 		vals:         make(map[string]*Val),
+		families:     make(map[string]*Family),
 		historySize:  histSize,
 		historyScale: 1,
 		graphs:       make(map[string]*graph),
@@ -146,9 +147,18 @@ const (
 // Link adds a hyperlink to metric name.
 type Link string
 
-// Prometheus exports the metric to Prometheus under the given name.
+// Prometheus sets the name the metric is exported to Prometheus under.
+// By default the name is derived from the metric name (see PrometheusHandler).
 type Prometheus string
 
+// PrometheusLabel attaches a constant label to the metric exported to Prometheus.
+// Metrics that have the same exported name and differ only in the label values
+// are exported as a single metric family (e.g. per-pool metrics of the diff fuzzer).
+type PrometheusLabel struct {
+	Name  string
+	Value string
+}
+
 // Rate says to collect/visualize metric rate per unit of time rather then total value.
 type Rate struct{}
 
@@ -184,14 +194,30 @@ func FormatMB(v int, period time.Duration) string {
 // and 'func(int, time.Duration) string' can be passed for custom formatting of the metric value.
 
 func (s *set) New(name, desc string, opts ...any) *Val {
+	v := newVal(name, desc, opts)
+	v.order = s.nextOrder.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vals[name] = v
+	if v.graph != "" {
+		if s.graphs[v.graph] == nil {
+			s.graphs[v.graph] = &graph{
+				lines: make(map[string]*line),
+			}
+		}
+		s.graphs[v.graph].level = max(s.graphs[v.graph].level, v.level)
+		s.graphs[v.graph].stacked = v.stacked
+	}
+	return v
+}
+
+func newVal(name, desc string, opts []any) *Val {
 	v := &Val{
 		name:  name,
 		desc:  desc,
 		graph: name,
-		order: s.nextOrder.Add(1),
 		fmt:   func(v int, period time.Duration) string { return strconv.Itoa(v) },
 	}
-	stacked := false
 	for _, o := range opts {
 		switch opt := o.(type) {
 		case Level:
@@ -202,7 +228,7 @@ func (s *set) New(name, desc string, opts ...any) *Val {
 			v.graph = string(opt)
 		case StackedGraph:
 			v.graph = string(opt)
-			stacked = true
+			v.stacked = true
 		case Rate:
 			v.rate = true
 			v.fmt = formatRate
@@ -213,29 +239,13 @@ func (s *set) New(name, desc string, opts ...any) *Val {
 		case func(int, time.Duration) string:
 			v.fmt = opt
 		case Prometheus:
-			// Prometheus Instrumentation https://prometheus.io/docs/guides/go-application.
-			prometheus.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
-				Name: string(opt),
-				Help: desc,
-			},
-				func() float64 { return float64(v.Val()) },
-			))
+			v.metric = string(opt)
+		case PrometheusLabel:
+			v.labels = append(v.labels, opt)
 		default:
 			panic(fmt.Sprintf("unknown stats option %#v", o))
 		}
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.vals[name] = v
-	if v.graph != "" {
-		if s.graphs[v.graph] == nil {
-			s.graphs[v.graph] = &graph{
-				lines: make(map[string]*line),
-			}
-		}
-		s.graphs[v.graph].level = max(s.graphs[v.graph].level, v.level)
-		s.graphs[v.graph].stacked = stacked
-	}
 	return v
 }
 
@@ -249,11 +259,18 @@ type Val struct {
 	val     atomic.Uint64
 	ext     func() int
 	fmt     func(int, time.Duration) string
+	metric  string
+	labels  []PrometheusLabel
+	stacked bool
 	rate    bool
 	hist    bool
 	prev    int
 	histMu  sync.Mutex
 	histVal *gohistogram.NumericHistogram
+	// Cumulative histogram state for export (histVal is reset on every history tick).
+	histCount   uint64
+	histSum     float64
+	histBuckets []uint64
 }
 
 func (v *Val) Add(val int) {
@@ -266,6 +283,14 @@ func (v *Val) Add(val int) {
 			v.histVal = gohistogram.NewHistogram(histogramBuckets)
 		}
 		v.histVal.Add(float64(val))
+		v.histCount++
+		v.histSum += float64(val)
+		if v.histBuckets == nil {
+			v.histBuckets = make([]uint64, len(exportBuckets))
+		}
+		if i := sort.SearchFloat64s(exportBuckets, float64(val)); i < len(exportBuckets) {
+			v.histBuckets[i]++
+		}
 		v.histMu.Unlock()
 		return
 	}
@@ -315,7 +340,8 @@ func (s *set) tick() {
 		}
 		graph := s.graphs[v.graph]
 		ln := graph.lines[v.name]
-		if ln == nil {
+		// The line may be restored from history for a metric of a different type.
+		if ln == nil || (ln.hist != nil) != v.hist {
 			ln = &line{
 				name:  v.name,
 				desc:  v.desc,
@@ -416,7 +442,6 @@ func (s *set) RenderGraphs() []UIGraph {
 			return lines[i].order < lines[j].order
 		})
 		g := UIGraph{
-			ID:      len(graphs),
 			Title:   title,
 			Stacked: graph.stacked,
 			Level:   graph.level,
@@ -452,5 +477,9 @@ func (s *set) RenderGraphs() []UIGraph {
 		}
 		return graphs[i].Title < graphs[j].Title
 	})
+	// Assign IDs after sorting to make them stable.
+	for i := range graphs {
+		graphs[i].ID = i
+	}
 	return graphs
 }
@@ -6,6 +6,7 @@ package stat
 import (
 	"fmt"
 	"math/rand"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -214,3 +215,46 @@ func TestSetStress(t *testing.T) {
 	time.Sleep(time.Second)
 	stop.Store(true)
 }
+
+func TestSetHistoryPersist(t *testing.T) {
+	a := assert.New(t)
+	set := newSet(4, false)
+	v0 := set.New("v0", "desc0", Graph("graph"))
+	v1 := set.New("v1", "desc1", Rate{}, StackedGraph("graph"))
+	v2 := set.New("v2", "desc2", Distribution{})
+	for i := 1; i <= 5; i++ {
+		v0.Add(i)
+		v1.Add(i)
+		v2.Add(i)
+		set.tick()
+	}
+	file := filepath.Join(t.TempDir(), "history.json")
+	a.NoError(set.saveHistory(file))
+
+	graphs, err := ReadHistory(file)
+	a.NoError(err)
+	a.Equal(set.RenderGraphs(), graphs)
+
+	// Metrics are re-created after restart and continue the restored history.
+	set1 := newSet(4, false)
+	a.NoError(set1.loadHistory(file, 0))
+	a.Equal(graphs, set1.RenderGraphs())
+	set1.New("v0", "desc0", Graph("graph")).Add(100)
+	set1.New("v1", "desc1", Rate{}, StackedGraph("graph")).Add(7)
+	// The metric type has changed, the history for it is dropped.
+	set1.New("v2", "desc2", Graph("v2"))
+	// Each point now covers 2 ticks.
+	set1.tick()
+	set1.tick()
+	a.Equal(set1.graphs["graph"].lines["v0"].data[:set1.historyPos], []float64{3, 10, 100})
+	a.Equal(set1.graphs["graph"].lines["v1"].data[:set1.historyPos], []float64{1.5, 3.5, 3.5})
+	a.Equal(set1.graphs["v2"].lines["v2"].data[:set1.historyPos], []float64{0, 0, 0})
+
+	// Each point covers 2 seconds, so only the last point fits into the retention period.
+	set2 := newSet(4, false)
+	a.NoError(set2.loadHistory(file, 3*time.Second))
+	a.Equal(1, set2.historyPos)
+	a.Equal(set2.graphs["graph"].lines["v0"].data[:set2.historyPos], []float64{10})
+
+	a.Error(newSet(8, false).loadHistory(file, 0))
+}
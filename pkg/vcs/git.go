@@ -460,6 +460,71 @@ func (git *gitRepo) Bisect(bad, good string, dt debugtracer.DebugTracer, pred fu
 	}
 }
 
+func (git *gitRepo) AddWorktree(dir string) error {
+	output, err := git.Run("worktree", "list", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(output, []byte("worktree "+dir+"\n")) && osutil.IsExist(dir) {
+		return nil
+	}
+	os.RemoveAll(dir)
+	git.Run("worktree", "prune")
+	_, err = git.Run("worktree", "add", "--detach", "--force", dir, HEAD)
+	return err
+}
+
+func (git *gitRepo) BisectCandidates(n int) ([]string, error) {
+	bad, err := git.Run("rev-parse", "refs/bisect/bad")
+	if err != nil {
+		return nil, err
+	}
+	goods, err := git.Run("for-each-ref", "--format=%(objectname)", "refs/bisect/good-*")
+	if err != nil {
+		return nil, err
+	}
+	current, err := git.Commit(HEAD)
+	if err != nil {
+		return nil, err
+	}
+	// Each step splits the commit range at the tested commit, so possible next steps form a binary tree.
+	// We walk the tree breadth-first and ask git what it would test for each outcome.
+	type step struct {
+		bad   string
+		goods []string
+		test  string
+	}
+	queue := []step{{
+		bad:   strings.TrimSpace(string(bad)),
+		goods: strings.Fields(string(goods)),
+		test:  current.Hash,
+	}}
+	seen := map[string]bool{current.Hash: true}
+	var res []string
+	for len(queue) != 0 && len(res) < n {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range []step{
+			{bad: cur.bad, goods: append(append([]string{}, cur.goods...), cur.test)},
+			{bad: cur.test, goods: cur.goods},
+		} {
+			args := append([]string{"rev-list", "--bisect", next.bad, "--not"}, next.goods...)
+			output, err := git.Run(args...)
+			if err != nil {
+				return nil, err
+			}
+			next.test = strings.TrimSpace(string(output))
+			if next.test == "" || next.test == next.bad || seen[next.test] || len(res) == n {
+				continue
+			}
+			seen[next.test] = true
+			res = append(res, next.test)
+			queue = append(queue, next)
+		}
+	}
+	return res, nil
+}
+
 var gitFullHashRe = regexp.MustCompile("[a-f0-9]{40}")
 
 func (git *gitRepo) bisectInconclusive(output []byte) ([]*Commit, error) {
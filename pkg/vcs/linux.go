@@ -340,6 +340,47 @@ func (ctx *linux) Minimize(target *targets.Target, original, baseline []byte, ty
 	return minimizeCtx.getConfig(), nil
 }
 
+// BisectConfig() finds config options that are enabled in the bad config, but not in the good one,
+// and that trigger the bug. Both configs are transformed in the same way as for Minimize().
+func (ctx *linux) BisectConfig(target *targets.Target, bad, good []byte, dt debugtracer.DebugTracer,
+	pred func(test []byte) (BisectResult, error)) (*ConfigBisectResult, error) {
+	kconf, err := kconfig.Parse(target, filepath.Join(ctx.gitRepo.Dir, "Kconfig"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadKconfig, err)
+	}
+	badConfig, err := kconfig.ParseConfigData(bad, "bad")
+	if err != nil {
+		return nil, err
+	}
+	goodConfig, err := kconfig.ParseConfigData(good, "good")
+	if err != nil {
+		return nil, err
+	}
+	minimizeCtx := &minimizeLinuxCtx{
+		kconf:  kconf,
+		config: badConfig,
+		pred: func(cfg *kconfig.ConfigFile) (bool, error) {
+			res, err := pred(serialize(cfg))
+			return res == BisectBad, err
+		},
+		transform: func(cfg *kconfig.ConfigFile) {
+			setLinuxTagConfigs(cfg, nil)
+		},
+		DebugTracer: dt,
+	}
+	minimizeCtx.transform(goodConfig)
+	res, err := kconf.Bisect(goodConfig, badConfig, minimizeCtx.runPred, dt)
+	if err != nil {
+		return nil, err
+	}
+	minimizeCtx.config = res.Config
+	return &ConfigBisectResult{
+		Config:   minimizeCtx.getConfig(),
+		Culprits: res.Culprits,
+		Enabled:  res.Enabled,
+	}, nil
+}
+
 func serialize(cf *kconfig.ConfigFile) []byte {
 	return []byte(fmt.Sprintf("%v, rev: %v\n%s", configBisectTag, prog.GitRevision, cf.Serialize()))
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/kconfig"
 	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/sys/targets"
 )
@@ -15,7 +16,10 @@ type testos struct {
 	*gitRepo
 }
 
-var _ ConfigMinimizer = new(testos)
+var (
+	_ ConfigMinimizer = new(testos)
+	_ ConfigBisecter  = new(testos)
+)
 
 func newTestos(dir string, opts []RepoOpt) *testos {
 	return &testos{
@@ -56,6 +60,31 @@ func (ctx *testos) Minimize(target *targets.Target, original, baseline []byte, t
 	}
 }
 
+// BisectConfig treats configs as plain config files without any dependencies between options.
+func (ctx *testos) BisectConfig(target *targets.Target, bad, good []byte, dt debugtracer.DebugTracer,
+	pred func(test []byte) (BisectResult, error)) (*ConfigBisectResult, error) {
+	badConfig, err := kconfig.ParseConfigData(bad, "bad")
+	if err != nil {
+		return nil, err
+	}
+	goodConfig, err := kconfig.ParseConfigData(good, "good")
+	if err != nil {
+		return nil, err
+	}
+	res, err := new(kconfig.KConfig).Bisect(goodConfig, badConfig, func(cfg *kconfig.ConfigFile) (bool, error) {
+		res, err := pred(cfg.Serialize())
+		return res == BisectBad, err
+	}, dt)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigBisectResult{
+		Config:   res.Config.Serialize(),
+		Culprits: res.Culprits,
+		Enabled:  res.Enabled,
+	}, nil
+}
+
 func (ctx *testos) PrepareBisect() error {
 	return nil
 }
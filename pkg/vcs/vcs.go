@@ -104,6 +104,34 @@ type Bisecter interface {
 		kernelConfig []byte, backports []BackportCommit) (*BisectEnv, error)
 }
 
+// ParallelBisecter is implemented by repos that support testing of several bisection commits at the same time.
+type ParallelBisecter interface {
+	// AddWorktree creates (or reuses) a separate checkout of the repository in dir.
+	AddWorktree(dir string) error
+
+	// BisectCandidates returns up to n commits that the bisection started with Bisecter.Bisect may test
+	// in the next steps (in the order of bisection steps), assuming the current commit is either good or bad.
+	// Must be called from the Bisect predicate.
+	BisectCandidates(n int) ([]string, error)
+}
+
+// ConfigBisecter is implemented by repos that support kernel config bisection.
+type ConfigBisecter interface {
+	// BisectConfig finds the minimal set of config options that are enabled in the bad config,
+	// but not in the good one, and whose enabling makes pred return BisectBad.
+	BisectConfig(target *targets.Target, bad, good []byte, dt debugtracer.DebugTracer,
+		pred func(test []byte) (BisectResult, error)) (*ConfigBisectResult, error)
+}
+
+type ConfigBisectResult struct {
+	// The good config with Enabled options.
+	Config []byte
+	// The minimal set of options that trigger the bug.
+	Culprits []string
+	// Culprits plus the options they depend on.
+	Enabled []string
+}
+
 type ConfigMinimizer interface {
 	Minimize(target *targets.Target, original, baseline []byte, types []crash.Type,
 		dt debugtracer.DebugTracer, pred func(test []byte) (BisectResult, error)) ([]byte, error)
@@ -0,0 +1,128 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package verifier
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+)
+
+// RunFunc executes the program on all kernels and returns the results in the order of the kernels.
+type RunFunc func(p *prog.Prog) ([]*Result, error)
+
+// MismatchBucket groups mismatches of the same class (see Mismatch.Class).
+type MismatchBucket struct {
+	Class string
+	Count int
+	// Repro is the (minimized) program that reproduces the mismatch.
+	Repro *prog.Prog
+}
+
+// Buckets groups the found mismatches by class and keeps a reproducer for each class.
+type Buckets struct {
+	comparer *Comparer
+	dir      string
+	run      RunFunc
+	runs     int
+
+	mu      sync.Mutex
+	buckets map[string]*MismatchBucket
+}
+
+// NewBuckets creates mismatch buckets that save reproducers to dir/<class hash>/.
+// If run is not nil, the reproducer of each new class is minimized with it,
+// a program is considered to reproduce the mismatch if it's present in all of the runs.
+func NewBuckets(comparer *Comparer, dir string, run RunFunc, runs int) *Buckets {
+	return &Buckets{
+		comparer: comparer,
+		dir:      dir,
+		run:      run,
+		runs:     max(runs, 1),
+		buckets:  make(map[string]*MismatchBucket),
+	}
+}
+
+// Add adds the mismatches found in the program results to the buckets.
+// It returns the classes that have not been seen before.
+func (b *Buckets) Add(res []*Result, p *prog.Prog) ([]string, error) {
+	var classes []string
+	for _, m := range b.comparer.Mismatches(res, p) {
+		class := m.Class()
+		b.mu.Lock()
+		bucket := b.buckets[class]
+		if bucket != nil {
+			bucket.Count++
+			b.mu.Unlock()
+			continue
+		}
+		bucket = &MismatchBucket{Class: class, Count: 1}
+		b.buckets[class] = bucket
+		b.mu.Unlock()
+		classes = append(classes, class)
+
+		repro := p
+		if b.run != nil {
+			repro = b.comparer.Minimize(p, m, b.runs, b.run)
+		}
+		b.mu.Lock()
+		bucket.Repro = repro
+		b.mu.Unlock()
+
+		dir := filepath.Join(b.dir, hash.String([]byte(class)))
+		if err := osutil.MkdirAll(dir); err != nil {
+			return classes, fmt.Errorf("failed to create bucket dir: %w", err)
+		}
+		if err := osutil.WriteFile(filepath.Join(dir, "class"), []byte(class+"\n")); err != nil {
+			return classes, err
+		}
+		if err := osutil.WriteFile(filepath.Join(dir, "repro.prog"), repro.Serialize()); err != nil {
+			return classes, err
+		}
+	}
+	return classes, nil
+}
+
+// Bucket returns a copy of the bucket of the given class, or nil if there is no such bucket.
+func (b *Buckets) Bucket(class string) *MismatchBucket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bucket := b.buckets[class]
+	if bucket == nil {
+		return nil
+	}
+	ret := *bucket
+	return &ret
+}
+
+// Minimize returns a minimized version of the program that still triggers the mismatch
+// of the same class on the same call in each of the runs.
+func (c *Comparer) Minimize(p *prog.Prog, m *Mismatch, runs int, run RunFunc) *prog.Prog {
+	class := m.Class()
+	repro, _ := prog.Minimize(p.Clone(), m.CallIndex, prog.MinimizeCrash,
+		func(p1 *prog.Prog, callIndex int) bool {
+			// Reruns exclude flaky mismatches.
+			for i := 0; i < runs; i++ {
+				res, err := run(p1)
+				if err != nil || !hasMismatch(c.Mismatches(res, p1), callIndex, class) {
+					return false
+				}
+			}
+			return true
+		})
+	return repro
+}
+
+func hasMismatch(mismatches []*Mismatch, callIndex int, class string) bool {
+	for _, m := range mismatches {
+		if m.CallIndex == callIndex && m.Class() == class {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/prog"
+)
+
+func TestBuckets(t *testing.T) {
+	dir := t.TempDir()
+	buckets := NewBuckets(NewComparer(nil, nil), dir, nil, 0)
+	p := getTestProgram(t)
+	res := []*Result{
+		makeResult([]int{1, 3, 2}),
+		makeResult([]int{1, 3, 5}),
+	}
+	const class = "test$res0: errno 2 vs 5"
+	for i, want := range [][]string{{class}, nil} {
+		classes, err := buckets.Add(res, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, classes); diff != "" {
+			t.Fatalf("Add #%v (-want +got):\n%s", i, diff)
+		}
+	}
+	bucket := buckets.Bucket(class)
+	if bucket == nil || bucket.Count != 2 || bucket.Repro != p {
+		t.Fatalf("bad bucket: %+v", bucket)
+	}
+	if buckets.Bucket("unknown") != nil {
+		t.Fatalf("found unknown bucket")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, hash.String([]byte(class)), "repro.prog"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(p.Serialize()), string(data)); diff != "" {
+		t.Errorf("bad repro.prog (-want +got):\n%s", diff)
+	}
+}
+
+func TestBucketsMinimize(t *testing.T) {
+	// test$res0 returns different errnos on the two kernels, other calls succeed.
+	runs := 0
+	run := func(p *prog.Prog) ([]*Result, error) {
+		runs++
+		res := []*Result{{Info: &flatrpc.ProgInfo{}}, {Info: &flatrpc.ProgInfo{}}}
+		for _, call := range p.Calls {
+			for i, r := range res {
+				errno := 0
+				if call.Meta.Name == "test$res0" {
+					errno = 2 + 3*i
+				}
+				r.Info.Calls = append(r.Info.Calls, &flatrpc.CallInfo{Error: int32(errno)})
+			}
+		}
+		return res, nil
+	}
+	buckets := NewBuckets(NewComparer(nil, nil), t.TempDir(), run, 2)
+	p := getTestProgram(t)
+	res, _ := run(p)
+	if _, err := buckets.Add(res, p); err != nil {
+		t.Fatal(err)
+	}
+	bucket := buckets.Bucket("test$res0: errno 2 vs 5")
+	if bucket == nil {
+		t.Fatalf("no bucket")
+	}
+	if diff := cmp.Diff("test$res0()\n", string(bucket.Repro.Serialize())); diff != "" {
+		t.Errorf("bad repro (-want +got):\n%s", diff)
+	}
+	if runs < 3 || runs%2 != 1 {
+		t.Errorf("the predicate is not rerun: %v runs", runs)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package verifier compares results of execution of the same program on different kernels
+// (see syz-verifier).
+package verifier
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/prog"
+)
+
+// Result is the result of execution of a program on one kernel.
+type Result struct {
+	// Info contains results of the individual calls.
+	// The output buffers are compared by CallInfo.Copyout, so the program needs to be executed
+	// with flatrpc.ExecFlagCollectCopyout for the output comparison to work.
+	Info *flatrpc.ProgInfo
+	// Crashed is set if the kernel crashed while executing the program.
+	Crashed bool
+}
+
+// CallState is the result of execution of one call of the program.
+type CallState struct {
+	Errno   int
+	Flags   flatrpc.CallFlag
+	Crashed bool
+}
+
+// CompareRules describes differences between kernels that are known to be benign.
+// The rules file contains one rule per line, empty lines and lines starting with # are ignored:
+//
+//	<call> <errno> <errno>  - the call may return either of the errnos on different kernels
+//	<call> output           - differences in the call output buffers are ignored
+//
+// <call> is a syscall name, possibly with wildcards (see path.Match), e.g. "ioctl$*".
+// <errno> is an errno number or * that matches any errno.
+type CompareRules struct {
+	rules []compareRule
+}
+
+type compareRule struct {
+	call   string
+	errno0 int
+	errno1 int
+	output bool
+}
+
+const anyErrno = -1
+
+func LoadCompareRules(file string) (*CompareRules, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	return ParseCompareRules(data)
+}
+
+func ParseCompareRules(data []byte) (*CompareRules, error) {
+	rules := new(CompareRules)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if _, err := path.Match(fields[0], ""); err != nil {
+			return nil, fmt.Errorf("line %v: bad call pattern %q: %w", line, fields[0], err)
+		}
+		rule := compareRule{call: fields[0]}
+		switch {
+		case len(fields) == 2 && fields[1] == "output":
+			rule.output = true
+		case len(fields) == 3:
+			var err0, err1 error
+			rule.errno0, err0 = parseRuleErrno(fields[1])
+			rule.errno1, err1 = parseRuleErrno(fields[2])
+			if err0 != nil || err1 != nil {
+				return nil, fmt.Errorf("line %v: bad errno", line)
+			}
+		default:
+			return nil, fmt.Errorf("line %v: expected '<call> <errno> <errno>' or '<call> output'", line)
+		}
+		rules.rules = append(rules.rules, rule)
+	}
+	return rules, s.Err()
+}
+
+func parseRuleErrno(s string) (int, error) {
+	if s == "*" {
+		return anyErrno, nil
+	}
+	errno, err := strconv.Atoi(s)
+	if err == nil && errno < 0 {
+		err = fmt.Errorf("negative errno")
+	}
+	return errno, err
+}
+
+func (cr *CompareRules) match(call string, fn func(rule *compareRule) bool) bool {
+	if cr == nil {
+		return false
+	}
+	for i := range cr.rules {
+		rule := &cr.rules[i]
+		if ok, _ := path.Match(rule.call, call); ok && fn(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// benignErrnos says whether the call may return errno0 on one kernel and errno1 on another.
+func (cr *CompareRules) benignErrnos(call string, errno0, errno1 int) bool {
+	matchErrno := func(rule, errno int) bool {
+		return rule == anyErrno || rule == errno
+	}
+	return cr.match(call, func(rule *compareRule) bool {
+		return !rule.output &&
+			(matchErrno(rule.errno0, errno0) && matchErrno(rule.errno1, errno1) ||
+				matchErrno(rule.errno0, errno1) && matchErrno(rule.errno1, errno0))
+	})
+}
+
+func (cr *CompareRules) benignOutput(call string) bool {
+	return cr.match(call, func(rule *compareRule) bool {
+		return rule.output
+	})
+}
+
+// Comparer compares the results of the same program executed on different kernels.
+type Comparer struct {
+	// Calls for which output buffers are compared in addition to errnos.
+	outputCalls map[string]bool
+	rules       *CompareRules
+}
+
+func NewComparer(outputCalls []string, rules *CompareRules) *Comparer {
+	c := &Comparer{
+		outputCalls: make(map[string]bool),
+		rules:       rules,
+	}
+	for _, call := range outputCalls {
+		c.outputCalls[call] = true
+	}
+	return c
+}
+
+// Mismatch describes a difference in the execution of one call of the program.
+type Mismatch struct {
+	CallIndex int
+	Call      string
+	// States contains the distinct states of the call on different kernels, sorted by errno.
+	// For output mismatches it contains the single state shared by all kernels.
+	States []CallState
+	// Output is set if all kernels returned the same state, but different output buffers.
+	Output bool
+}
+
+// Class identifies the kind of the mismatch: the call and the set of the returned errnos.
+// Mismatches of the same class are likely caused by the same kernel difference.
+func (m *Mismatch) Class() string {
+	if m.Output {
+		return m.Call + ": output"
+	}
+	var errnos, flags []string
+	for _, state := range m.States {
+		if state.Crashed {
+			errnos = append(errnos, "crashed")
+		} else {
+			errnos = append(errnos, strconv.Itoa(state.Errno))
+		}
+		flags = append(flags, strconv.Itoa(int(state.Flags)))
+	}
+	if errnos[0] == errnos[len(errnos)-1] {
+		// Only the flags differ.
+		return fmt.Sprintf("%v: flags %v", m.Call, strings.Join(flags, " vs "))
+	}
+	return fmt.Sprintf("%v: errno %v", m.Call, strings.Join(dedupStrings(errnos), " vs "))
+}
+
+// Mismatches returns all differences in results that are not benign according to the rules.
+func (c *Comparer) Mismatches(res []*Result, p *prog.Prog) []*Mismatch {
+	var mismatches []*Mismatch
+	for idx, call := range p.Calls {
+		name := call.Meta.Name
+		states := make(map[CallState]bool)
+		for _, r := range res {
+			states[callState(r, idx)] = true
+		}
+		m := &Mismatch{
+			CallIndex: idx,
+			Call:      name,
+		}
+		for state := range states {
+			m.States = append(m.States, state)
+		}
+		sort.Slice(m.States, func(i, j int) bool {
+			a, b := m.States[i], m.States[j]
+			if a.Crashed != b.Crashed {
+				return b.Crashed
+			}
+			if a.Errno != b.Errno {
+				return a.Errno < b.Errno
+			}
+			return a.Flags < b.Flags
+		})
+		if len(m.States) > 1 {
+			if !c.benignStates(name, m.States) {
+				mismatches = append(mismatches, m)
+			}
+			continue
+		}
+		if c.outputCalls[name] && !c.rules.benignOutput(name) && outputsDiffer(res, idx) {
+			m.Output = true
+			mismatches = append(mismatches, m)
+		}
+	}
+	return mismatches
+}
+
+// benignStates says whether the call states differ only in errnos allowed by the rules.
+func (c *Comparer) benignStates(call string, states []CallState) bool {
+	for i, s0 := range states {
+		for _, s1 := range states[i+1:] {
+			if s0.Crashed || s1.Crashed || s0.Errno == s1.Errno ||
+				!c.rules.benignErrnos(call, s0.Errno, s1.Errno) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func callState(r *Result, idx int) CallState {
+	if r.Crashed || r.Info == nil || idx >= len(r.Info.Calls) {
+		return CallState{Crashed: true}
+	}
+	ci := r.Info.Calls[idx]
+	if ci == nil {
+		return CallState{}
+	}
+	return CallState{Errno: int(ci.Error), Flags: ci.Flags}
+}
+
+// outputsDiffer says whether the call copied out different values on different kernels.
+func outputsDiffer(res []*Result, idx int) bool {
+	var output0 []uint64
+	seen := false
+	for _, r := range res {
+		if r.Info == nil || idx >= len(r.Info.Calls) || r.Info.Calls[idx] == nil {
+			continue
+		}
+		output := r.Info.Calls[idx].Copyout
+		if !seen {
+			output0, seen = output, true
+		} else if !slices.Equal(output0, output) {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupStrings(list []string) []string {
+	var res []string
+	for i, s := range list {
+		if i == 0 || s != list[i-1] {
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package verifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+func TestParseCompareRules(t *testing.T) {
+	rules, err := ParseCompareRules([]byte(`
+# comment
+test$res0 2 5
+minimize$* * 22
+breaks_returns output
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		call   string
+		errno0 int
+		errno1 int
+		want   bool
+	}{
+		{"test$res0", 2, 5, true},
+		{"test$res0", 5, 2, true},
+		{"test$res0", 2, 3, false},
+		{"minimize$0", 3, 22, true},
+		{"minimize$0", 22, 0, true},
+		{"minimize$0", 3, 4, false},
+		{"breaks_returns", 1, 2, false},
+	}
+	for _, test := range tests {
+		if got := rules.benignErrnos(test.call, test.errno0, test.errno1); got != test.want {
+			t.Errorf("benignErrnos(%v, %v, %v) = %v, want %v",
+				test.call, test.errno0, test.errno1, got, test.want)
+		}
+	}
+	if !rules.benignOutput("breaks_returns") || rules.benignOutput("test$res0") {
+		t.Errorf("wrong benignOutput result")
+	}
+
+	for _, bad := range []string{"test$res0 2", "test$res0 2 x", "test$res0 -1 2", "[ 1 2", "test$res0 input"} {
+		if _, err := ParseCompareRules([]byte(bad)); err == nil {
+			t.Errorf("parsed bad rule %q", bad)
+		}
+	}
+}
+
+func TestMismatches(t *testing.T) {
+	withOutput := func(r *Result, output ...uint64) *Result {
+		for idx, o := range output {
+			r.Info.Calls[idx].Copyout = []uint64{o}
+		}
+		return r
+	}
+	tests := []struct {
+		name        string
+		res         []*Result
+		rules       string
+		outputCalls []string
+		want        []string
+	}{
+		{
+			name: "no mismatches",
+			res: []*Result{
+				makeResult([]int{1, 3, 2}),
+				makeResult([]int{1, 3, 2}),
+			},
+		},
+		{
+			name: "errno mismatches",
+			res: []*Result{
+				makeResult([]int{1, 3, 2}),
+				makeResult([]int{1, 4, 5}),
+				makeResult([]int{1, 3, 2}),
+			},
+			want: []string{"minimize$0: errno 3 vs 4", "test$res0: errno 2 vs 5"},
+		},
+		{
+			name: "benign errnos",
+			res: []*Result{
+				makeResult([]int{1, 3, 2}),
+				makeResult([]int{1, 4, 5}),
+			},
+			rules: "test$res0 5 2",
+			want:  []string{"minimize$0: errno 3 vs 4"},
+		},
+		{
+			name: "flags and crashes",
+			res: []*Result{
+				makeResult([]int{1, 3, 2}, 1, 1, 1),
+				makeResult([]int{1, 3, 2}, 1, 3, 1),
+				{Crashed: true},
+			},
+			rules: "* * *",
+			want: []string{
+				"breaks_returns: errno 1 vs crashed",
+				"minimize$0: errno 3 vs crashed",
+				"test$res0: errno 2 vs crashed",
+			},
+		},
+		{
+			name: "flags only",
+			res: []*Result{
+				makeResult([]int{1, 3, 2}, 1, 1, 1),
+				makeResult([]int{1, 3, 2}, 1, 3, 1),
+			},
+			rules: "* * *",
+			want:  []string{"minimize$0: flags 1 vs 3"},
+		},
+		{
+			name: "output mismatches",
+			res: []*Result{
+				withOutput(makeResult([]int{0, 0, 0}), 1, 2, 3),
+				withOutput(makeResult([]int{0, 0, 0}), 1, 4, 5),
+				withOutput(makeResult([]int{0, 0, 0}), 1, 2, 3),
+			},
+			rules:       "test$res0 output",
+			outputCalls: []string{"breaks_returns", "minimize$0", "test$res0"},
+			want:        []string{"minimize$0: output"},
+		},
+	}
+	p := getTestProgram(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rules, err := ParseCompareRules([]byte(test.rules))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, m := range NewComparer(test.outputCalls, rules).Mismatches(test.res, p) {
+				got = append(got, m.Class())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Mismatches (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func getTestProgram(t *testing.T) *prog.Prog {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := target.Deserialize([]byte("breaks_returns()\nminimize$0(0x1, 0x1)\ntest$res0()\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func makeResult(errnos []int, flags ...int) *Result {
+	r := &Result{Info: &flatrpc.ProgInfo{}}
+	for _, errno := range errnos {
+		r.Info.Calls = append(r.Info.Calls, &flatrpc.CallInfo{Error: int32(errno)})
+	}
+	for idx, f := range flags {
+		r.Info.Calls[idx].Flags = flatrpc.CallFlag(f)
+	}
+	return r
+}
@@ -46,8 +46,8 @@ func fill(target *prog.Target, files embed.FS) {
 	if err != nil {
 		panic(err)
 	}
-	desc := new(Desc)
-	if err := gob.NewDecoder(flate.NewReader(bytes.NewReader(data))).Decode(desc); err != nil {
+	desc, err := Deserialize(data)
+	if err != nil {
 		panic(err)
 	}
 	target.Syscalls = desc.Syscalls
@@ -73,6 +73,14 @@ func Serialize(desc *Desc) ([]byte, error) {
 	return out.Bytes(), nil
 }
 
+func Deserialize(data []byte) (*Desc, error) {
+	desc := new(Desc)
+	if err := gob.NewDecoder(flate.NewReader(bytes.NewReader(data))).Decode(desc); err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
+
 func FileName(os, arch string) string {
 	return fileName(fmt.Sprintf("%v_%v", os, arch))
 }
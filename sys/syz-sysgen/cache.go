@@ -0,0 +1,123 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/generated"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// compilationCache stores the latest compilation result for each target along with dependencies
+// of all its syscalls (see compiler.CompileIncremental). Only syscalls that depend on changed
+// declarations or consts are recompiled, the rest are taken from the cached result.
+type compilationCache struct {
+	dir string
+	// Hash of the syz-sysgen binary: changes to the compiler invalidate all entries.
+	binary string
+	// Number of syscalls reused from the cache and compiled anew.
+	reused   atomic.Int64
+	compiled atomic.Int64
+}
+
+type cacheEntry struct {
+	Binary      string
+	Revision    string
+	Unsupported map[string]bool
+	Calls       map[string]*compiler.CallDeps
+}
+
+func newCompilationCache(dir string) (*compilationCache, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(exe)
+	if err != nil {
+		return nil, err
+	}
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	return &compilationCache{
+		dir:    dir,
+		binary: hash.String(data),
+	}, nil
+}
+
+// load returns the cached compilation result for the target, or nil if there is none.
+func (cache *compilationCache) load(target *targets.Target) *compiler.Prog {
+	entry := new(cacheEntry)
+	descFile, dataFile := cache.files(target)
+	if desc, err := os.ReadFile(descFile); err != nil || json.Unmarshal(desc, entry) != nil ||
+		entry.Binary != cache.binary {
+		return nil
+	}
+	data, err := os.ReadFile(dataFile)
+	// Revision is the hash of the data, so this also detects partially written entries.
+	if err != nil || hash.String(data) != entry.Revision {
+		return nil
+	}
+	desc, err := generated.Deserialize(data)
+	if err != nil {
+		return nil
+	}
+	return &compiler.Prog{
+		Resources:   desc.Resources,
+		Syscalls:    desc.Syscalls,
+		Types:       desc.Types,
+		Unsupported: entry.Unsupported,
+		Calls:       entry.Calls,
+	}
+}
+
+func (cache *compilationCache) store(target *targets.Target, prg *compiler.Prog, data []byte) error {
+	desc, err := json.Marshal(&cacheEntry{
+		Binary:      cache.binary,
+		Revision:    hash.String(data),
+		Unsupported: prg.Unsupported,
+		Calls:       prg.Calls,
+	})
+	if err != nil {
+		return err
+	}
+	descFile, dataFile := cache.files(target)
+	if err := osutil.WriteFileAtomically(dataFile, data); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := osutil.WriteFileAtomically(descFile, desc); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// count updates statistics of reused and compiled syscalls given syscalls of the cached result
+// and of the new result.
+func (cache *compilationCache) count(prev, syscalls []*prog.Syscall) {
+	reused := make(map[*prog.Syscall]bool)
+	for _, c := range prev {
+		reused[c] = true
+	}
+	for _, c := range syscalls {
+		if reused[c] {
+			cache.reused.Add(1)
+		} else {
+			cache.compiled.Add(1)
+		}
+	}
+}
+
+func (cache *compilationCache) files(target *targets.Target) (string, string) {
+	base := filepath.Join(cache.dir, target.OS+"_"+target.Arch)
+	return base + ".json", base + ".gob.flate"
+}
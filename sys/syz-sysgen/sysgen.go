@@ -67,24 +67,42 @@ type TemplateData struct {
 	CallProps []CallPropDescription
 }
 
-var srcDir = flag.String("src", "", "path to root of syzkaller source dir")
-var outDir = flag.String("out", "", "path to out dir")
+var (
+	srcDir      = flag.String("src", "", "path to root of syzkaller source dir")
+	outDir      = flag.String("out", "", "path to out dir")
+	incremental = flag.Bool("incremental", false, "recompile only syscalls whose descriptions or consts have changed")
+	cacheDir    = flag.String("cache", "", "dir to cache compilation results for -incremental (default: OUT/bin/sysgen-cache)")
+)
 
 func main() {
 	defer tool.Init()()
 
-	// Cleanup old files in the case set of architectures has chnaged.
-	allFiles, err := filepath.Glob(filepath.Join(*outDir, "sys", generated.Glob()))
-	if err != nil {
-		tool.Failf("failed to glob: %v", err)
+	var cache *compilationCache
+	if *incremental {
+		dir := *cacheDir
+		if dir == "" {
+			dir = filepath.Join(*outDir, "bin", "sysgen-cache")
+		}
+		var err error
+		if cache, err = newCompilationCache(dir); err != nil {
+			tool.Fail(err)
+		}
 	}
-	for _, file := range allFiles {
-		os.Remove(file)
+
+	var OSList []string
+	for OS := range targets.List {
+		OSList = append(OSList, OS)
 	}
+	sort.Strings(OSList)
+	generate(*srcDir, *outDir, OSList, cache)
+}
 
+// generate writes generated files for the given OSes to outDir.
+// If cache is not nil, it's used to skip compilation of syscalls whose inputs have not changed.
+func generate(srcDir, outDir string, OSList []string, cache *compilationCache) {
 	// Also remove old generated files since they will break build.
 	// TODO: remove this after some time after 2025-01-23.
-	oldFiles, err := filepath.Glob(filepath.Join(*outDir, "sys", "*", "gen", "*"))
+	oldFiles, err := filepath.Glob(filepath.Join(outDir, "sys", "*", "gen", "*"))
 	if err != nil {
 		tool.Failf("failed to glob: %v", err)
 	}
@@ -92,21 +110,16 @@ func main() {
 		os.Remove(file)
 	}
 
-	var OSList []string
-	for OS := range targets.List {
-		OSList = append(OSList, OS)
-	}
-	sort.Strings(OSList)
-
 	data := &TemplateData{
 		Notice: "Automatically generated by syz-sysgen; DO NOT EDIT.",
 	}
+	generatedFiles := make(map[string]bool)
 	for _, OS := range OSList {
-		descriptions := ast.ParseGlob(filepath.Join(*srcDir, "sys", OS, "*.txt"), nil)
+		descriptions := ast.ParseGlob(filepath.Join(srcDir, "sys", OS, "*.txt"), nil)
 		if descriptions == nil {
 			os.Exit(1)
 		}
-		constFile := compiler.DeserializeConstFile(filepath.Join(*srcDir, "sys", OS, "*.const"), nil)
+		constFile := compiler.DeserializeConstFile(filepath.Join(srcDir, "sys", OS, "*.const"), nil)
 		if constFile == nil {
 			os.Exit(1)
 		}
@@ -141,7 +154,7 @@ func main() {
 		for _, job := range jobs {
 			go func() {
 				defer wg.Done()
-				processJob(job, descriptions, constFile)
+				processJob(job, descriptions, constFile, outDir, cache)
 			}()
 		}
 		wg.Wait()
@@ -157,6 +170,7 @@ func main() {
 				os.Exit(1)
 			}
 			syscallArchs = append(syscallArchs, job.ArchData)
+			generatedFiles[job.File] = true
 			for u := range job.Unsupported {
 				unsupported[u]++
 			}
@@ -190,9 +204,21 @@ func main() {
 		return data.OSes[i].GOOS < data.OSes[j].GOOS
 	})
 
-	writeTemplate(filepath.Join(*outDir, "sys", "register.go"), registerTempl, data)
-	writeTemplate(filepath.Join(*outDir, "executor", "defs.h"), defsTempl, data)
-	writeTemplate(filepath.Join(*outDir, "executor", "syscalls.h"), syscallsTempl, data)
+	writeTemplate(filepath.Join(outDir, "sys", "register.go"), registerTempl, data)
+	writeTemplate(filepath.Join(outDir, "executor", "defs.h"), defsTempl, data)
+	writeTemplate(filepath.Join(outDir, "executor", "syscalls.h"), syscallsTempl, data)
+
+	// Cleanup old files in the case set of architectures has changed.
+	// Files that are still generated are not touched, so that unchanged files keep their timestamps.
+	allFiles, err := filepath.Glob(filepath.Join(outDir, "sys", generated.Glob()))
+	if err != nil {
+		tool.Failf("failed to glob: %v", err)
+	}
+	for _, file := range allFiles {
+		if !generatedFiles[file] {
+			os.Remove(file)
+		}
+	}
 }
 
 type Job struct {
@@ -202,10 +228,46 @@ type Job struct {
 	Unsupported map[string]bool
 	ArchData    ArchData
 	ConstInfo   map[string]*compiler.ConstInfo
-	Revision    string
+	File        string
+}
+
+func processJob(job *Job, descriptions *ast.Description, constFile *compiler.ConstFile,
+	outDir string, cache *compilationCache) {
+	eh := func(pos ast.Pos, msg string) {
+		job.Errors = append(job.Errors, fmt.Sprintf("%v: %v\n", pos, msg))
+	}
+	res := compileTarget(job.Target, descriptions, constFile.Arch(job.Target.Arch), cache, eh)
+	if res == nil {
+		return
+	}
+	for what := range res.Unsupported {
+		job.Unsupported[what] = true
+	}
+	job.File = filepath.Join(outDir, "sys", generated.FileName(job.Target.OS, job.Target.Arch))
+	writeFile(job.File, res.Data)
+	job.ArchData = res.ArchData
+
+	// Don't print warnings, they are printed in syz-check.
+	job.Errors = nil
+	// But let's fail on always actionable errors.
+	if job.Target.OS != targets.Fuchsia {
+		// There are too many broken consts on Fuchsia.
+		constsAreAllDefined(constFile, job.ConstInfo, eh)
+	}
+	job.OK = len(job.Errors) == 0
+}
+
+// compilationResult is everything we need to know about a compiled target to generate files.
+type compilationResult struct {
+	Data        []byte // serialized generated.Desc
+	ArchData    ArchData
+	Unsupported map[string]bool
 }
 
-func processJob(job *Job, descriptions *ast.Description, constFile *compiler.ConstFile) {
+// compileTarget compiles descriptions for the target.
+// If cache is not nil, only syscalls affected by changes since the cached compilation are recompiled.
+func compileTarget(target *targets.Target, descriptions *ast.Description, consts map[string]uint64,
+	cache *compilationCache, eh ast.ErrorHandler) *compilationResult {
 	var flags []prog.FlagDesc
 	for _, decl := range descriptions.Nodes {
 		switch n := decl.(type) {
@@ -219,10 +281,6 @@ func processJob(job *Job, descriptions *ast.Description, constFile *compiler.Con
 		}
 	}
 
-	eh := func(pos ast.Pos, msg string) {
-		job.Errors = append(job.Errors, fmt.Sprintf("%v: %v\n", pos, msg))
-	}
-	consts := constFile.Arch(job.Target.Arch)
 	constArr := make([]prog.ConstValue, 0, len(consts))
 	for name, val := range consts {
 		constArr = append(constArr, prog.ConstValue{Name: name, Value: val})
@@ -231,12 +289,19 @@ func processJob(job *Job, descriptions *ast.Description, constFile *compiler.Con
 		return constArr[i].Name < constArr[j].Name
 	})
 
-	prg := compiler.Compile(descriptions, consts, job.Target, eh)
-	if prg == nil {
-		return
+	var prg *compiler.Prog
+	var prevSyscalls []*prog.Syscall
+	if cache == nil {
+		prg = compiler.Compile(descriptions, consts, target, eh)
+	} else {
+		prev := cache.load(target)
+		if prev != nil {
+			prevSyscalls = prev.Syscalls
+		}
+		prg = compiler.CompileIncremental(descriptions, consts, target, prev, eh)
 	}
-	for what := range prg.Unsupported {
-		job.Unsupported[what] = true
+	if prg == nil {
+		return nil
 	}
 
 	desc := &generated.Desc{
@@ -250,19 +315,17 @@ func processJob(job *Job, descriptions *ast.Description, constFile *compiler.Con
 	if err != nil {
 		tool.Fail(err)
 	}
-	sysFile := filepath.Join(*outDir, "sys", generated.FileName(job.Target.OS, job.Target.Arch))
-	writeFile(sysFile, data)
-
-	job.ArchData = generateExecutorSyscalls(job.Target, prg.Syscalls, hash.String(data))
-
-	// Don't print warnings, they are printed in syz-check.
-	job.Errors = nil
-	// But let's fail on always actionable errors.
-	if job.Target.OS != targets.Fuchsia {
-		// There are too many broken consts on Fuchsia.
-		constsAreAllDefined(constFile, job.ConstInfo, eh)
+	if cache != nil {
+		cache.count(prevSyscalls, prg.Syscalls)
+		if err := cache.store(target, prg, data); err != nil {
+			tool.Fail(err)
+		}
+	}
+	return &compilationResult{
+		Data:        data,
+		ArchData:    generateExecutorSyscalls(target, prg.Syscalls, hash.String(data)),
+		Unsupported: prg.Unsupported,
 	}
-	job.OK = len(job.Errors) == 0
 }
 
 func generateExecutorSyscalls(target *targets.Target, syscalls []*prog.Syscall, rev string) ArchData {
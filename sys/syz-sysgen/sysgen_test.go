@@ -0,0 +1,99 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncremental(t *testing.T) {
+	src := t.TempDir()
+	sysDir := filepath.Join(src, "sys", targets.TestOS)
+	files, err := filepath.Glob(filepath.Join("..", targets.TestOS, "*"))
+	require.NoError(t, err)
+	require.NoError(t, osutil.MkdirAll(sysDir))
+	for _, file := range files {
+		if strings.HasSuffix(file, ".txt") || strings.HasSuffix(file, ".const") {
+			require.NoError(t, osutil.CopyFile(file, filepath.Join(sysDir, filepath.Base(file))))
+		}
+	}
+	OSList := []string{targets.TestOS}
+	numArches := len(targets.List[targets.TestOS])
+	cache, err := newCompilationCache(t.TempDir())
+	require.NoError(t, err)
+	incOut := t.TempDir()
+	check := func() (reused, compiled int64) {
+		t.Helper()
+		cache.reused.Store(0)
+		cache.compiled.Store(0)
+		generate(src, incOut, OSList, cache)
+		fullOut := t.TempDir()
+		generate(src, fullOut, OSList, nil)
+		assert.Equal(t, readDir(t, fullOut), readDir(t, incOut))
+		return cache.reused.Load(), cache.compiled.Load()
+	}
+
+	// Cold cache.
+	reused, total := check()
+	assert.Zero(t, reused)
+	// Nothing has changed.
+	reused, compiled := check()
+	assert.Equal(t, total, reused)
+	assert.Zero(t, compiled)
+	// A const is changed for a single arch, only syscalls that use it are recompiled.
+	editFile(t, filepath.Join(sysDir, "test.txt.const"), "IPPROTO_TCP = 6", "IPPROTO_TCP = 6, 64:106")
+	reused, compiled = check()
+	assert.Equal(t, total, reused+compiled)
+	assert.NotZero(t, compiled)
+	assert.Less(t, compiled, total/int64(numArches))
+	// A new syscall is added.
+	editFile(t, filepath.Join(sysDir, "test.txt"), "", "\ntest$incremental(a intptr)\n")
+	reused, compiled = check()
+	assert.Equal(t, total, reused)
+	assert.Equal(t, int64(numArches), compiled)
+}
+
+func editFile(t *testing.T, file, old, new string) {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	var res string
+	if old == "" {
+		res = string(data) + new
+	} else {
+		require.Contains(t, string(data), old)
+		res = strings.Replace(string(data), old, new, 1)
+	}
+	require.NoError(t, os.WriteFile(file, []byte(res), 0644))
+}
+
+func readDir(t *testing.T, dir string) map[string]string {
+	res := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		res[rel] = string(data)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, res)
+	return res
+}
@@ -523,6 +523,13 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 		}
 		return err
 	}
+	resp.Confidence = res.ConfidenceScore
+	for _, f := range res.ConfidenceFactors {
+		resp.ConfidenceFactors = append(resp.ConfidenceFactors, dashapi.ConfidenceFactor{
+			Reason: f.Reason,
+			Score:  f.Score,
+		})
+	}
 	for _, com := range res.Commits {
 		resp.Commits = append(resp.Commits, dashapi.Commit{
 			Hash:       com.Hash,
@@ -543,10 +550,15 @@ func (jp *JobProcessor) bisect(job *Job, mgrcfg *mgrconfig.Config) error {
 		if res.IsRelease {
 			resp.Flags |= dashapi.BisectResultRelease
 		}
+		// The cut off is applied to the false negative probability only,
+		// the heuristic penalties just flag the result as low-confidence.
 		const confidenceCutOff = 0.66
 		if res.Confidence < confidenceCutOff {
 			resp.Flags |= dashapi.BisectResultIgnore
 		}
+		if res.LowConfidence {
+			resp.Flags |= dashapi.BisectResultLowConfidence
+		}
 		if jp.ignoreBisectCommit(res.Commits[0]) {
 			resp.Flags |= dashapi.BisectResultIgnore
 		}
@@ -30,15 +30,21 @@ type SeriesProcessor struct {
 }
 
 func NewSeriesProcessor(env *app.AppEnvironment, cfg *app.AppConfig) *SeriesProcessor {
-	workflows, err := workflow.NewArgoService()
+	var workflows workflow.Service
+	var err error
+	if cfg.LocalWorkflows != nil {
+		workflows, err = workflow.NewLocalService(cfg.LocalWorkflows)
+	} else {
+		workflows, err = workflow.NewArgoService()
+	}
 	if err != nil {
 		app.Fatalf("failed to initialize workflows: %v", err)
 	}
 	return &SeriesProcessor{
 		blobStorage:       env.BlobStorage,
-		seriesRepo:        db.NewSeriesRepository(env.Spanner),
-		sessionRepo:       db.NewSessionRepository(env.Spanner),
-		sessionTestRepo:   db.NewSessionTestRepository(env.Spanner),
+		seriesRepo:        db.NewSeriesRepository(env.DB),
+		sessionRepo:       db.NewSessionRepository(env.DB),
+		sessionTestRepo:   db.NewSessionTestRepository(env.DB),
 		dbPollInterval:    time.Minute,
 		workflows:         workflows,
 		parallelWorkflows: cfg.ParallelWorkflows,
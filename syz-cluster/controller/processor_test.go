@@ -191,9 +191,9 @@ func prepareProcessorTest(t *testing.T, workflows workflow.Service) (*SeriesProc
 	env, ctx := app.TestEnvironment(t)
 	client := controller.TestServer(t, env)
 	return &SeriesProcessor{
-		seriesRepo:        db.NewSeriesRepository(env.Spanner),
-		sessionRepo:       db.NewSessionRepository(env.Spanner),
-		sessionTestRepo:   db.NewSessionTestRepository(env.Spanner),
+		seriesRepo:        db.NewSeriesRepository(env.DB),
+		sessionRepo:       db.NewSessionRepository(env.DB),
+		sessionTestRepo:   db.NewSessionTestRepository(env.DB),
 		workflows:         workflows,
 		dbPollInterval:    time.Second / 10,
 		parallelWorkflows: 2,
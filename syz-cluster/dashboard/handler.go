@@ -50,10 +50,10 @@ func newHandler(env *app.AppEnvironment) (*dashboardHandler, error) {
 		title:           cfg.Name,
 		templates:       perFile,
 		blobStorage:     env.BlobStorage,
-		seriesRepo:      db.NewSeriesRepository(env.Spanner),
-		sessionRepo:     db.NewSessionRepository(env.Spanner),
-		sessionTestRepo: db.NewSessionTestRepository(env.Spanner),
-		findingRepo:     db.NewFindingRepository(env.Spanner),
+		seriesRepo:      db.NewSeriesRepository(env.DB),
+		sessionRepo:     db.NewSessionRepository(env.DB),
+		sessionTestRepo: db.NewSessionTestRepository(env.DB),
+		findingRepo:     db.NewFindingRepository(env.DB),
 	}, nil
 }
 
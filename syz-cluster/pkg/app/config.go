@@ -8,6 +8,7 @@ import (
 	"net/mail"
 	"os"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -21,6 +22,31 @@ type AppConfig struct {
 	LoreArchives []string `yaml:"loreArchives"`
 	// Parameters used for sending/generating emails.
 	EmailReporting *EmailConfig `yaml:"emailReporting"`
+	// If set, the workflows are executed as local processes instead of Argo Workflows.
+	LocalWorkflows *LocalWorkflowsConfig `yaml:"localWorkflows"`
+}
+
+type LocalWorkflowsConfig struct {
+	// Where the per-session step inputs, outputs and logs are stored.
+	Workdir string `yaml:"workdir"`
+	// The kernel git repository the triage and build steps will work on.
+	// Each step gets its own shared clone of it.
+	KernelRepo string `yaml:"kernelRepo"`
+	// The address at which the steps can reach the controller (http://localhost:8080 by default).
+	ControllerURL string `yaml:"controllerURL"`
+	// Per-step settings. The keys are "triage", "build", "boot" and "fuzz".
+	Steps map[string]LocalStepConfig `yaml:"steps"`
+}
+
+type LocalStepConfig struct {
+	// The command that runs the step, the step arguments are appended to it.
+	// By default, it's the step binary name (e.g. "build-step"), which is looked up in $PATH.
+	// It may refer to {{session}}, {{dir}}, {{repository}}, {{base_kernel}} and {{patched_kernel}}.
+	Command []string `yaml:"command"`
+	// How many steps of this kind may run at the same time (0 means no limit).
+	Parallelism int `yaml:"parallelism"`
+	// Overrides the default timeout of the step.
+	Timeout time.Duration `yaml:"timeout"`
 }
 
 const (
@@ -113,6 +139,28 @@ func (c AppConfig) Validate() error {
 			return fmt.Errorf("emailReporting: %w", err)
 		}
 	}
+	if c.LocalWorkflows != nil {
+		if err := c.LocalWorkflows.Validate(); err != nil {
+			return fmt.Errorf("localWorkflows: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c LocalWorkflowsConfig) Validate() error {
+	if err := ensureNonEmpty("workdir", c.Workdir); err != nil {
+		return err
+	}
+	for name, step := range c.Steps {
+		switch name {
+		case "triage", "build", "boot", "fuzz":
+		default:
+			return fmt.Errorf("unknown step %q", name)
+		}
+		if step.Parallelism < 0 {
+			return fmt.Errorf("%v: parallelism must be non-negative", name)
+		}
+	}
 	return nil
 }
 
@@ -16,21 +16,21 @@ import (
 )
 
 type AppEnvironment struct {
-	Spanner     *spanner.Client
+	DB          db.Client
 	BlobStorage blob.Storage
 }
 
 func Environment(ctx context.Context) (*AppEnvironment, error) {
-	spanner, err := DefaultSpanner(ctx)
+	client, err := DefaultDB(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set up a Spanner client: %w", err)
+		return nil, fmt.Errorf("failed to set up a DB client: %w", err)
 	}
 	storage, err := DefaultStorage(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set up the blob storage: %w", err)
 	}
 	return &AppEnvironment{
-		Spanner:     spanner,
+		DB:          client,
 		BlobStorage: storage,
 	}, nil
 }
@@ -38,7 +38,7 @@ func Environment(ctx context.Context) (*AppEnvironment, error) {
 func TestEnvironment(t *testing.T) (*AppEnvironment, context.Context) {
 	client, ctx := db.NewTransientDB(t)
 	return &AppEnvironment{
-		Spanner:     client,
+		DB:          client,
 		BlobStorage: blob.NewLocalStorage(t.TempDir()),
 	}, ctx
 }
@@ -51,6 +51,19 @@ func DefaultSpannerURI() (db.ParsedURI, error) {
 	return db.ParseURI(rawURI)
 }
 
+// DefaultDB connects to Spanner, unless SQLITE_DATABASE_PATH is set.
+// An SQLite database is convenient for local runs, but it cannot be shared by multiple machines.
+func DefaultDB(ctx context.Context) (db.Client, error) {
+	if path := os.Getenv("SQLITE_DATABASE_PATH"); path != "" {
+		return db.NewSQLiteClient(path)
+	}
+	client, err := DefaultSpanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewSpannerClient(client), nil
+}
+
 func DefaultSpanner(ctx context.Context) (*spanner.Client, error) {
 	uri, err := DefaultSpannerURI()
 	if err != nil {
@@ -61,7 +74,10 @@ func DefaultSpanner(ctx context.Context) (*spanner.Client, error) {
 }
 
 func DefaultStorage(ctx context.Context) (blob.Storage, error) {
-	// BLOB_STORAGE_GCS_BUCKET is the only supported option.
+	// For local runs, the blobs may be stored in a folder.
+	if dir := os.Getenv("BLOB_STORAGE_DIR"); dir != "" {
+		return blob.NewLocalStorage(dir), nil
+	}
 	bucket := os.Getenv("BLOB_STORAGE_GCS_BUCKET")
 	if bucket == "" {
 		return nil, fmt.Errorf("empty BLOB0_STORAGE_GCS_BUCKET")
@@ -70,6 +86,10 @@ func DefaultStorage(ctx context.Context) (blob.Storage, error) {
 }
 
 func DefaultClient() *api.Client {
+	// The local workflow runner passes the controller address explicitly.
+	if url := os.Getenv("CONTROLLER_URL"); url != "" {
+		return api.NewClient(url)
+	}
 	return api.NewClient(`http://controller-service:8080`)
 }
 
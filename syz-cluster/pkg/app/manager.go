@@ -0,0 +1,23 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package app
+
+import "path/filepath"
+
+// ManagerConfigPatch returns a patch (see config.PatchJSON) that makes a syzkaller config refer
+// to the kernel build artifacts in the kernel folder and to the syzkaller binaries in the syzkaller
+// folder. Empty folders leave the corresponding config values unchanged.
+func ManagerConfigPatch(kernel, syzkaller string) map[string]any {
+	patch := map[string]any{}
+	if kernel != "" {
+		// The layout of the build-step output.
+		patch["kernel_obj"] = filepath.Join(kernel, "obj")
+		patch["image"] = filepath.Join(kernel, "image")
+		patch["vm"] = map[string]any{"kernel": filepath.Join(kernel, "kernel")}
+	}
+	if syzkaller != "" {
+		patch["syzkaller"] = syzkaller
+	}
+	return patch
+}
@@ -102,7 +102,7 @@ func FakeSeriesWithFindings(t *testing.T, ctx context.Context, env *app.AppEnvir
 }
 
 func MarkSessionFinished(t *testing.T, env *app.AppEnvironment, sessionID string) {
-	repo := db.NewSessionRepository(env.Spanner)
+	repo := db.NewSessionRepository(env.DB)
 	err := repo.Update(context.Background(), sessionID, func(session *db.Session) error {
 		session.SetFinishedAt(time.Now())
 		return nil
@@ -11,11 +11,11 @@ import (
 )
 
 type BuildRepository struct {
-	client *spanner.Client
+	client Client
 	*genericEntityOps[Build, string]
 }
 
-func NewBuildRepository(client *spanner.Client) *BuildRepository {
+func NewBuildRepository(client Client) *BuildRepository {
 	return &BuildRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Build, string]{
@@ -67,7 +67,7 @@ func (repo *BuildRepository) LastBuiltTree(ctx context.Context, params *LastBuil
 		stmt.Params["commit"] = params.Commit
 	}
 	stmt.SQL += " ORDER BY `CommitDate` DESC LIMIT 1"
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readOne[Build](iter)
 }
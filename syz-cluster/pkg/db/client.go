@@ -0,0 +1,170 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/golang-migrate/migrate/v4"
+	"google.golang.org/api/iterator"
+)
+
+// Client is the storage the repositories are built on top of.
+// There are two implementations:
+// 1) Spanner, which is used in production.
+// 2) SQLite, which allows to run the whole system (and the tests) without a Spanner emulator.
+//
+// The queries are written in the Spanner SQL dialect, the SQLite implementation translates
+// the few constructs that differ (see sqlite.go).
+type Client interface {
+	// Query executes a single read-only query.
+	Query(ctx context.Context, stmt spanner.Statement) RowIterator
+	// ReadOnlyTransaction executes all queries of f on a consistent snapshot of the database.
+	ReadOnlyTransaction(ctx context.Context, f func(context.Context, Transaction) error) error
+	// ReadWriteTransaction atomically applies all the changes made in f.
+	// The function may be invoked several times if the transaction has to be retried.
+	ReadWriteTransaction(ctx context.Context, f func(context.Context, Transaction) error) error
+	Close()
+	migrateInstance() (*migrate.Migrate, error)
+}
+
+type Transaction interface {
+	Query(ctx context.Context, stmt spanner.Statement) RowIterator
+	// Insert and Update take a pointer to a struct with `spanner` field tags.
+	// The changes are only guaranteed to be visible after the transaction is committed.
+	Insert(table string, obj any) error
+	Update(table string, obj any) error
+}
+
+type RowIterator interface {
+	// Next decodes the next row into dst, which must be a pointer to a struct with `spanner` field tags.
+	// If dst is nil, the row is skipped.
+	// Once there are no more rows, iterator.Done is returned.
+	Next(dst any) error
+	Stop()
+}
+
+func readOne[T any](iter RowIterator) (*T, error) {
+	var obj T
+	err := iter.Next(&obj)
+	if err == iterator.Done {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+func readEntities[T any](iter RowIterator) ([]*T, error) {
+	var ret []*T
+	for {
+		obj, err := readOne[T](iter)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil {
+			break
+		}
+		ret = append(ret, obj)
+	}
+	return ret, nil
+}
+
+// rowExists returns true if the query returned at least one row.
+func rowExists(iter RowIterator) (bool, error) {
+	err := iter.Next(nil)
+	if err == iterator.Done {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func addLimit(stmt *spanner.Statement, limit int) {
+	if limit > 0 {
+		stmt.SQL += " LIMIT @limit"
+		stmt.Params["limit"] = limit
+	}
+}
+
+type genericEntityOps[EntityType, KeyType any] struct {
+	client   Client
+	keyField string
+	table    string
+}
+
+func (g *genericEntityOps[EntityType, KeyType]) GetByID(ctx context.Context, key KeyType) (*EntityType, error) {
+	stmt := spanner.Statement{
+		SQL:    "SELECT * FROM " + g.table + " WHERE " + g.keyField + "=@key",
+		Params: map[string]interface{}{"key": key},
+	}
+	iter := g.client.Query(ctx, stmt)
+	defer iter.Stop()
+	return readOne[EntityType](iter)
+}
+
+var ErrEntityNotFound = errors.New("entity not found")
+
+func (g *genericEntityOps[EntityType, KeyType]) Update(ctx context.Context, key KeyType,
+	cb func(*EntityType) error) error {
+	return g.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
+			stmt := spanner.Statement{
+				SQL:    "SELECT * from `" + g.table + "` WHERE `" + g.keyField + "`=@key",
+				Params: map[string]interface{}{"key": key},
+			}
+			iter := txn.Query(ctx, stmt)
+			entity, err := readOne[EntityType](iter)
+			iter.Stop()
+			if err != nil {
+				return err
+			}
+			if entity == nil {
+				return ErrEntityNotFound
+			}
+			err = cb(entity)
+			if err != nil {
+				return err
+			}
+			return txn.Update(g.table, entity)
+		})
+}
+
+// errEntityExists is returned by Client implementations on primary key and unique index violations.
+var errEntityExists = errors.New("entity already exists")
+
+func (g *genericEntityOps[EntityType, KeyType]) Insert(ctx context.Context, obj *EntityType) error {
+	return g.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
+			return txn.Insert(g.table, obj)
+		})
+}
+
+func (g *genericEntityOps[EntityType, KeyType]) readEntities(ctx context.Context, stmt spanner.Statement) (
+	[]*EntityType, error) {
+	iter := g.client.Query(ctx, stmt)
+	defer iter.Stop()
+	return readEntities[EntityType](iter)
+}
+
+// NewTransientDB creates an empty database for the duration of the test.
+// If a Spanner emulator is available, it's used. Otherwise, an SQLite database is created.
+func NewTransientDB(t *testing.T) (Client, context.Context) {
+	// If the environment contains the emulator binary, start it.
+	if bin := os.Getenv("SPANNER_EMULATOR_BIN"); bin != "" {
+		host := spannerTestWrapper(t, bin)
+		os.Setenv("SPANNER_EMULATOR_HOST", host)
+	} else if os.Getenv("CI") != "" {
+		// We do want to always run these tests on CI.
+		t.Fatalf("CI is set, but SPANNER_EMULATOR_BIN is empty")
+	}
+	if os.Getenv("SPANNER_EMULATOR_HOST") != "" {
+		return newTransientSpanner(t)
+	}
+	return newTransientSQLite(t)
+}
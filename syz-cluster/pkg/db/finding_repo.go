@@ -9,15 +9,14 @@ import (
 
 	"cloud.google.com/go/spanner"
 	"github.com/google/uuid"
-	"google.golang.org/api/iterator"
 )
 
 type FindingRepository struct {
-	client *spanner.Client
+	client Client
 	*genericEntityOps[Finding, string]
 }
 
-func NewFindingRepository(client *spanner.Client) *FindingRepository {
+func NewFindingRepository(client Client) *FindingRepository {
 	return &FindingRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Finding, string]{
@@ -35,8 +34,8 @@ func (repo *FindingRepository) Save(ctx context.Context, finding *Finding) error
 	if finding.ID == "" {
 		finding.ID = uuid.NewString()
 	}
-	_, err := repo.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+	return repo.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
 			// Check if there is still no such finding.
 			stmt := spanner.Statement{
 				SQL: "SELECT * from `Findings` WHERE `SessionID`=@sessionID " +
@@ -49,19 +48,14 @@ func (repo *FindingRepository) Save(ctx context.Context, finding *Finding) error
 			}
 			iter := txn.Query(ctx, stmt)
 			defer iter.Stop()
-			_, iterErr := iter.Next()
-			if iterErr == nil {
-				return ErrFindingExists
-			} else if iterErr != iterator.Done {
-				return iterErr
-			}
-			m, err := spanner.InsertStruct("Findings", finding)
+			exists, err := rowExists(iter)
 			if err != nil {
 				return err
+			} else if exists {
+				return ErrFindingExists
 			}
-			return txn.BufferWrite([]*spanner.Mutation{m})
+			return txn.Insert("Findings", finding)
 		})
-	return err
 }
 
 // nolint: dupl
@@ -70,7 +64,7 @@ func (repo *FindingRepository) ListForSession(ctx context.Context, sessionID str
 		SQL:    "SELECT * FROM `Findings` WHERE `SessionID` = @session ORDER BY `TestName`, `Title`",
 		Params: map[string]interface{}{"session": sessionID},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readEntities[Finding](iter)
 }
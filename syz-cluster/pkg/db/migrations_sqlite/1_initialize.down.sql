@@ -0,0 +1,11 @@
+-- Series and Sessions reference each other, so the checks must only happen on commit.
+PRAGMA defer_foreign_keys = ON;
+
+DROP TABLE ReportReplies;
+DROP TABLE Findings;
+DROP TABLE SessionTests;
+DROP TABLE SessionReports;
+DROP TABLE Patches;
+DROP TABLE Builds;
+DROP TABLE Series;
+DROP TABLE Sessions;
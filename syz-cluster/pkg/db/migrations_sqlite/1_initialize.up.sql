@@ -0,0 +1,114 @@
+-- This is the SQLite counterpart of migrations/1_initialize.up.sql.
+-- Timestamps are stored as fixed width RFC3339 strings, arrays are stored as JSON.
+
+CREATE TABLE Series (
+    ID TEXT NOT NULL PRIMARY KEY, -- UUID
+    ExtID TEXT NOT NULL, -- For LKML, it's a message ID of the series.
+    AuthorName TEXT NOT NULL,
+    AuthorEmail TEXT NOT NULL,
+    Title TEXT NOT NULL,
+    Version INTEGER NOT NULL,
+    Link TEXT NOT NULL,
+    PublishedAt TEXT NOT NULL,
+    LatestSessionID TEXT,
+    Cc TEXT,
+    CONSTRAINT FK_SeriesLatestSession FOREIGN KEY (LatestSessionID) REFERENCES Sessions (ID)
+);
+
+CREATE INDEX SeriesByPublishedAt ON Series (PublishedAt);
+CREATE UNIQUE INDEX SeriesByExtID ON Series (ExtID);
+
+CREATE TABLE Patches (
+    ID TEXT NOT NULL PRIMARY KEY, -- UUID
+    SeriesID TEXT NOT NULL,
+    Seq INTEGER NOT NULL,
+    Title TEXT NOT NULL,
+    Link TEXT NOT NULL,
+    BodyURI TEXT NOT NULL,
+    CONSTRAINT FK_SeriesPatches FOREIGN KEY (SeriesID) REFERENCES Series (ID)
+);
+
+CREATE INDEX PatchesBySeriesAndSeq ON Patches (SeriesID, Seq);
+
+CREATE TABLE Builds (
+    ID TEXT NOT NULL PRIMARY KEY, -- UUID
+    TreeName TEXT NOT NULL,
+    CommitHash TEXT NOT NULL,
+    CommitDate TEXT NOT NULL,
+    SeriesID TEXT, -- NULL if no series were applied to the tree.
+    Arch TEXT NOT NULL,
+    ConfigName TEXT NOT NULL,
+    ConfigURI TEXT NOT NULL,
+    Status TEXT NOT NULL,
+    CONSTRAINT FK_Series FOREIGN KEY (SeriesID) REFERENCES Series (ID),
+    CONSTRAINT StatusEnum CHECK (Status IN ('build_failed', 'built', 'tests_failed', 'success'))
+);
+
+CREATE INDEX LastSuccessfulBuild ON Builds (TreeName, SeriesID, CommitDate DESC);
+
+CREATE TABLE Sessions (
+    ID TEXT NOT NULL PRIMARY KEY, -- UUID
+    SeriesID TEXT NOT NULL,
+    CreatedAt TEXT NOT NULL,
+    StartedAt TEXT,
+    FinishedAt TEXT,
+    SkipReason TEXT,
+    LogURI TEXT NOT NULL,
+    TriageLogURI TEXT NOT NULL,
+    Tags TEXT,
+    CONSTRAINT FK_SeriesSessions FOREIGN KEY (SeriesID) REFERENCES Series (ID)
+);
+
+CREATE INDEX SessionsByFinishedAt ON Sessions (FinishedAt);
+
+CREATE TABLE SessionTests (
+    SessionID TEXT NOT NULL, -- UUID
+    TestName TEXT NOT NULL,
+    UpdatedAt TEXT NOT NULL,
+    Result TEXT NOT NULL,
+    BaseBuildID TEXT,
+    PatchedBuildID TEXT,
+    LogURI TEXT NOT NULL,
+    ArtifactsArchiveURI TEXT NOT NULL,
+    PRIMARY KEY (SessionID, TestName),
+    CONSTRAINT FK_SessionResults FOREIGN KEY (SessionID) REFERENCES Sessions (ID),
+    CONSTRAINT ResultEnum CHECK (Result IN ('passed', 'failed', 'error', 'running')),
+    CONSTRAINT FK_BaseBuild FOREIGN KEY (BaseBuildID) REFERENCES Builds (ID),
+    CONSTRAINT FK_PatchedBuild FOREIGN KEY (PatchedBuildID) REFERENCES Builds (ID)
+);
+
+CREATE TABLE Findings (
+    ID TEXT NOT NULL PRIMARY KEY, -- UUID
+    SessionID TEXT NOT NULL,
+    TestName TEXT NOT NULL,
+    Title TEXT NOT NULL,
+    LogURI TEXT NOT NULL,
+    ReportURI TEXT NOT NULL,
+    SyzReproURI TEXT NOT NULL,
+    SyzReproOptsURI TEXT NOT NULL,
+    CReproURI TEXT NOT NULL,
+    CONSTRAINT FK_SessionCrashes FOREIGN KEY (SessionID) REFERENCES Sessions (ID),
+    CONSTRAINT FK_TestCrashes FOREIGN KEY (SessionID, TestName) REFERENCES SessionTests (SessionID, TestName)
+);
+
+CREATE UNIQUE INDEX NoDupFindings ON Findings(SessionID, TestName, Title);
+
+CREATE TABLE SessionReports (
+    ID TEXT NOT NULL PRIMARY KEY, -- UUID
+    SessionID TEXT NOT NULL, -- UUID
+    ReportedAt TEXT,
+    Moderation BOOLEAN,
+    Reporter TEXT,
+    CONSTRAINT FK_SessionReports FOREIGN KEY (SessionID) REFERENCES Sessions (ID)
+);
+
+CREATE UNIQUE INDEX NoDupSessionReports ON SessionReports(SessionID, Moderation);
+CREATE INDEX SessionReportsByStatus ON SessionReports (Reporter, ReportedAt);
+
+CREATE TABLE ReportReplies (
+    MessageID TEXT NOT NULL,
+    ReportID TEXT NOT NULL, -- UUID
+    Time TEXT,
+    PRIMARY KEY (MessageID, ReportID),
+    CONSTRAINT FK_ReplyReportID FOREIGN KEY (ReportID) REFERENCES SessionReports (ID)
+);
@@ -11,10 +11,10 @@ import (
 )
 
 type ReportReplyRepository struct {
-	client *spanner.Client
+	client Client
 }
 
-func NewReportReplyRepository(client *spanner.Client) *ReportReplyRepository {
+func NewReportReplyRepository(client Client) *ReportReplyRepository {
 	return &ReportReplyRepository{
 		client: client,
 	}
@@ -31,7 +31,7 @@ func (repo *ReportReplyRepository) FindParentReportID(ctx context.Context, repor
 			"messageID": messageID,
 		},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 
 	type result struct {
@@ -49,8 +49,8 @@ func (repo *ReportReplyRepository) FindParentReportID(ctx context.Context, repor
 var ErrReportReplyExists = errors.New("the reply has already been recorded")
 
 func (repo *ReportReplyRepository) Insert(ctx context.Context, reply *ReportReply) error {
-	_, err := repo.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+	return repo.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
 			stmt := spanner.Statement{
 				SQL: "SELECT * from `ReportReplies` " +
 					"WHERE `ReportID`=@reportID AND `MessageID`=@messageID",
@@ -67,13 +67,8 @@ func (repo *ReportReplyRepository) Insert(ctx context.Context, reply *ReportRepl
 			} else if entity != nil {
 				return ErrReportReplyExists
 			}
-			insert, err := spanner.InsertStruct("ReportReplies", reply)
-			if err != nil {
-				return err
-			}
-			return txn.BufferWrite([]*spanner.Mutation{insert})
+			return txn.Insert("ReportReplies", reply)
 		})
-	return err
 }
 
 func (repo *ReportReplyRepository) LastForReporter(ctx context.Context, reporter string) (*ReportReply, error) {
@@ -86,7 +81,7 @@ func (repo *ReportReplyRepository) LastForReporter(ctx context.Context, reporter
 			"reporter": reporter,
 		},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readOne[ReportReply](iter)
 }
@@ -13,11 +13,11 @@ import (
 )
 
 type ReportRepository struct {
-	client *spanner.Client
+	client Client
 	*genericEntityOps[SessionReport, string]
 }
 
-func NewReportRepository(client *spanner.Client) *ReportRepository {
+func NewReportRepository(client Client) *ReportRepository {
 	return &ReportRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[SessionReport, string]{
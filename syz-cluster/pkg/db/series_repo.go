@@ -14,15 +14,14 @@ import (
 
 	"cloud.google.com/go/spanner"
 	"github.com/google/uuid"
-	"google.golang.org/api/iterator"
 )
 
 type SeriesRepository struct {
-	client *spanner.Client
+	client Client
 	*genericEntityOps[Series, string]
 }
 
-func NewSeriesRepository(client *spanner.Client) *SeriesRepository {
+func NewSeriesRepository(client Client) *SeriesRepository {
 	return &SeriesRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Series, string]{
@@ -40,7 +39,7 @@ func (repo *SeriesRepository) PatchByID(ctx context.Context, id string) (*Patch,
 		SQL:    "SELECT * FROM Patches WHERE ID=@id",
 		Params: map[string]interface{}{"id": id},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readOne[Patch](iter)
 }
@@ -51,7 +50,7 @@ func (repo *SeriesRepository) GetByExtID(ctx context.Context, extID string) (*Se
 		SQL:    "SELECT * FROM Series WHERE ExtID=@extID",
 		Params: map[string]interface{}{"extID": extID},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readOne[Series](iter)
 }
@@ -75,8 +74,8 @@ func (repo *SeriesRepository) Insert(ctx context.Context, series *Series,
 	if series.ID == "" {
 		series.ID = uuid.NewString()
 	}
-	_, err := repo.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+	return repo.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
 			// Check if the series already exists.
 			stmt := spanner.Statement{
 				SQL:    "SELECT 1 from `Series` WHERE `ExtID`=@extID",
@@ -85,11 +84,11 @@ func (repo *SeriesRepository) Insert(ctx context.Context, series *Series,
 			iter := txn.Query(ctx, stmt)
 			defer iter.Stop()
 
-			_, iterErr := iter.Next()
-			if iterErr == nil {
+			exists, err := rowExists(iter)
+			if err != nil {
+				return err
+			} else if exists {
 				return ErrSeriesExists
-			} else if iterErr != iterator.Done {
-				return iterErr
 			}
 			// Query patches (once).
 			patchesOnce.Do(doQueryPatches)
@@ -97,33 +96,32 @@ func (repo *SeriesRepository) Insert(ctx context.Context, series *Series,
 				return patchesErr
 			}
 			// Save the objects.
-			var stmts []*spanner.Mutation
-			seriesStmt, err := spanner.InsertStruct("Series", series)
-			if err != nil {
+			if err := txn.Insert("Series", series); err != nil {
 				return err
 			}
-			stmts = append(stmts, seriesStmt)
 			for _, patch := range patches {
 				patch.ID = uuid.NewString()
 				patch.SeriesID = series.ID
-				stmt, err := spanner.InsertStruct("Patches", patch)
-				if err != nil {
+				if err := txn.Insert("Patches", patch); err != nil {
 					return err
 				}
-				stmts = append(stmts, stmt)
 			}
-			return txn.BufferWrite(stmts)
+			return nil
 		})
-	return err
 }
 
 func (repo *SeriesRepository) Count(ctx context.Context) (int, error) {
-	stmt := spanner.Statement{SQL: "SELECT COUNT(*) FROM `Series`"}
-	var count int64
-	err := repo.client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
-		return row.Column(0, &count)
-	})
-	return int(count), err
+	stmt := spanner.Statement{SQL: "SELECT COUNT(*) AS `Count` FROM `Series`"}
+	iter := repo.client.Query(ctx, stmt)
+	defer iter.Stop()
+	type result struct {
+		Count int64 `spanner:"Count"`
+	}
+	ret, err := readOne[result](iter)
+	if err != nil || ret == nil {
+		return 0, err
+	}
+	return int(ret.Count), nil
 }
 
 type SeriesWithSession struct {
@@ -143,9 +141,6 @@ type SeriesFilter struct {
 // ListLatest() returns the list of series ordered by the decreasing PublishedAt value.
 func (repo *SeriesRepository) ListLatest(ctx context.Context, filter SeriesFilter,
 	maxPublishedAt time.Time) ([]*SeriesWithSession, error) {
-	ro := repo.client.ReadOnlyTransaction()
-	defer ro.Close()
-
 	stmt := spanner.Statement{
 		SQL:    "SELECT Series.* FROM Series WHERE 1=1",
 		Params: map[string]interface{}{},
@@ -191,34 +186,37 @@ func (repo *SeriesRepository) ListLatest(ctx context.Context, filter SeriesFilte
 		stmt.SQL += " OFFSET @offset"
 		stmt.Params["offset"] = filter.Offset
 	}
-	iter := ro.Query(ctx, stmt)
-	defer iter.Stop()
+	var ret []*SeriesWithSession
+	err := repo.client.ReadOnlyTransaction(ctx, func(ctx context.Context, ro Transaction) error {
+		iter := ro.Query(ctx, stmt)
+		defer iter.Stop()
 
-	seriesList, err := readEntities[Series](iter)
-	if err != nil {
-		return nil, err
-	}
+		seriesList, err := readEntities[Series](iter)
+		if err != nil {
+			return err
+		}
 
-	// Now query Sessions.
-	var ret []*SeriesWithSession
-	for _, series := range seriesList {
-		obj := &SeriesWithSession{Series: series}
-		ret = append(ret, obj)
-	}
+		// Now query Sessions.
+		for _, series := range seriesList {
+			obj := &SeriesWithSession{Series: series}
+			ret = append(ret, obj)
+		}
 
-	// And the rest of the data.
-	err = repo.querySessions(ctx, ro, ret)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query sessions: %w", err)
-	}
-	err = repo.queryFindingCounts(ctx, ro, ret)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query finding counts: %w", err)
-	}
-	return ret, nil
+		// And the rest of the data.
+		err = repo.querySessions(ctx, ro, ret)
+		if err != nil {
+			return fmt.Errorf("failed to query sessions: %w", err)
+		}
+		err = repo.queryFindingCounts(ctx, ro, ret)
+		if err != nil {
+			return fmt.Errorf("failed to query finding counts: %w", err)
+		}
+		return nil
+	})
+	return ret, err
 }
 
-func (repo *SeriesRepository) querySessions(ctx context.Context, ro *spanner.ReadOnlyTransaction,
+func (repo *SeriesRepository) querySessions(ctx context.Context, ro Transaction,
 	seriesList []*SeriesWithSession) error {
 	idToSeries := map[string]*SeriesWithSession{}
 	var keys []string
@@ -252,7 +250,7 @@ func (repo *SeriesRepository) querySessions(ctx context.Context, ro *spanner.Rea
 	return nil
 }
 
-func (repo *SeriesRepository) queryFindingCounts(ctx context.Context, ro *spanner.ReadOnlyTransaction,
+func (repo *SeriesRepository) queryFindingCounts(ctx context.Context, ro Transaction,
 	seriesList []*SeriesWithSession) error {
 	var keys []string
 	sessionToSeries := map[string]*SeriesWithSession{}
@@ -279,7 +277,7 @@ func (repo *SeriesRepository) queryFindingCounts(ctx context.Context, ro *spanne
 			"ids": keys,
 		},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := ro.Query(ctx, stmt)
 	defer iter.Stop()
 
 	list, err := readEntities[findingCount](iter)
@@ -301,7 +299,7 @@ func (repo *SeriesRepository) ListPatches(ctx context.Context, series *Series) (
 			"seriesID": series.ID,
 		},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readEntities[Patch](iter)
 }
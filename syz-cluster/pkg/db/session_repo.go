@@ -13,11 +13,11 @@ import (
 )
 
 type SessionRepository struct {
-	client *spanner.Client
+	client Client
 	*genericEntityOps[Session, string]
 }
 
-func NewSessionRepository(client *spanner.Client) *SessionRepository {
+func NewSessionRepository(client Client) *SessionRepository {
 	return &SessionRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Session, string]{
@@ -31,8 +31,8 @@ func NewSessionRepository(client *spanner.Client) *SessionRepository {
 var ErrSessionAlreadyStarted = errors.New("the session already started")
 
 func (repo *SessionRepository) Start(ctx context.Context, sessionID string) error {
-	_, err := repo.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+	return repo.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
 			iter := txn.Query(ctx, spanner.Statement{
 				SQL:    "SELECT * from `Sessions` WHERE `ID`=@id",
 				Params: map[string]interface{}{"id": sessionID},
@@ -46,10 +46,6 @@ func (repo *SessionRepository) Start(ctx context.Context, sessionID string) erro
 				return ErrSessionAlreadyStarted
 			}
 			session.SetStartedAt(time.Now())
-			updateSession, err := spanner.UpdateStruct("Sessions", session)
-			if err != nil {
-				return err
-			}
 			iter = txn.Query(ctx, spanner.Statement{
 				SQL:    "SELECT * from `Series` WHERE `ID`=@id",
 				Params: map[string]interface{}{"id": session.SeriesID},
@@ -60,13 +56,11 @@ func (repo *SessionRepository) Start(ctx context.Context, sessionID string) erro
 				return err
 			}
 			series.SetLatestSession(session)
-			updateSeries, err := spanner.UpdateStruct("Series", series)
-			if err != nil {
+			if err := txn.Update("Series", series); err != nil {
 				return err
 			}
-			return txn.BufferWrite([]*spanner.Mutation{updateSeries, updateSession})
+			return txn.Update("Sessions", session)
 		})
-	return err
 }
 
 func (repo *SessionRepository) Insert(ctx context.Context, session *Session) error {
@@ -79,7 +73,7 @@ func (repo *SessionRepository) Insert(ctx context.Context, session *Session) err
 func (repo *SessionRepository) ListRunning(ctx context.Context) ([]*Session, error) {
 	stmt := spanner.Statement{SQL: "SELECT * FROM `Sessions` WHERE `StartedAt` IS NOT NULL " +
 		"AND `FinishedAt` IS NULL"}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readEntities[Session](iter)
 }
@@ -7,14 +7,13 @@ import (
 	"context"
 
 	"cloud.google.com/go/spanner"
-	"google.golang.org/api/iterator"
 )
 
 type SessionTestRepository struct {
-	client *spanner.Client
+	client Client
 }
 
-func NewSessionTestRepository(client *spanner.Client) *SessionTestRepository {
+func NewSessionTestRepository(client Client) *SessionTestRepository {
 	return &SessionTestRepository{
 		client: client,
 	}
@@ -23,8 +22,8 @@ func NewSessionTestRepository(client *spanner.Client) *SessionTestRepository {
 // If the beforeSave callback is specified, it will be called before saving the entity.
 func (repo *SessionTestRepository) InsertOrUpdate(ctx context.Context, test *SessionTest,
 	beforeSave func(*SessionTest)) error {
-	_, err := repo.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+	return repo.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn Transaction) error {
 			// Check if the test already exists.
 			stmt := spanner.Statement{
 				SQL: "SELECT * from `SessionTests` WHERE `SessionID`=@sessionID AND `TestName` = @testName",
@@ -36,33 +35,18 @@ func (repo *SessionTestRepository) InsertOrUpdate(ctx context.Context, test *Ses
 			iter := txn.Query(ctx, stmt)
 			defer iter.Stop()
 
-			var stmts []*spanner.Mutation
-
-			_, iterErr := iter.Next()
-			if iterErr == nil {
-				if beforeSave != nil {
-					beforeSave(test)
-				}
-				m, err := spanner.UpdateStruct("SessionTests", test)
-				if err != nil {
-					return err
-				}
-				stmts = append(stmts, m)
-			} else if iterErr != iterator.Done {
-				return iterErr
-			} else {
-				if beforeSave != nil {
-					beforeSave(test)
-				}
-				m, err := spanner.InsertStruct("SessionTests", test)
-				if err != nil {
-					return err
-				}
-				stmts = append(stmts, m)
+			exists, err := rowExists(iter)
+			if err != nil {
+				return err
+			}
+			if beforeSave != nil {
+				beforeSave(test)
+			}
+			if exists {
+				return txn.Update("SessionTests", test)
 			}
-			return txn.BufferWrite(stmts)
+			return txn.Insert("SessionTests", test)
 		})
-	return err
 }
 
 func (repo *SessionTestRepository) Get(ctx context.Context, sessionID, testName string) (*SessionTest, error) {
@@ -73,7 +57,7 @@ func (repo *SessionTestRepository) Get(ctx context.Context, sessionID, testName
 			"name":    testName,
 		},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readOne[SessionTest](iter)
 }
@@ -110,7 +94,7 @@ func (repo *SessionTestRepository) BySession(ctx context.Context, sessionID stri
 			SQL:    "SELECT * FROM `Builds` WHERE `ID` IN UNNEST(@ids)",
 			Params: map[string]interface{}{"ids": keys},
 		}
-		iter := repo.client.Single().Query(ctx, stmt)
+		iter := repo.client.Query(ctx, stmt)
 		defer iter.Stop()
 		builds, err := readEntities[Build](iter)
 		if err != nil {
@@ -131,7 +115,7 @@ func (repo *SessionTestRepository) BySessionRaw(ctx context.Context, sessionID s
 			" ORDER BY `UpdatedAt`",
 		Params: map[string]interface{}{"session": sessionID},
 	}
-	iter := repo.client.Single().Query(ctx, stmt)
+	iter := repo.client.Query(ctx, stmt)
 	defer iter.Stop()
 	return readEntities[SessionTest](iter)
 }
@@ -7,10 +7,8 @@ import (
 	"bufio"
 	"context"
 	"embed"
-	"errors"
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -26,7 +24,6 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	migrate_spanner "github.com/golang-migrate/migrate/v4/database/spanner"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
-	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -102,6 +99,102 @@ func dropSpannerDB(ctx context.Context, uri ParsedURI) error {
 	return client.DropDatabase(ctx, &databasepb.DropDatabaseRequest{Database: uri.Full})
 }
 
+type spannerClient struct {
+	client *spanner.Client
+	uri    string
+}
+
+// NewSpannerClient wraps the Spanner client to be used by the repositories.
+func NewSpannerClient(client *spanner.Client) Client {
+	return &spannerClient{client: client, uri: client.DatabaseName()}
+}
+
+func (c *spannerClient) Query(ctx context.Context, stmt spanner.Statement) RowIterator {
+	return &spannerRowIterator{c.client.Single().Query(ctx, stmt)}
+}
+
+func (c *spannerClient) ReadOnlyTransaction(ctx context.Context,
+	f func(context.Context, Transaction) error) error {
+	ro := c.client.ReadOnlyTransaction()
+	defer ro.Close()
+	return f(ctx, &spannerTransaction{ro: ro})
+}
+
+func (c *spannerClient) ReadWriteTransaction(ctx context.Context,
+	f func(context.Context, Transaction) error) error {
+	_, err := c.client.ReadWriteTransaction(ctx,
+		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+			return f(ctx, &spannerTransaction{rw: txn})
+		})
+	if status.Code(err) == codes.AlreadyExists {
+		return errEntityExists
+	}
+	return err
+}
+
+func (c *spannerClient) Close() {
+	c.client.Close()
+}
+
+func (c *spannerClient) migrateInstance() (*migrate.Migrate, error) {
+	return getMigrateInstance(c.uri)
+}
+
+// Only one of the fields is set.
+type spannerTransaction struct {
+	ro *spanner.ReadOnlyTransaction
+	rw *spanner.ReadWriteTransaction
+}
+
+func (txn *spannerTransaction) Query(ctx context.Context, stmt spanner.Statement) RowIterator {
+	if txn.rw != nil {
+		return &spannerRowIterator{txn.rw.Query(ctx, stmt)}
+	}
+	return &spannerRowIterator{txn.ro.Query(ctx, stmt)}
+}
+
+func (txn *spannerTransaction) Insert(table string, obj any) error {
+	m, err := spanner.InsertStruct(table, obj)
+	if err != nil {
+		return err
+	}
+	return txn.bufferWrite(m)
+}
+
+func (txn *spannerTransaction) Update(table string, obj any) error {
+	m, err := spanner.UpdateStruct(table, obj)
+	if err != nil {
+		return err
+	}
+	return txn.bufferWrite(m)
+}
+
+func (txn *spannerTransaction) bufferWrite(m *spanner.Mutation) error {
+	if txn.rw == nil {
+		return fmt.Errorf("cannot write in a read-only transaction")
+	}
+	return txn.rw.BufferWrite([]*spanner.Mutation{m})
+}
+
+type spannerRowIterator struct {
+	iter *spanner.RowIterator
+}
+
+func (it *spannerRowIterator) Next(dst any) error {
+	row, err := it.iter.Next()
+	if err != nil {
+		return err
+	}
+	if dst == nil {
+		return nil
+	}
+	return row.ToStruct(dst)
+}
+
+func (it *spannerRowIterator) Stop() {
+	it.iter.Stop()
+}
+
 //go:embed migrations/*.sql
 var migrationsFs embed.FS
 
@@ -135,19 +228,7 @@ func getMigrateInstance(uri string) (*migrate.Migrate, error) {
 	return m, nil
 }
 
-func NewTransientDB(t *testing.T) (*spanner.Client, context.Context) {
-	// If the environment contains the emulator binary, start it.
-	if bin := os.Getenv("SPANNER_EMULATOR_BIN"); bin != "" {
-		host := spannerTestWrapper(t, bin)
-		os.Setenv("SPANNER_EMULATOR_HOST", host)
-	} else if os.Getenv("CI") != "" {
-		// We do want to always run these tests on CI.
-		t.Fatalf("CI is set, but SPANNER_EMULATOR_BIN is empty")
-	}
-	if os.Getenv("SPANNER_EMULATOR_HOST") == "" {
-		t.Skip("SPANNER_EMULATOR_HOST must be set")
-		return nil, nil
-	}
+func newTransientSpanner(t *testing.T) (Client, context.Context) {
 	uri, err := ParseURI("projects/my-project/instances/test-instance/databases/" +
 		fmt.Sprintf("db%v", time.Now().UnixNano()))
 	if err != nil {
@@ -177,7 +258,7 @@ func NewTransientDB(t *testing.T) (*spanner.Client, context.Context) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	return client, ctx
+	return NewSpannerClient(client), ctx
 }
 
 var setupSpannerOnce sync.Once
@@ -239,113 +320,3 @@ func runSpanner(bin string) (*exec.Cmd, string, error) {
 	}
 	return cmd, host, nil
 }
-
-func readOne[T any](iter *spanner.RowIterator) (*T, error) {
-	row, err := iter.Next()
-	if err == iterator.Done {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-	var obj T
-	err = row.ToStruct(&obj)
-	if err != nil {
-		return nil, err
-	}
-	return &obj, nil
-}
-
-func readEntities[T any](iter *spanner.RowIterator) ([]*T, error) {
-	var ret []*T
-	for {
-		obj, err := readOne[T](iter)
-		if err != nil {
-			return nil, err
-		}
-		if obj == nil {
-			break
-		}
-		ret = append(ret, obj)
-	}
-	return ret, nil
-}
-
-func addLimit(stmt *spanner.Statement, limit int) {
-	if limit > 0 {
-		stmt.SQL += " LIMIT @limit"
-		stmt.Params["limit"] = limit
-	}
-}
-
-type genericEntityOps[EntityType, KeyType any] struct {
-	client   *spanner.Client
-	keyField string
-	table    string
-}
-
-func (g *genericEntityOps[EntityType, KeyType]) GetByID(ctx context.Context, key KeyType) (*EntityType, error) {
-	stmt := spanner.Statement{
-		SQL:    "SELECT * FROM " + g.table + " WHERE " + g.keyField + "=@key",
-		Params: map[string]interface{}{"key": key},
-	}
-	iter := g.client.Single().Query(ctx, stmt)
-	defer iter.Stop()
-	return readOne[EntityType](iter)
-}
-
-var ErrEntityNotFound = errors.New("entity not found")
-
-func (g *genericEntityOps[EntityType, KeyType]) Update(ctx context.Context, key KeyType,
-	cb func(*EntityType) error) error {
-	_, err := g.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
-			stmt := spanner.Statement{
-				SQL:    "SELECT * from `" + g.table + "` WHERE `" + g.keyField + "`=@key",
-				Params: map[string]interface{}{"key": key},
-			}
-			iter := txn.Query(ctx, stmt)
-			entity, err := readOne[EntityType](iter)
-			iter.Stop()
-			if err != nil {
-				return err
-			}
-			if entity == nil {
-				return ErrEntityNotFound
-			}
-			err = cb(entity)
-			if err != nil {
-				return err
-			}
-			m, err := spanner.UpdateStruct(g.table, entity)
-			if err != nil {
-				return err
-			}
-			return txn.BufferWrite([]*spanner.Mutation{m})
-		})
-	return err
-}
-
-var errEntityExists = errors.New("entity already exists")
-
-func (g *genericEntityOps[EntityType, KeyType]) Insert(ctx context.Context, obj *EntityType) error {
-	_, err := g.client.ReadWriteTransaction(ctx,
-		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
-			insert, err := spanner.InsertStruct(g.table, obj)
-			if err != nil {
-				return err
-			}
-			return txn.BufferWrite([]*spanner.Mutation{insert})
-		})
-	if status.Code(err) == codes.AlreadyExists {
-		return errEntityExists
-	}
-	return err
-}
-
-func (g *genericEntityOps[EntityType, KeyType]) readEntities(ctx context.Context, stmt spanner.Statement) (
-	[]*EntityType, error) {
-	iter := g.client.Single().Query(ctx, stmt)
-	defer iter.Stop()
-	return readEntities[EntityType](iter)
-}
@@ -12,7 +12,7 @@ import (
 func TestMigrations(t *testing.T) {
 	// Run, rollback and then again apply all DB migrations.
 	client, _ := NewTransientDB(t)
-	m, err := getMigrateInstance(client.DatabaseName())
+	m, err := client.migrateInstance()
 	require.NoError(t, err)
 	err = m.Down()
 	require.NoError(t, err, "migrating down failed")
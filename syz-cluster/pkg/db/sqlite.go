@@ -0,0 +1,453 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/golang-migrate/migrate/v4"
+	migrate_sqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"google.golang.org/api/iterator"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// The SQLite schema must be kept in sync with the Spanner one: each migration in migrations/
+// must have a counterpart with the same version in migrations_sqlite/.
+// TestSQLiteSchemaInSync checks that the resulting schemas match.
+//
+//go:embed migrations_sqlite/*.sql
+var sqliteMigrationsFs embed.FS
+
+type sqliteClient struct {
+	db *sql.DB
+
+	mu          sync.Mutex
+	primaryKeys map[string][]string
+}
+
+// NewSQLiteClient opens (or creates) an SQLite database file and applies the schema migrations.
+func NewSQLiteClient(path string) (Client, error) {
+	// Spanner transactions are serializable, so we take the write lock right away.
+	// Otherwise concurrent read-write transactions would fail when upgrading their locks.
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)" +
+		"&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	client := &sqliteClient{
+		db:          db,
+		primaryKeys: map[string][]string{},
+	}
+	m, err := client.migrateInstance()
+	if err == nil {
+		err = m.Up()
+	}
+	if err != nil && err != migrate.ErrNoChange {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate the SQLite DB: %w", err)
+	}
+	return client, nil
+}
+
+func newTransientSQLite(t *testing.T) (Client, context.Context) {
+	client, err := NewSQLiteClient(filepath.Join(t.TempDir(), "db.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client, context.Background()
+}
+
+func (c *sqliteClient) Query(ctx context.Context, stmt spanner.Statement) RowIterator {
+	return sqliteQuery(ctx, c.db, stmt)
+}
+
+func (c *sqliteClient) ReadOnlyTransaction(ctx context.Context,
+	f func(context.Context, Transaction) error) error {
+	return c.transaction(ctx, &sql.TxOptions{ReadOnly: true}, f)
+}
+
+func (c *sqliteClient) ReadWriteTransaction(ctx context.Context,
+	f func(context.Context, Transaction) error) error {
+	return c.transaction(ctx, nil, f)
+}
+
+func (c *sqliteClient) transaction(ctx context.Context, opts *sql.TxOptions,
+	f func(context.Context, Transaction) error) error {
+	tx, err := c.db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	err = f(ctx, &sqliteTransaction{client: c, tx: tx})
+	if err == nil {
+		err = tx.Commit()
+	} else {
+		tx.Rollback()
+	}
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+			return errEntityExists
+		}
+	}
+	return err
+}
+
+func (c *sqliteClient) Close() {
+	c.db.Close()
+}
+
+func (c *sqliteClient) migrateInstance() (*migrate.Migrate, error) {
+	sourceDriver, err := iofs.New(sqliteMigrationsFs, "migrations_sqlite")
+	if err != nil {
+		return nil, err
+	}
+	dbDriver, err := migrate_sqlite.WithInstance(c.db, &migrate_sqlite.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", sourceDriver, "sqlite", dbDriver)
+}
+
+func (c *sqliteClient) tablePrimaryKey(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if keys, ok := c.primaryKeys[table]; ok {
+		return keys, nil
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		keys = append(keys, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("table %q has no primary key", table)
+	}
+	c.primaryKeys[table] = keys
+	return keys, nil
+}
+
+type sqliteTransaction struct {
+	client *sqliteClient
+	tx     *sql.Tx
+}
+
+func (txn *sqliteTransaction) Query(ctx context.Context, stmt spanner.Statement) RowIterator {
+	return sqliteQuery(ctx, txn.tx, stmt)
+}
+
+func (txn *sqliteTransaction) Insert(table string, obj any) error {
+	columns, values, err := sqliteStructValues(obj)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO `%s` (`%s`) VALUES (%s)", table,
+		strings.Join(columns, "`, `"), sqlitePlaceholders(len(columns)))
+	_, err = txn.tx.Exec(query, values...)
+	return err
+}
+
+func (txn *sqliteTransaction) Update(table string, obj any) error {
+	ctx := context.Background()
+	keys, err := txn.client.tablePrimaryKey(ctx, txn.tx, table)
+	if err != nil {
+		return err
+	}
+	columns, values, err := sqliteStructValues(obj)
+	if err != nil {
+		return err
+	}
+	var set, where []string
+	var setArgs, whereArgs []any
+	for i, column := range columns {
+		isKey := false
+		for _, key := range keys {
+			isKey = isKey || strings.EqualFold(key, column)
+		}
+		if isKey {
+			where = append(where, "`"+column+"` = ?")
+			whereArgs = append(whereArgs, values[i])
+		} else {
+			set = append(set, "`"+column+"` = ?")
+			setArgs = append(setArgs, values[i])
+		}
+	}
+	if len(where) != len(keys) {
+		return fmt.Errorf("%T does not contain all primary key columns of %q", obj, table)
+	}
+	query := fmt.Sprintf("UPDATE `%s` SET %s WHERE %s", table,
+		strings.Join(set, ", "), strings.Join(where, " AND "))
+	res, err := txn.tx.ExecContext(ctx, query, append(setArgs, whereArgs...)...)
+	if err != nil {
+		return err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if updated == 0 {
+		return ErrEntityNotFound
+	}
+	return nil
+}
+
+type sqliteQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func sqliteQuery(ctx context.Context, q sqliteQueryer, stmt spanner.Statement) RowIterator {
+	query, args, err := translateToSQLite(stmt)
+	if err != nil {
+		return &sqliteRowIterator{err: err}
+	}
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return &sqliteRowIterator{err: fmt.Errorf("%w (query: %s)", err, query)}
+	}
+	return &sqliteRowIterator{rows: rows}
+}
+
+type sqliteRowIterator struct {
+	rows *sql.Rows
+	err  error
+}
+
+func (it *sqliteRowIterator) Next(dst any) error {
+	if it.err != nil {
+		return it.err
+	}
+	if !it.rows.Next() {
+		if err := it.rows.Err(); err != nil {
+			return err
+		}
+		return iterator.Done
+	}
+	if dst == nil {
+		return nil
+	}
+	columns, err := it.rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := it.rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	obj := reflect.ValueOf(dst).Elem()
+	for i, column := range columns {
+		field, ok := sqliteStructField(obj, column)
+		if !ok {
+			continue
+		}
+		if err := sqliteDecode(field, values[i]); err != nil {
+			return fmt.Errorf("column %q: %w", column, err)
+		}
+	}
+	return nil
+}
+
+func (it *sqliteRowIterator) Stop() {
+	if it.rows != nil {
+		it.rows.Close()
+	}
+}
+
+var (
+	// Array columns are stored as JSON.
+	sqliteArrayContainsRe = regexp.MustCompile(`(?i)(@\w+)\s+IN\s+UNNEST\((\w+)\)`)
+	sqliteInArrayRe       = regexp.MustCompile(`(?i)IN\s+UNNEST\((@\w+)\)`)
+	sqliteParamRe         = regexp.MustCompile(`@\w+`)
+)
+
+// translateToSQLite converts a statement in the Spanner SQL dialect to an SQLite query.
+func translateToSQLite(stmt spanner.Statement) (string, []any, error) {
+	query := sqliteArrayContainsRe.ReplaceAllString(stmt.SQL, "$1 IN (SELECT value FROM json_each($2))")
+	query = sqliteInArrayRe.ReplaceAllString(query, "IN ($1)")
+	var args []any
+	var err error
+	query = sqliteParamRe.ReplaceAllStringFunc(query, func(param string) string {
+		val, ok := lookupParam(stmt.Params, param[1:])
+		if !ok {
+			err = errors.Join(err, fmt.Errorf("no value for parameter %s", param))
+			return param
+		}
+		if list, ok := val.([]string); ok {
+			// It's only valid in the IN UNNEST(@param) context.
+			if len(list) == 0 {
+				return "NULL"
+			}
+			for _, item := range list {
+				args = append(args, item)
+			}
+			return sqlitePlaceholders(len(list))
+		}
+		arg, encodeErr := sqliteEncode(val)
+		err = errors.Join(err, encodeErr)
+		args = append(args, arg)
+		return "?"
+	})
+	return query, args, err
+}
+
+// Similarly to Spanner, parameter names are case insensitive.
+func lookupParam(params map[string]any, name string) (any, bool) {
+	if val, ok := params[name]; ok {
+		return val, true
+	}
+	for key, val := range params {
+		if strings.EqualFold(key, name) {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+func sqlitePlaceholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", count), ", ")
+}
+
+// The fixed width format lets us compare the timestamps as strings.
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+func sqliteEncode(val any) (any, error) {
+	switch v := val.(type) {
+	case nil, string, bool, int, int64:
+		return v, nil
+	case time.Time:
+		return v.UTC().Format(sqliteTimeFormat), nil
+	case spanner.NullTime:
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.Time.UTC().Format(sqliteTimeFormat), nil
+	case spanner.NullString:
+		if !v.Valid {
+			return nil, nil
+		}
+		return v.StringVal, nil
+	case []string:
+		if v == nil {
+			return nil, nil
+		}
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+	return nil, fmt.Errorf("unsupported value type %T", val)
+}
+
+func sqliteDecode(field reflect.Value, val any) error {
+	if b, ok := val.([]byte); ok {
+		val = string(b)
+	}
+	switch ptr := field.Addr().Interface().(type) {
+	case *string:
+		*ptr, _ = val.(string)
+	case *int64:
+		*ptr, _ = val.(int64)
+	case *bool:
+		num, _ := val.(int64)
+		*ptr = num != 0
+	case *time.Time:
+		return sqliteDecodeTime(val, ptr)
+	case *spanner.NullTime:
+		*ptr = spanner.NullTime{Valid: val != nil}
+		return sqliteDecodeTime(val, &ptr.Time)
+	case *spanner.NullString:
+		*ptr = spanner.NullString{}
+		ptr.StringVal, ptr.Valid = val.(string)
+	case *[]string:
+		*ptr = nil
+		if str, ok := val.(string); ok {
+			return json.Unmarshal([]byte(str), ptr)
+		}
+	default:
+		return fmt.Errorf("unsupported field type %T", ptr)
+	}
+	return nil
+}
+
+func sqliteDecodeTime(val any, ret *time.Time) error {
+	*ret = time.Time{}
+	str, ok := val.(string)
+	if !ok {
+		return nil
+	}
+	var err error
+	*ret, err = time.Parse(time.RFC3339Nano, str)
+	return err
+}
+
+// sqliteStructValues returns the column names and the encoded values of the struct fields.
+func sqliteStructValues(obj any) ([]string, []any, error) {
+	val := reflect.Indirect(reflect.ValueOf(obj))
+	var columns []string
+	var values []any
+	for i := 0; i < val.NumField(); i++ {
+		name := sqliteColumnName(val.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		encoded, err := sqliteEncode(val.Field(i).Interface())
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		columns = append(columns, name)
+		values = append(values, encoded)
+	}
+	return columns, values, nil
+}
+
+func sqliteStructField(obj reflect.Value, column string) (reflect.Value, bool) {
+	for i := 0; i < obj.NumField(); i++ {
+		if strings.EqualFold(sqliteColumnName(obj.Type().Field(i)), column) {
+			return obj.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// sqliteColumnName follows the rules of the Spanner library: the `spanner` tag or the field name.
+func sqliteColumnName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	tag := field.Tag.Get("spanner")
+	if tag == "-" {
+		return ""
+	} else if tag != "" {
+		return tag
+	}
+	return field.Name
+}
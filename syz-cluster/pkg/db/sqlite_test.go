@@ -0,0 +1,301 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteMigrationsInSync(t *testing.T) {
+	spannerFiles, err := fs.Glob(migrationsFs, "migrations/*.sql")
+	require.NoError(t, err)
+	sqliteFiles, err := fs.Glob(sqliteMigrationsFs, "migrations_sqlite/*.sql")
+	require.NoError(t, err)
+	var spannerNames, sqliteNames []string
+	for _, name := range spannerFiles {
+		spannerNames = append(spannerNames, strings.TrimPrefix(name, "migrations/"))
+	}
+	for _, name := range sqliteFiles {
+		sqliteNames = append(sqliteNames, strings.TrimPrefix(name, "migrations_sqlite/"))
+	}
+	assert.Equal(t, spannerNames, sqliteNames)
+}
+
+// TestSQLiteSchemaInSync checks that the SQLite migrations produce the same tables, columns, keys and indexes
+// as the Spanner migrations.
+func TestSQLiteSchemaInSync(t *testing.T) {
+	client, _ := newTransientSQLite(t)
+	assert.Equal(t, spannerSchema(t), sqliteSchema(t, client.(*sqliteClient)))
+}
+
+type schemaTable struct {
+	// Columns in the "Name TYPE [NOT NULL]" form with Spanner types translated to the SQLite ones.
+	Columns     []string
+	PrimaryKey  string
+	ForeignKeys []string
+	Indexes     []string
+}
+
+var (
+	spannerCreateTable = regexp.MustCompile(`^CREATE TABLE (\w+) \((.*)\) PRIMARY KEY ?\(([^)]*)\)$`)
+	spannerCreateIndex = regexp.MustCompile(`^CREATE (UNIQUE )?INDEX (\w+) ON (\w+) ?\(([^)]*)\)$`)
+	spannerAddColumn   = regexp.MustCompile(`^ALTER TABLE (\w+) ADD COLUMN (.*)$`)
+	spannerAddFK       = regexp.MustCompile(`^ALTER TABLE (\w+) ADD CONSTRAINT \w+ (FOREIGN KEY .*)$`)
+	spannerForeignKey  = regexp.MustCompile(`^FOREIGN KEY ?\(([^)]*)\) REFERENCES (\w+) ?\(([^)]*)\)$`)
+	spannerColumn      = regexp.MustCompile(`^(\w+) (ARRAY<[^>]*>|\w+)(?:\(\w+\))?( NOT NULL)?$`)
+	sqlComments        = regexp.MustCompile(`(?s)/\*.*?\*/|--[^\n]*`)
+)
+
+func spannerSchema(t *testing.T) map[string]*schemaTable {
+	files, err := fs.Glob(migrationsFs, "migrations/*.up.sql")
+	require.NoError(t, err)
+	version := func(file string) int {
+		v, err := strconv.Atoi(strings.Split(strings.TrimPrefix(file, "migrations/"), "_")[0])
+		require.NoError(t, err)
+		return v
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return version(files[i]) < version(files[j])
+	})
+	tables := make(map[string]*schemaTable)
+	table := func(name string) *schemaTable {
+		require.Contains(t, tables, name)
+		return tables[name]
+	}
+	addColumn := func(tbl *schemaTable, def string) {
+		m := spannerColumn.FindStringSubmatch(def)
+		require.NotNil(t, m, "unsupported column definition %q", def)
+		typ := map[string]string{
+			"STRING":    "TEXT",
+			"INT64":     "INTEGER",
+			"TIMESTAMP": "TEXT",
+			"BOOL":      "BOOLEAN",
+		}[m[2]]
+		if strings.HasPrefix(m[2], "ARRAY<") {
+			typ = "TEXT"
+		}
+		require.NotEmpty(t, typ, "unsupported column type %q", m[2])
+		tbl.Columns = append(tbl.Columns, m[1]+" "+typ+m[3])
+	}
+	addForeignKey := func(tbl *schemaTable, def string) {
+		m := spannerForeignKey.FindStringSubmatch(def)
+		require.NotNil(t, m, "unsupported foreign key %q", def)
+		tbl.ForeignKeys = append(tbl.ForeignKeys, formatForeignKey(splitColumns(m[1]), m[2], splitColumns(m[3])))
+	}
+	for _, file := range files {
+		data, err := migrationsFs.ReadFile(file)
+		require.NoError(t, err)
+		for _, stmt := range strings.Split(sqlComments.ReplaceAllString(string(data), ""), ";") {
+			stmt = strings.Join(strings.Fields(stmt), " ")
+			if m := spannerCreateTable.FindStringSubmatch(stmt); m != nil {
+				tbl := &schemaTable{PrimaryKey: strings.Join(splitColumns(m[3]), ", ")}
+				tables[m[1]] = tbl
+				for _, def := range splitTopLevel(m[2]) {
+					switch {
+					case def == "":
+					case strings.HasPrefix(def, "CONSTRAINT "):
+						def = strings.SplitN(def, " ", 3)[2]
+						if !strings.HasPrefix(def, "CHECK ") {
+							addForeignKey(tbl, def)
+						}
+					default:
+						addColumn(tbl, def)
+					}
+				}
+			} else if m := spannerCreateIndex.FindStringSubmatch(stmt); m != nil {
+				tbl := table(m[3])
+				tbl.Indexes = append(tbl.Indexes, formatIndex(m[2], m[1] != "", splitColumns(m[4])))
+			} else if m := spannerAddColumn.FindStringSubmatch(stmt); m != nil {
+				addColumn(table(m[1]), m[2])
+			} else if m := spannerAddFK.FindStringSubmatch(stmt); m != nil {
+				addForeignKey(table(m[1]), m[2])
+			} else if stmt != "" {
+				t.Fatalf("%v: unsupported statement %q, please update the test", file, stmt)
+			}
+		}
+	}
+	for _, tbl := range tables {
+		sort.Strings(tbl.ForeignKeys)
+		sort.Strings(tbl.Indexes)
+	}
+	return tables
+}
+
+func sqliteSchema(t *testing.T, client *sqliteClient) map[string]*schemaTable {
+	query := func(query string, args ...any) [][]string {
+		rows, err := client.db.Query(query, args...)
+		require.NoError(t, err)
+		defer rows.Close()
+		columns, err := rows.Columns()
+		require.NoError(t, err)
+		var res [][]string
+		for rows.Next() {
+			row := make([]string, len(columns))
+			ptrs := make([]any, len(columns))
+			for i := range row {
+				ptrs[i] = &row[i]
+			}
+			require.NoError(t, rows.Scan(ptrs...))
+			res = append(res, row)
+		}
+		require.NoError(t, rows.Err())
+		return res
+	}
+	tables := make(map[string]*schemaTable)
+	for _, row := range query(`SELECT name FROM sqlite_master WHERE type = 'table' AND ` +
+		`name NOT LIKE 'sqlite_%' AND name != 'schema_migrations'`) {
+		name := row[0]
+		tbl := &schemaTable{}
+		tables[name] = tbl
+		var primaryKey []string
+		for _, col := range query(`SELECT name, type, "notnull", pk FROM pragma_table_info(?) ORDER BY cid`, name) {
+			def := col[0] + " " + col[1]
+			if col[2] == "1" {
+				def += " NOT NULL"
+			}
+			tbl.Columns = append(tbl.Columns, def)
+			if col[3] != "0" {
+				pos, err := strconv.Atoi(col[3])
+				require.NoError(t, err)
+				primaryKey = append(primaryKey, make([]string, max(0, pos-len(primaryKey)))...)
+				primaryKey[pos-1] = col[0]
+			}
+		}
+		tbl.PrimaryKey = strings.Join(primaryKey, ", ")
+		foreignKeys := make(map[string][3][]string)
+		for _, fk := range query(`SELECT id, "table", "from", "to" FROM pragma_foreign_key_list(?) ORDER BY id, seq`,
+			name) {
+			key := foreignKeys[fk[0]]
+			key[0] = append(key[0], fk[2])
+			key[1] = []string{fk[1]}
+			key[2] = append(key[2], fk[3])
+			foreignKeys[fk[0]] = key
+		}
+		for _, key := range foreignKeys {
+			tbl.ForeignKeys = append(tbl.ForeignKeys, formatForeignKey(key[0], key[1][0], key[2]))
+		}
+		for _, idx := range query(`SELECT name, "unique" FROM pragma_index_list(?) WHERE origin = 'c'`, name) {
+			var columns []string
+			for _, col := range query(`SELECT name, "desc" FROM pragma_index_xinfo(?) WHERE key = 1 ORDER BY seqno`,
+				idx[0]) {
+				if col[1] == "1" {
+					col[0] += " DESC"
+				}
+				columns = append(columns, col[0])
+			}
+			tbl.Indexes = append(tbl.Indexes, formatIndex(idx[0], idx[1] == "1", columns))
+		}
+		sort.Strings(tbl.ForeignKeys)
+		sort.Strings(tbl.Indexes)
+	}
+	return tables
+}
+
+func formatForeignKey(columns []string, table string, refColumns []string) string {
+	return fmt.Sprintf("(%v) REFERENCES %v (%v)", strings.Join(columns, ", "), table, strings.Join(refColumns, ", "))
+}
+
+func formatIndex(name string, unique bool, columns []string) string {
+	res := fmt.Sprintf("%v (%v)", name, strings.Join(columns, ", "))
+	if unique {
+		res = "UNIQUE " + res
+	}
+	return res
+}
+
+func splitColumns(list string) []string {
+	var res []string
+	for _, col := range strings.Split(list, ",") {
+		res = append(res, strings.TrimSpace(col))
+	}
+	return res
+}
+
+// splitTopLevel splits the list on commas that are not enclosed in parentheses or angle brackets.
+func splitTopLevel(list string) []string {
+	var res []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '(', '<':
+			depth++
+		case ')', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(res, strings.TrimSpace(list[start:]))
+}
+
+func TestTranslateToSQLite(t *testing.T) {
+	date := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	tests := []struct {
+		stmt  spanner.Statement
+		query string
+		args  []any
+	}{
+		{
+			stmt: spanner.Statement{
+				SQL:    "SELECT 1 from `Series` WHERE `ExtID`=@extID",
+				Params: map[string]any{"ExtID": "id"},
+			},
+			query: "SELECT 1 from `Series` WHERE `ExtID`=?",
+			args:  []any{"id"},
+		},
+		{
+			stmt: spanner.Statement{
+				SQL:    "SELECT * FROM Series WHERE PublishedAt < @toTime AND @cc IN UNNEST(Cc) LIMIT @limit",
+				Params: map[string]any{"toTime": date, "cc": "a@b.com", "limit": 10},
+			},
+			query: "SELECT * FROM Series WHERE PublishedAt < ? AND ? IN (SELECT value FROM json_each(Cc)) LIMIT ?",
+			args:  []any{"2025-01-02T03:04:05.000000006Z", "a@b.com", 10},
+		},
+		{
+			stmt: spanner.Statement{
+				SQL:    "SELECT * FROM Sessions WHERE ID IN UNNEST(@ids)",
+				Params: map[string]any{"ids": []string{"a", "b"}},
+			},
+			query: "SELECT * FROM Sessions WHERE ID IN (?, ?)",
+			args:  []any{"a", "b"},
+		},
+		{
+			stmt: spanner.Statement{
+				SQL:    "SELECT * FROM Sessions WHERE ID IN UNNEST(@ids)",
+				Params: map[string]any{"ids": []string{}},
+			},
+			query: "SELECT * FROM Sessions WHERE ID IN (NULL)",
+		},
+	}
+	for _, test := range tests {
+		query, args, err := translateToSQLite(test.stmt)
+		require.NoError(t, err)
+		assert.Equal(t, test.query, query)
+		assert.Equal(t, test.args, args)
+	}
+	_, _, err := translateToSQLite(spanner.Statement{SQL: "SELECT * FROM Series WHERE ID=@id"})
+	assert.Error(t, err)
+}
+
+func TestSQLiteEntityExists(t *testing.T) {
+	client, ctx := newTransientSQLite(t)
+	repo := NewBuildRepository(client)
+	build := &Build{TreeName: "mainline", Status: BuildSuccess}
+	require.NoError(t, repo.Insert(ctx, build))
+	assert.Equal(t, errEntityExists, repo.Insert(ctx, build))
+}
@@ -8,7 +8,6 @@ import (
 	"testing"
 	"time"
 
-	"cloud.google.com/go/spanner"
 	"github.com/google/syzkaller/syz-cluster/pkg/api"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,7 +15,7 @@ import (
 type dummyTestData struct {
 	t      *testing.T
 	ctx    context.Context
-	client *spanner.Client
+	client Client
 }
 
 func (d *dummyTestData) addSessionTest(session *Session, names ...string) {
@@ -30,8 +30,8 @@ type ReportGenerator struct {
 
 func NewGenerator(env *app.AppEnvironment) *ReportGenerator {
 	return &ReportGenerator{
-		sessionRepo: db.NewSessionRepository(env.Spanner),
-		reportRepo:  db.NewReportRepository(env.Spanner),
+		sessionRepo: db.NewSessionRepository(env.DB),
+		reportRepo:  db.NewReportRepository(env.DB),
 	}
 }
 
@@ -17,7 +17,7 @@ type BuildService struct {
 
 func NewBuildService(env *app.AppEnvironment) *BuildService {
 	return &BuildService{
-		buildRepo: db.NewBuildRepository(env.Spanner),
+		buildRepo: db.NewBuildRepository(env.DB),
 	}
 }
 
@@ -25,8 +25,8 @@ type DiscussionService struct {
 
 func NewDiscussionService(env *app.AppEnvironment) *DiscussionService {
 	return &DiscussionService{
-		reportRepo:      db.NewReportRepository(env.Spanner),
-		reportReplyRepo: db.NewReportReplyRepository(env.Spanner),
+		reportRepo:      db.NewReportRepository(env.DB),
+		reportReplyRepo: db.NewReportReplyRepository(env.DB),
 	}
 }
 
@@ -22,7 +22,7 @@ type FindingService struct {
 
 func NewFindingService(env *app.AppEnvironment) *FindingService {
 	return &FindingService{
-		findingRepo: db.NewFindingRepository(env.Spanner),
+		findingRepo: db.NewFindingRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
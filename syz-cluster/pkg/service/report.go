@@ -22,7 +22,7 @@ type ReportService struct {
 
 func NewReportService(env *app.AppEnvironment) *ReportService {
 	return &ReportService{
-		reportRepo:     db.NewReportRepository(env.Spanner),
+		reportRepo:     db.NewReportRepository(env.DB),
 		seriesService:  NewSeriesService(env),
 		findingService: NewFindingService(env),
 	}
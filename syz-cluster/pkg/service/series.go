@@ -26,8 +26,8 @@ type SeriesService struct {
 
 func NewSeriesService(env *app.AppEnvironment) *SeriesService {
 	return &SeriesService{
-		sessionRepo: db.NewSessionRepository(env.Spanner),
-		seriesRepo:  db.NewSeriesRepository(env.Spanner),
+		sessionRepo: db.NewSessionRepository(env.DB),
+		seriesRepo:  db.NewSeriesRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
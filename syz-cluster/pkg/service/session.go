@@ -24,8 +24,8 @@ type SessionService struct {
 
 func NewSessionService(env *app.AppEnvironment) *SessionService {
 	return &SessionService{
-		sessionRepo: db.NewSessionRepository(env.Spanner),
-		seriesRepo:  db.NewSeriesRepository(env.Spanner),
+		sessionRepo: db.NewSessionRepository(env.DB),
+		seriesRepo:  db.NewSeriesRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
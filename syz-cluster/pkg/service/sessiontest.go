@@ -24,7 +24,7 @@ type SessionTestService struct {
 
 func NewSessionTestService(env *app.AppEnvironment) *SessionTestService {
 	return &SessionTestService{
-		testRepo:    db.NewSessionTestRepository(env.Spanner),
+		testRepo:    db.NewSessionTestRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
@@ -0,0 +1,478 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+	"github.com/google/syzkaller/syz-cluster/pkg/app"
+)
+
+// LocalService executes the same steps as the Argo workflow (see template.yaml), but as local processes.
+// It lets one run the whole system on a single machine.
+//
+// The state is only kept in memory. If the service is restarted, the unfinished workflows
+// are reported as not found and the controller starts them anew. Finished workflows
+// are forgotten once their final status has been queried.
+type LocalService struct {
+	cfg       *app.LocalWorkflowsConfig
+	limits    map[string]chan struct{}
+	mu        sync.Mutex
+	workflows map[string]*localWorkflow
+}
+
+const (
+	stepTriage = "triage"
+	stepBuild  = "build"
+	stepBoot   = "boot"
+	stepFuzz   = "fuzz"
+)
+
+var defaultStepTimeouts = map[string]time.Duration{
+	stepTriage: time.Hour,
+	stepBuild:  2 * time.Hour,
+	stepBoot:   time.Hour,
+	stepFuzz:   4 * time.Hour,
+}
+
+// The same value as in the Argo workflow template.
+const fuzzTime = "3h"
+
+func NewLocalService(cfg *app.LocalWorkflowsConfig) (*LocalService, error) {
+	if err := osutil.MkdirAll(cfg.Workdir); err != nil {
+		return nil, err
+	}
+	limits := map[string]chan struct{}{}
+	for name, step := range cfg.Steps {
+		if step.Parallelism > 0 {
+			limits[name] = make(chan struct{}, step.Parallelism)
+		}
+	}
+	return &LocalService{
+		cfg:       cfg,
+		limits:    limits,
+		workflows: map[string]*localWorkflow{},
+	}, nil
+}
+
+func (ls *LocalService) Start(sessionID string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if wf := ls.workflows[sessionID]; wf != nil && wf.getStatus() == StatusRunning {
+		return fmt.Errorf("the workflow for %q is already running", sessionID)
+	}
+	dir := filepath.Join(ls.cfg.Workdir, sessionID)
+	// There may be leftovers from a previous run.
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := osutil.MkdirAll(dir); err != nil {
+		return err
+	}
+	wf := &localWorkflow{
+		sessionID: sessionID,
+		dir:       dir,
+		status:    StatusRunning,
+	}
+	ls.workflows[sessionID] = wf
+	go ls.run(wf)
+	return nil
+}
+
+func (ls *LocalService) Status(sessionID string) (Status, []byte, error) {
+	ls.mu.Lock()
+	wf := ls.workflows[sessionID]
+	ls.mu.Unlock()
+	if wf == nil {
+		return StatusNotFound, nil, nil
+	}
+	status, log := wf.getStatus(), wf.generateLog()
+	if status != StatusRunning {
+		// The controller stops tracking the workflow once it has seen the final status
+		// and saves the log, so there's no need to keep it.
+		ls.mu.Lock()
+		if ls.workflows[sessionID] == wf {
+			delete(ls.workflows, sessionID)
+			os.RemoveAll(wf.dir)
+		}
+		ls.mu.Unlock()
+	}
+	return status, log, nil
+}
+
+func (ls *LocalService) PollPeriod() time.Duration {
+	return 10 * time.Second
+}
+
+func (ls *LocalService) run(wf *localWorkflow) {
+	status := StatusFinished
+	if err := ls.runWorkflow(wf); err != nil {
+		log.Printf("workflow for %q failed: %v", wf.sessionID, err)
+		status = StatusFailed
+	}
+	// Only keep the logs, the build artifacts and the checkouts take too much space.
+	for _, step := range wf.steps {
+		os.RemoveAll(step.dir)
+	}
+	wf.mu.Lock()
+	wf.status = status
+	wf.mu.Unlock()
+}
+
+func (ls *LocalService) runWorkflow(wf *localWorkflow) error {
+	triage := wf.newStep(stepTriage, "Triage", "triage")
+	err := ls.runStep(triage, []string{
+		"--session", wf.sessionID,
+		"--repository", triage.repository(),
+		"--verdict", triage.resultPath(),
+	})
+	if err != nil {
+		return err
+	}
+	var verdict api.TriageResult
+	if err := triage.readResult(&verdict); err != nil {
+		return err
+	}
+	if verdict.Skip != nil || verdict.Fuzz == nil {
+		return nil
+	}
+	// Similarly to the Argo workflow, we don't fail the whole workflow if the processing failed.
+	// The steps themselves report the test results.
+	if err := ls.processFuzz(wf, verdict.Fuzz); err != nil {
+		log.Printf("workflow for %q: %v", wf.sessionID, err)
+	}
+	return nil
+}
+
+func (ls *LocalService) processFuzz(wf *localWorkflow, fuzz *api.FuzzConfig) error {
+	baseBuild := wf.newStep(stepBuild, "Build Base", "build-base")
+	baseResult, err := ls.runBuild(baseBuild, &fuzz.Base, false)
+	if err != nil {
+		return err
+	}
+	patchedBuild := wf.newStep(stepBuild, "Build Patched", "build-patched")
+	patchedResult, err := ls.runBuild(patchedBuild, &fuzz.Patched, true)
+	if err != nil {
+		return err
+	}
+	wf.baseKernel = baseBuild.output()
+	wf.patchedKernel = patchedBuild.output()
+
+	bootBase := wf.newStep(stepBoot, "Boot test: Base", "boot-base")
+	bootPatched := wf.newStep(stepBoot, "Boot test: Patched", "boot-patched")
+	var baseErr, patchedErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		baseErr = ls.runBoot(bootBase, fuzz.Config, wf.baseKernel, baseResult.BuildID, "", false)
+	}()
+	go func() {
+		defer wg.Done()
+		patchedErr = ls.runBoot(bootPatched, fuzz.Config, wf.patchedKernel, "", patchedResult.BuildID, true)
+	}()
+	wg.Wait()
+	// Proceed only if both boot tests succeeded.
+	if err := errors.Join(baseErr, patchedErr); err != nil {
+		return err
+	}
+
+	fuzzStep := wf.newStep(stepFuzz, "Fuzzing", "fuzz")
+	return ls.runStep(fuzzStep, []string{
+		"--config", fuzz.Config,
+		"--session", wf.sessionID,
+		"--base_build", baseResult.BuildID,
+		"--patched_build", patchedResult.BuildID,
+		"--base_kernel", wf.baseKernel,
+		"--patched_kernel", wf.patchedKernel,
+		"--corpus_url", fuzz.CorpusURL,
+		"--time", fuzzTime,
+		"--workdir", fuzzStep.workdir(),
+		"--vv", "1",
+	})
+}
+
+func (ls *LocalService) runBuild(step *localStep, req *api.BuildRequest, findings bool) (*api.BuildResult, error) {
+	if err := osutil.MkdirAll(step.dir); err != nil {
+		return nil, err
+	}
+	requestPath := filepath.Join(step.dir, "request.json")
+	if err := osutil.WriteJSON(requestPath, req); err != nil {
+		return nil, err
+	}
+	err := ls.runStep(step, []string{
+		"--request", requestPath,
+		"--repository", step.repository(),
+		"--output", step.output(),
+		"--session", step.wf.sessionID,
+		"--test_name", step.name,
+		fmt.Sprintf("-findings=%v", findings),
+		"-smoke_build=false",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result api.BuildResult
+	if err := step.readResult(&result); err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("%v: the build failed", step.name)
+	}
+	return &result, nil
+}
+
+func (ls *LocalService) runBoot(step *localStep, config, kernel, baseBuildID, patchedBuildID string,
+	findings bool) error {
+	err := ls.runStep(step, []string{
+		"--config", config,
+		"--kernel", kernel,
+		"--workdir", step.workdir(),
+		"--output", step.resultPath(),
+		"--session", step.wf.sessionID,
+		"--test_name", step.name,
+		"--base_build", baseBuildID,
+		"--patched_build", patchedBuildID,
+		fmt.Sprintf("-findings=%v", findings),
+	})
+	if err != nil {
+		return err
+	}
+	var result api.BootResult
+	if err := step.readResult(&result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("%v: the kernel did not boot", step.name)
+	}
+	return nil
+}
+
+func (ls *LocalService) runStep(step *localStep, args []string) error {
+	if limit := ls.limits[step.kind]; limit != nil {
+		step.setPhase("Pending")
+		limit <- struct{}{}
+		defer func() { <-limit }()
+	}
+	err := ls.execStep(step, args)
+	step.finish(err)
+	if err != nil {
+		return fmt.Errorf("%v: %w", step.name, err)
+	}
+	return nil
+}
+
+func (ls *LocalService) execStep(step *localStep, args []string) error {
+	step.setPhase("Running")
+	for _, dir := range []string{step.repository(), step.output(), step.workdir()} {
+		if err := osutil.MkdirAll(dir); err != nil {
+			return err
+		}
+	}
+	if ls.cfg.KernelRepo != "" && (step.kind == stepTriage || step.kind == stepBuild) {
+		// The steps check out the necessary commits themselves.
+		_, err := osutil.RunCmd(time.Hour, "", "git", "clone", "--quiet", "--shared", "--no-checkout",
+			ls.cfg.KernelRepo, step.repository())
+		if err != nil {
+			return fmt.Errorf("failed to clone the kernel repository: %w", err)
+		}
+	}
+	cfg := ls.cfg.Steps[step.kind]
+	command := cfg.Command
+	if len(command) == 0 {
+		command = []string{step.kind + "-step"}
+	}
+	replacer := strings.NewReplacer(
+		"{{session}}", step.wf.sessionID,
+		"{{dir}}", step.dir,
+		"{{repository}}", step.repository(),
+		"{{base_kernel}}", step.wf.baseKernel,
+		"{{patched_kernel}}", step.wf.patchedKernel,
+	)
+	var argv []string
+	for _, arg := range command {
+		argv = append(argv, replacer.Replace(arg))
+	}
+	argv = append(argv, args...)
+	step.mu.Lock()
+	step.command = argv
+	step.mu.Unlock()
+
+	logFile, err := os.Create(step.logPath)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	controllerURL := ls.cfg.ControllerURL
+	if controllerURL == "" {
+		controllerURL = "http://localhost:8080"
+	}
+	cmd := osutil.Command(argv[0], argv[1:]...)
+	cmd.Dir = step.workdir()
+	cmd.Env = append(os.Environ(), "CONTROLLER_URL="+controllerURL)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultStepTimeouts[step.kind]
+	}
+	_, err = osutil.Run(timeout, cmd)
+	return err
+}
+
+type localWorkflow struct {
+	sessionID     string
+	dir           string
+	baseKernel    string
+	patchedKernel string
+
+	mu     sync.Mutex
+	status Status
+	steps  []*localStep
+}
+
+func (wf *localWorkflow) newStep(kind, name, folder string) *localStep {
+	step := &localStep{
+		wf:      wf,
+		kind:    kind,
+		name:    name,
+		dir:     filepath.Join(wf.dir, folder),
+		logPath: filepath.Join(wf.dir, folder+".log"),
+		phase:   "Pending",
+	}
+	wf.mu.Lock()
+	wf.steps = append(wf.steps, step)
+	wf.mu.Unlock()
+	return step
+}
+
+func (wf *localWorkflow) getStatus() Status {
+	wf.mu.Lock()
+	defer wf.mu.Unlock()
+	return wf.status
+}
+
+// How much of each step's output is included in the workflow log.
+const stepLogTail = 16 << 10
+
+func (wf *localWorkflow) generateLog() []byte {
+	wf.mu.Lock()
+	steps := append([]*localStep{}, wf.steps...)
+	wf.mu.Unlock()
+	var buf bytes.Buffer
+	for i, step := range steps {
+		if i > 0 {
+			buf.WriteString("---------\n")
+		}
+		step.mu.Lock()
+		fmt.Fprintf(&buf, "Name: %s\n", step.name)
+		fmt.Fprintf(&buf, "Phase: %s\n", step.phase)
+		fmt.Fprintf(&buf, "StartedAt: %s\n", formatTime(step.startedAt))
+		fmt.Fprintf(&buf, "FinishedAt: %s\n", formatTime(step.finishedAt))
+		fmt.Fprintf(&buf, "Command: %q\n", step.command)
+		if step.err != nil {
+			fmt.Fprintf(&buf, "Error: %v\n", step.err)
+		}
+		started := !step.startedAt.IsZero()
+		step.mu.Unlock()
+		if started {
+			fmt.Fprintf(&buf, "Output:\n%s\n", readTail(step.logPath, stepLogTail))
+		}
+	}
+	return buf.Bytes()
+}
+
+type localStep struct {
+	wf      *localWorkflow
+	kind    string
+	name    string
+	dir     string
+	logPath string
+
+	mu         sync.Mutex
+	phase      string
+	command    []string
+	startedAt  time.Time
+	finishedAt time.Time
+	err        error
+}
+
+func (step *localStep) repository() string {
+	return filepath.Join(step.dir, "repo")
+}
+
+func (step *localStep) output() string {
+	return filepath.Join(step.dir, "output")
+}
+
+func (step *localStep) workdir() string {
+	return filepath.Join(step.dir, "workdir")
+}
+
+func (step *localStep) resultPath() string {
+	return filepath.Join(step.output(), "result.json")
+}
+
+func (step *localStep) readResult(obj any) error {
+	data, err := os.ReadFile(step.resultPath())
+	if err != nil {
+		return fmt.Errorf("%v: failed to read the result: %w", step.name, err)
+	}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("%v: failed to parse the result: %w", step.name, err)
+	}
+	return nil
+}
+
+func (step *localStep) setPhase(phase string) {
+	step.mu.Lock()
+	defer step.mu.Unlock()
+	step.phase = phase
+	if phase == "Running" {
+		step.startedAt = time.Now()
+	}
+}
+
+func (step *localStep) finish(err error) {
+	step.mu.Lock()
+	defer step.mu.Unlock()
+	step.finishedAt = time.Now()
+	step.err = err
+	step.phase = "Succeeded"
+	if err != nil {
+		step.phase = "Failed"
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func readTail(path string, size int64) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	if stat, err := f.Stat(); err == nil && stat.Size() > size {
+		f.Seek(stat.Size()-size, io.SeekStart)
+	}
+	data, _ := io.ReadAll(f)
+	return data
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+	"github.com/google/syzkaller/syz-cluster/pkg/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The test binary also plays the role of the workflow step binaries.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == "fake-step" {
+		if err := fakeStep(os.Args[2], os.Args[3:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func fakeStep(kind string, args []string) error {
+	flags := flag.NewFlagSet(kind, flag.ContinueOnError)
+	var (
+		verdict       = flags.String("verdict", "", "")
+		output        = flags.String("output", "", "")
+		testName      = flags.String("test_name", "", "")
+		findings      = flags.Bool("findings", false, "")
+		patched       = flags.String("patched_build", "", "")
+		sessionID     = flags.String("session", "", "")
+		kernel        = flags.String("kernel", "", "")
+		baseKernel    = flags.String("base_kernel", "", "")
+		patchedKernel = flags.String("patched_kernel", "", "")
+	)
+	for _, name := range []string{"repository", "request", "config", "base_build",
+		"corpus_url", "time", "workdir", "vv"} {
+		flags.String(name, "", "")
+	}
+	flags.Bool("smoke_build", false, "")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	fmt.Printf("running %s for %s\n", kind, *sessionID)
+	switch kind {
+	case stepTriage:
+		result := &api.TriageResult{Fuzz: &api.FuzzConfig{Config: "all"}}
+		if *sessionID == "skip" {
+			result = &api.TriageResult{Skip: &api.SkipRequest{Reason: "skipped"}}
+		}
+		return osutil.WriteJSON(*verdict, result)
+	case stepBuild:
+		return osutil.WriteJSON(filepath.Join(*output, "result.json"), &api.BuildResult{
+			BuildID: *testName,
+			Success: true,
+		})
+	case stepBoot:
+		if *findings != (*patched != "") {
+			return fmt.Errorf("unexpected arguments")
+		}
+		if err := checkBuildOutput(*kernel); err != nil {
+			return err
+		}
+		return osutil.WriteJSON(*output, &api.BootResult{Success: true})
+	case stepFuzz:
+		return errors.Join(checkBuildOutput(*baseKernel), checkBuildOutput(*patchedKernel))
+	}
+	return fmt.Errorf("unknown step %q", kind)
+}
+
+func checkBuildOutput(dir string) error {
+	if !osutil.IsExist(filepath.Join(dir, "result.json")) {
+		return fmt.Errorf("%q is not a build output", dir)
+	}
+	return nil
+}
+
+func TestLocalService(t *testing.T) {
+	cfg := &app.LocalWorkflowsConfig{
+		Workdir: t.TempDir(),
+		Steps:   map[string]app.LocalStepConfig{},
+	}
+	for _, kind := range []string{stepTriage, stepBuild, stepBoot, stepFuzz} {
+		cfg.Steps[kind] = app.LocalStepConfig{
+			Command:     []string{os.Args[0], "fake-step", kind},
+			Parallelism: 1,
+		}
+	}
+	service, err := NewLocalService(cfg)
+	require.NoError(t, err)
+
+	status, _, err := service.Status("session")
+	require.NoError(t, err)
+	assert.Equal(t, StatusNotFound, status)
+
+	ids := []string{"session", "skip"}
+	logs := make([][]byte, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		require.NoError(t, service.Start(id))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				status, log, err := service.Status(id)
+				if !assert.NoError(t, err) {
+					return
+				}
+				if status != StatusRunning {
+					assert.Equal(t, StatusFinished, status)
+					logs[i] = log
+					return
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, name := range []string{"Triage", "Build Base", "Build Patched", "Boot test: Base",
+		"Boot test: Patched", "Fuzzing"} {
+		assert.Contains(t, string(logs[0]), "Name: "+name+"\nPhase: Succeeded\n")
+	}
+	assert.Contains(t, string(logs[0]), "running fuzz for session")
+	assert.Contains(t, string(logs[1]), "Name: Triage\nPhase: Succeeded\n")
+	assert.NotContains(t, string(logs[1]), "Build Base")
+
+	// The finished workflows are forgotten once their final status has been queried.
+	for _, id := range ids {
+		status, _, err := service.Status(id)
+		require.NoError(t, err)
+		assert.Equal(t, StatusNotFound, status)
+		assert.NoDirExists(t, filepath.Join(cfg.Workdir, id))
+	}
+}
+
+func TestLocalServiceFailure(t *testing.T) {
+	cfg := &app.LocalWorkflowsConfig{
+		Workdir: t.TempDir(),
+		Steps: map[string]app.LocalStepConfig{
+			stepTriage: {Command: []string{os.Args[0], "fake-step", "unknown"}},
+		},
+	}
+	service, err := NewLocalService(cfg)
+	require.NoError(t, err)
+	require.NoError(t, service.Start("session"))
+	var log []byte
+	for {
+		var status Status
+		status, log, err = service.Status("session")
+		require.NoError(t, err)
+		if status != StatusRunning {
+			assert.Equal(t, StatusFailed, status)
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Contains(t, string(log), "Phase: Failed")
+	assert.Contains(t, string(log), `unknown step "unknown"`)
+}
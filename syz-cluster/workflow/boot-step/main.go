@@ -5,11 +5,13 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"path/filepath"
 
+	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/instance"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -26,6 +28,10 @@ var (
 	flagPatchedBuild = flag.String("patched_build", "", "patched build ID")
 	flagOutput       = flag.String("output", "", "where to store the result")
 	flagFindings     = flag.Bool("findings", false, "report failur as findings")
+	flagConfigs      = flag.String("configs", "/configs", "the folder with syzkaller configs")
+	flagKernel       = flag.String("kernel", "", "kernel build folder (overrides the config)")
+	flagSyzkaller    = flag.String("syzkaller", "", "syzkaller folder (overrides the config)")
+	flagWorkdir      = flag.String("workdir", "/tmp/test-workdir", "syzkaller workdir path")
 )
 
 func main() {
@@ -77,11 +83,10 @@ func main() {
 const retryCount = 3
 
 func runTest(ctx context.Context, client *api.Client) (bool, error) {
-	cfg, err := mgrconfig.LoadFile(filepath.Join("/configs", *flagConfig, "base.cfg"))
+	cfg, err := loadConfig()
 	if err != nil {
 		return false, err
 	}
-	cfg.Workdir = "/tmp/test-workdir"
 
 	var rep *report.Report
 	for i := 0; i < retryCount; i++ {
@@ -110,3 +115,18 @@ func runTest(ctx context.Context, client *api.Client) (bool, error) {
 	}
 	return false, nil
 }
+
+func loadConfig() (*mgrconfig.Config, error) {
+	var raw json.RawMessage
+	err := config.LoadFile(filepath.Join(*flagConfigs, *flagConfig, "base.cfg"), &raw)
+	if err != nil {
+		return nil, err
+	}
+	patch := app.ManagerConfigPatch(*flagKernel, *flagSyzkaller)
+	patch["workdir"] = *flagWorkdir
+	data, err := config.PatchJSON(raw, patch)
+	if err != nil {
+		return nil, err
+	}
+	return mgrconfig.LoadData(data)
+}
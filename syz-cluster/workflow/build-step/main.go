@@ -32,6 +32,9 @@ var (
 	flagSession    = flag.String("session", "", "session ID")
 	flagFindings   = flag.Bool("findings", false, "report build failures as findings")
 	flagSmokeBuild = flag.Bool("smoke_build", false, "build only if new, don't report findings")
+	// See the Dockerfile.
+	flagKernelConfigs = flag.String("kernel_configs", "/kernel-configs", "the folder with kernel configs")
+	flagUserspace     = flag.String("userspace", "/disk-images/buildroot_amd64_2024.09", "the userspace image")
 )
 
 func main() {
@@ -198,7 +201,7 @@ func checkoutKernel(tracer debugtracer.DebugTracer, req *api.BuildRequest, serie
 }
 
 func buildKernel(tracer debugtracer.DebugTracer, req *api.BuildRequest) (*api.NewFinding, error) {
-	kernelConfig, err := os.ReadFile(filepath.Join("/kernel-configs", req.ConfigName))
+	kernelConfig, err := os.ReadFile(filepath.Join(*flagKernelConfigs, req.ConfigName))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read the kernel config: %w", err)
 	}
@@ -214,7 +217,7 @@ func buildKernel(tracer debugtracer.DebugTracer, req *api.BuildRequest) (*api.Ne
 		OutputDir:    *flagOutput,
 		Compiler:     "clang",
 		Linker:       "ld.lld",
-		UserspaceDir: "/disk-images/buildroot_amd64_2024.09", // See the Dockerfile.
+		UserspaceDir: *flagUserspace,
 		Config:       kernelConfig,
 		Tracer:       tracer,
 	}
@@ -28,13 +28,17 @@ import (
 )
 
 var (
-	flagConfig       = flag.String("config", "", "syzkaller config")
-	flagSession      = flag.String("session", "", "session ID")
-	flagBaseBuild    = flag.String("base_build", "", "base build ID")
-	flagPatchedBuild = flag.String("patched_build", "", "patched build ID")
-	flagTime         = flag.String("time", "1h", "how long to fuzz")
-	flagWorkdir      = flag.String("workdir", "/workdir", "base workdir path")
-	flagCorpusURL    = flag.String("corpus_url", "", "an URL to download corpus from")
+	flagConfig        = flag.String("config", "", "syzkaller config")
+	flagSession       = flag.String("session", "", "session ID")
+	flagBaseBuild     = flag.String("base_build", "", "base build ID")
+	flagPatchedBuild  = flag.String("patched_build", "", "patched build ID")
+	flagTime          = flag.String("time", "1h", "how long to fuzz")
+	flagWorkdir       = flag.String("workdir", "/workdir", "base workdir path")
+	flagCorpusURL     = flag.String("corpus_url", "", "an URL to download corpus from")
+	flagConfigs       = flag.String("configs", "/configs", "the folder with syzkaller configs")
+	flagBaseKernel    = flag.String("base_kernel", "", "base kernel build folder (overrides the config)")
+	flagPatchedKernel = flag.String("patched_kernel", "", "patched kernel build folder (overrides the config)")
+	flagSyzkaller     = flag.String("syzkaller", "", "syzkaller folder (overrides the config)")
 )
 
 const testName = "Fuzzing"
@@ -90,7 +94,7 @@ func run(baseCtx context.Context, client *api.Client, timeout time.Duration,
 	const MB = 1000000
 	log.EnableLogCaching(100000, 10*MB)
 
-	base, patched, err := loadConfigs("/configs", *flagConfig, true)
+	base, patched, err := loadConfigs(*flagConfigs, *flagConfig, true)
 	if err != nil {
 		return fmt.Errorf("failed to load configs: %w", err)
 	}
@@ -200,6 +204,14 @@ func loadConfigs(configFolder, configName string, complete bool) (*mgrconfig.Con
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to merge the configs: %w", err)
 	}
+	baseRaw, err = config.PatchJSON(baseRaw, app.ManagerConfigPatch(*flagBaseKernel, *flagSyzkaller))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to patch the base config: %w", err)
+	}
+	patchedRaw, err = config.PatchJSON(patchedRaw, app.ManagerConfigPatch(*flagPatchedKernel, *flagSyzkaller))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to patch the patched config: %w", err)
+	}
 	base, err := mgrconfig.LoadPartialData(baseRaw)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse the base config: %w", err)
@@ -6,6 +6,7 @@ package main
 import (
 	"io/fs"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +27,31 @@ func TestConfigLoad(t *testing.T) {
 		return nil
 	})
 }
+
+func TestConfigOverride(t *testing.T) {
+	*flagBaseKernel, *flagPatchedKernel, *flagSyzkaller = "/tmp/base", "/tmp/patched", "/tmp/syzkaller"
+	defer func() {
+		*flagBaseKernel, *flagPatchedKernel, *flagSyzkaller = "", "", ""
+	}()
+	base, patched, err := loadConfigs(filepath.Join("..", "configs"), "all", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		got, want string
+	}{
+		{base.KernelObj, "/tmp/base/obj"},
+		{base.Image, "/tmp/base/image"},
+		{base.Syzkaller, "/tmp/syzkaller"},
+		{patched.KernelObj, "/tmp/patched/obj"},
+		{patched.Image, "/tmp/patched/image"},
+		{patched.Syzkaller, "/tmp/syzkaller"},
+	} {
+		if test.got != test.want {
+			t.Errorf("got %q, want %q", test.got, test.want)
+		}
+	}
+	if !strings.Contains(string(patched.VM), `"kernel":"/tmp/patched/kernel"`) {
+		t.Errorf("the patched kernel is not overridden: %s", patched.VM)
+	}
+}
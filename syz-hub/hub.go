@@ -36,6 +36,7 @@ type Hub struct {
 	st   *state.State
 	keys map[string]string
 	auth auth.Endpoint
+	Stats
 }
 
 func main() {
@@ -59,6 +60,7 @@ func main() {
 		hub.keys[mgr.Name] = mgr.Key
 	}
 
+	hub.initStats()
 	hub.initHTTP(cfg.HTTP)
 	go hub.purgeOldManagers()
 
@@ -122,6 +124,12 @@ func (hub *Hub) Sync(a *rpctype.HubSyncArgs, r *rpctype.HubSyncRes) error {
 			r.Repros = [][]byte{repro}
 		}
 	}
+	hub.statSyncs.With(name).Add(1)
+	hub.statAdded.With(name).Add(len(a.Add))
+	hub.statDeleted.With(name).Add(len(a.Del))
+	hub.statSentProgs.With(name).Add(len(inputs))
+	hub.statRecvRepros.With(name).Add(len(a.Repros))
+	hub.statSentRepros.With(name).Add(len(r.Repros))
 	log.Logf(0, "sync from %v: recv: add=%v del=%v repros=%v; send: progs=%v repros=%v pending=%v",
 		name, len(a.Add), len(a.Del), len(a.Repros), len(inputs), len(r.Repros), more)
 	return nil
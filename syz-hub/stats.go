@@ -0,0 +1,44 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/google/syzkaller/pkg/stat"
+)
+
+type Stats struct {
+	statSyncs      *stat.Family
+	statAdded      *stat.Family
+	statDeleted    *stat.Family
+	statSentProgs  *stat.Family
+	statRecvRepros *stat.Family
+	statSentRepros *stat.Family
+}
+
+func (hub *Hub) initStats() {
+	stat.New("hub corpus", "Number of programs in the hub corpus", func() int {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.st.Corpus.Records)
+	})
+	stat.New("hub repros", "Number of reproducers in the hub", func() int {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.st.Repros.Records)
+	})
+	hub.Stats = Stats{
+		statSyncs: stat.NewFamily("hub syncs", "Number of syncs with the manager",
+			[]string{"manager"}, stat.Rate{}),
+		statAdded: stat.NewFamily("hub added", "Programs added by the manager",
+			[]string{"manager"}, stat.Rate{}),
+		statDeleted: stat.NewFamily("hub deleted", "Programs deleted by the manager",
+			[]string{"manager"}, stat.Rate{}),
+		statSentProgs: stat.NewFamily("hub sent progs", "Programs sent to the manager",
+			[]string{"manager"}, stat.Rate{}),
+		statRecvRepros: stat.NewFamily("hub recv repros", "Reproducers received from the manager",
+			[]string{"manager"}, stat.Rate{}),
+		statSentRepros: stat.NewFamily("hub sent repros", "Reproducers sent to the manager",
+			[]string{"manager"}, stat.Rate{}),
+	}
+}
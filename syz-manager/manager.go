@@ -80,6 +80,7 @@ type Manager struct {
 	reportGenerator *manager.ReportGeneratorWrapper
 	fresh           bool
 	coverFilters    manager.CoverageFilters
+	autoFocus       *manager.AutoFocus
 
 	dash *dashapi.Dashboard
 	// This is specifically separated from dash, so that we can keep dash = nil when
@@ -143,7 +144,9 @@ var (
 	ModeCorpusTriage = &Mode{
 		Name: "corpus-triage",
 		Description: `triage corpus and exit
-	This is useful mostly for benchmarking with testbed.`,
+	This is useful mostly for benchmarking with testbed.
+	If coverage is enabled, the corpus coverage is saved to workdir/rawcover
+	and the kernel module layout to workdir/modules.json (see syz-cover -diff-base).`,
 		LoadCorpus: true,
 	}
 	ModeCorpusRun = &Mode{
@@ -261,7 +264,6 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
-
 	mgr := &Manager{
 		cfg:                cfg,
 		mode:               mode,
@@ -284,6 +286,12 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 	if *flagDebug {
 		mgr.cfg.Procs = 1
 	}
+	if cfg.Experimental.AutoFocus {
+		mgr.autoFocus, err = manager.NewAutoFocus(cfg)
+		if err != nil {
+			log.Fatalf("failed to init auto focus: %v", err)
+		}
+	}
 	mgr.http = &manager.HTTPServer{
 		// Note that if cfg.HTTP == "", we don't start the server.
 		Cfg:        cfg,
@@ -292,6 +300,7 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 	}
 
 	mgr.initStats()
+	mgr.persistStatsHistory()
 	if mgr.mode.LoadCorpus {
 		go mgr.preloadCorpus()
 	} else {
@@ -677,12 +686,17 @@ func (mgr *Manager) runInstanceInner(ctx context.Context, inst *vm.Instance, inj
 }
 
 func (mgr *Manager) emailCrash(crash *manager.Crash) {
-	if len(mgr.cfg.EmailAddrs) == 0 {
+	emails := mgr.cfg.EmailAddrs
+	if len(crash.EmailAddrs) != 0 {
+		// Triage rules route the crash to the specific list.
+		emails = crash.EmailAddrs
+	}
+	if len(emails) == 0 {
 		return
 	}
 	args := []string{"-s", "syzkaller: " + crash.Title}
-	args = append(args, mgr.cfg.EmailAddrs...)
-	log.Logf(0, "sending email to %v", mgr.cfg.EmailAddrs)
+	args = append(args, emails...)
+	log.Logf(0, "sending email to %v", emails)
 
 	cmd := exec.Command("mailx", args...)
 	cmd.Stdin = bytes.NewReader(crash.Report.Report)
@@ -730,6 +744,11 @@ func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
 	}
 
 	mgr.statCrashes.Add(1)
+	if mgr.subsystems != nil && crash.GuiltyFile != "" {
+		for _, s := range mgr.subsystems.Match(crash.GuiltyFile) {
+			mgr.statSubsysCrashes.With(s.Name).Add(1)
+		}
+	}
 	mgr.mu.Lock()
 	if !mgr.crashTypes[crash.Title] {
 		mgr.crashTypes[crash.Title] = true
@@ -748,6 +767,7 @@ func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
 			Corrupted:   crash.Corrupted,
 			Suppressed:  crash.Suppressed,
 			Recipients:  crash.Recipients.ToDash(),
+			Priority:    crash.Priority,
 			Log:         crash.Output,
 			Report:      crash.Report.Report,
 			MachineInfo: crash.MachineInfo,
@@ -759,7 +779,7 @@ func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
 		}
 		// Don't store the crash locally even if we failed to upload it.
 		// There is 0 chance that one will ever look in the crashes/ folder of those instances.
-		return mgr.cfg.Reproduce && resp.NeedRepro
+		return mgr.cfg.Reproduce && resp.NeedRepro && !crash.SkipRepro
 	}
 	first, err := mgr.crashStore.SaveCrash(crash)
 	if err != nil {
@@ -789,6 +809,12 @@ func (mgr *Manager) NeedRepro(crash *manager.Crash) bool {
 	if crash.FromHub || crash.FromDashboard {
 		return true
 	}
+	if crash.SkipRepro {
+		return false
+	}
+	if crash.ImpactScore() < mgr.cfg.ReproMinImpactScore {
+		return false
+	}
 	mgr.mu.Lock()
 	phase, features := mgr.phase, mgr.enabledFeatures
 	mgr.mu.Unlock()
@@ -899,6 +925,7 @@ func (mgr *Manager) saveRepro(res *manager.ReproResult) {
 			AltTitles:     report.AltTitles,
 			Suppressed:    report.Suppressed,
 			Recipients:    report.Recipients.ToDash(),
+			Priority:      report.Priority,
 			Log:           output,
 			Flags:         crashFlags,
 			Report:        report.Report,
@@ -1163,6 +1190,9 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 		go mgr.corpusInputHandler(corpusUpdates)
 		go mgr.corpusMinimization()
 		go mgr.fuzzerLoop(fuzzerObj)
+		if mgr.autoFocus != nil {
+			go mgr.autoFocusLoop()
+		}
 		if mgr.dash != nil {
 			go mgr.dashboardReporter()
 			if mgr.cfg.Reproduce {
@@ -1261,6 +1291,25 @@ func (mgr *Manager) corpusMinimization() {
 	}
 }
 
+func (mgr *Manager) autoFocusLoop() {
+	for range time.NewTicker(30 * time.Minute).C {
+		mgr.mu.Lock()
+		phase := mgr.phase
+		mgr.mu.Unlock()
+		// Coverage of a partially triaged corpus does not tell much about the subsystems.
+		if phase < phaseTriagedCorpus {
+			continue
+		}
+		rg, err := mgr.reportGenerator.Get()
+		if err != nil {
+			log.Logf(0, "auto focus: failed to get report generator: %v", err)
+			continue
+		}
+		areas := mgr.autoFocus.Update(rg, mgr.corpus)
+		mgr.http.AutoFocus.Store(areas)
+	}
+}
+
 func (mgr *Manager) MaxSignal() signal.Signal {
 	if fuzzer := mgr.fuzzer.Load(); fuzzer != nil {
 		return fuzzer.Cover.CopyMaxSignal()
@@ -1284,6 +1333,9 @@ func (mgr *Manager) fuzzerLoop(fuzzer *fuzzer.Fuzzer) {
 		// Update the state machine.
 		if fuzzer.CandidateTriageFinished() {
 			if mgr.mode == ModeCorpusTriage {
+				if err := mgr.saveCorpusCover(); err != nil {
+					log.Fatalf("failed to save corpus coverage: %v", err)
+				}
 				mgr.exit("corpus triage")
 			}
 			mgr.mu.Lock()
@@ -1445,6 +1497,34 @@ func (mgr *Manager) dashboardReproTasks() {
 	}
 }
 
+// saveCorpusCover saves the corpus coverage in the format of the /rawcover page
+// and the module layout it corresponds to in the format of the /modules page.
+func (mgr *Manager) saveCorpusCover() error {
+	info := mgr.http.Cover.Load()
+	if !mgr.cfg.Cover || info == nil {
+		return nil
+	}
+	uniquePCs := make(map[uint64]bool)
+	for _, inp := range mgr.corpus.Items() {
+		for _, pc := range manager.CoverToPCs(mgr.cfg, inp.Cover) {
+			uniquePCs[pc] = true
+		}
+	}
+	var pcs []uint64
+	for pc := range uniquePCs {
+		pcs = append(pcs, pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	buf := new(bytes.Buffer)
+	for _, pc := range pcs {
+		fmt.Fprintf(buf, "0x%x\n", pc)
+	}
+	if err := osutil.WriteFile(filepath.Join(mgr.cfg.Workdir, "rawcover"), buf.Bytes()); err != nil {
+		return err
+	}
+	return osutil.WriteJSON(filepath.Join(mgr.cfg.Workdir, "modules.json"), info.Modules)
+}
+
 func (mgr *Manager) CoverageFilter(modules []*vminfo.KernelModule) ([]uint64, error) {
 	mgr.reportGenerator.Init(modules)
 	filters, err := manager.PrepareCoverageFilters(mgr.reportGenerator, mgr.cfg, true)
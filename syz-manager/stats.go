@@ -4,16 +4,23 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
 	"runtime"
 	"time"
 
 	"github.com/google/syzkaller/pkg/image"
+	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/pkg/subsystem"
 )
 
 type Stats struct {
 	statCrashes       *stat.Val
+	statSubsysCrashes *stat.Family
+	subsystems        *subsystem.PathMatcher
 	statCrashTypes    *stat.Val
 	statSuppressed    *stat.Val
 	statUptime        *stat.Val
@@ -25,6 +32,12 @@ type Stats struct {
 func (mgr *Manager) initStats() {
 	mgr.statCrashes = stat.New("crashes", "Total number of VM crashes",
 		stat.Simple, stat.Prometheus("syz_crash_total"))
+	mgr.statSubsysCrashes = stat.NewFamily("subsystem crashes",
+		"Total number of VM crashes per kernel subsystem of the guilty file", []string{"subsystem"},
+		stat.Rate{}, stat.Prometheus("syz_subsystem_crash_total"))
+	if list := subsystem.GetList(mgr.cfg.TargetOS); len(list) != 0 {
+		mgr.subsystems = subsystem.MakePathMatcher(list)
+	}
 	mgr.statCrashTypes = stat.New("crash types", "Number of unique crashes types",
 		stat.Simple, stat.NoGraph)
 	mgr.statSuppressed = stat.New("suppressed", "Total number of suppressed VM crashes",
@@ -78,3 +91,23 @@ func (mgr *Manager) initStats() {
 		})
 	mgr.statCoverFiltered = stat.New("filtered coverage", "", stat.NoGraph)
 }
+
+// persistStatsHistory restores graphs history from the previous manager runs and periodically saves it.
+// This is done only in the fuzzing mode, other modes are short-lived.
+func (mgr *Manager) persistStatsHistory() {
+	if mgr.cfg.StatsRetention == 0 || mgr.mode != ModeFuzzing {
+		return
+	}
+	file := filepath.Join(mgr.cfg.Workdir, "stats-history.json")
+	retention := time.Duration(mgr.cfg.StatsRetention) * time.Hour
+	if err := stat.LoadHistory(file, retention); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Logf(0, "failed to load stats history: %v", err)
+	}
+	go func() {
+		for range time.NewTicker(time.Minute).C {
+			if err := stat.SaveHistory(file); err != nil {
+				log.Logf(0, "failed to save stats history: %v", err)
+			}
+		}
+	}()
+}
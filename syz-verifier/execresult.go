@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"syscall"
 
+	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/ipc"
+	"github.com/google/syzkaller/pkg/verifier"
 	"github.com/google/syzkaller/prog"
 )
 
@@ -55,6 +57,27 @@ func (l *ExecResult) IsEqual(r *ExecResult) bool {
 	return true
 }
 
+// comparerResults converts the results for verifier.Comparer.
+// TODO: the runner does not request flatrpc.ExecFlagCollectCopyout yet,
+// so the output buffers are compared only once syz-verifier is switched to pkg/rpcserver.
+func comparerResults(res []*ExecResult) []*verifier.Result {
+	var converted []*verifier.Result
+	for _, r := range res {
+		info := new(flatrpc.ProgInfo)
+		for _, ci := range r.Info.Calls {
+			info.Calls = append(info.Calls, &flatrpc.CallInfo{
+				Flags: flatrpc.CallFlag(ci.Flags),
+				Error: int32(ci.Errno),
+			})
+		}
+		converted = append(converted, &verifier.Result{
+			Info:    info,
+			Crashed: r.Crashed,
+		})
+	}
+	return converted
+}
+
 type ResultReport struct {
 	// Prog is the serialized program.
 	Prog string
@@ -15,12 +15,14 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/pkg/verifier"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/vm"
 )
@@ -51,6 +53,10 @@ func main() {
 	flagEnv := flag.Bool("new-env", true, "create a new environment for each program")
 	flagAddress := flag.String("address", "127.0.0.1:8080", "http address for monitoring")
 	flagReruns := flag.Int("rerun", 3, "number of time program is rerun when a mismatch is found")
+	flagOutputCalls := flag.String("output-calls", "", "comma-separated list of syscalls "+
+		"whose output buffers (copied out values) are compared in addition to errnos")
+	flagRules := flag.String("rules", "", "file with known benign differences (see verifier.CompareRules)")
+	flagMinimize := flag.Bool("minimize", true, "minimize a reproducer for each new mismatch class")
 	flag.Parse()
 
 	pools := make(map[int]*poolInfo)
@@ -138,6 +144,18 @@ func main() {
 		}
 	}
 
+	var rules *verifier.CompareRules
+	if *flagRules != "" {
+		rules, err = verifier.LoadCompareRules(*flagRules)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+	var outputCalls []string
+	if *flagOutputCalls != "" {
+		outputCalls = strings.Split(*flagOutputCalls, ",")
+	}
+
 	calls := make(map[*prog.Syscall]bool)
 
 	for _, id := range cfg.Syscalls {
@@ -161,7 +179,13 @@ func main() {
 		statsWrite:    sw,
 		newEnv:        *flagEnv,
 		reruns:        *flagReruns,
+		comparer:      verifier.NewComparer(outputCalls, rules),
+	}
+	var run verifier.RunFunc
+	if *flagMinimize {
+		run = vrf.runMismatch
 	}
+	vrf.buckets = verifier.NewBuckets(vrf.comparer, filepath.Join(resultsdir, "buckets"), run, *flagReruns)
 
 	vrf.Init()
 
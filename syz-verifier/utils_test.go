@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/syzkaller/pkg/ipc"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/verifier"
 	"github.com/google/syzkaller/prog"
 )
 
@@ -25,6 +26,7 @@ func createTestVerifier(t *testing.T) *Verifier {
 		choiceTable: target.DefaultChoiceTable(),
 		progIdx:     3,
 		reruns:      1,
+		comparer:    verifier.NewComparer(nil, nil),
 	}
 	vrf.resultsdir = makeTestResultDirectory(t)
 	vrf.stats = emptyTestStats()
@@ -23,6 +23,7 @@ import (
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/rpctype"
+	"github.com/google/syzkaller/pkg/verifier"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/vm"
 )
@@ -55,6 +56,9 @@ type Verifier struct {
 	statsWrite        io.Writer
 	newEnv            bool
 	reruns            int
+	comparer          *verifier.Comparer
+	// Mismatch classes with their reproducers saved to <resultsdir>/buckets/<class hash>/.
+	buckets *verifier.Buckets
 
 	// We use single queue for every kernel environment.
 	tasksMutex     sync.Mutex
@@ -99,6 +103,7 @@ func (vrf *Verifier) StartProgramsAnalysis() {
 			for result := range results {
 				if result.Diff != nil {
 					vrf.SaveDiffResults(result.Diff, result.Prog)
+					vrf.BucketMismatches(result.Diff, result.Prog)
 				}
 			}
 		}()
@@ -153,7 +158,7 @@ func (vrf *Verifier) TestProgram(prog *prog.Prog) (result []*ExecResult) {
 			return
 		}
 		vrf.AddCallsExecutionStat(stepRes, prog)
-		if stepRes[0].IsEqual(stepRes[1]) {
+		if len(vrf.comparer.Mismatches(comparerResults(stepRes), prog)) == 0 {
 			if i != 0 {
 				vrf.stats.FlakyProgs.Inc()
 			}
@@ -364,6 +369,26 @@ func (vrf *Verifier) SaveDiffResults(results []*ExecResult, program *prog.Prog)
 	return true
 }
 
+// BucketMismatches adds the mismatches found in the program results to the buckets.
+func (vrf *Verifier) BucketMismatches(results []*ExecResult, program *prog.Prog) {
+	classes, err := vrf.buckets.Add(comparerResults(results), program)
+	if err != nil {
+		log.Logf(0, "failed to save mismatch bucket: %v", err)
+	}
+	for _, class := range classes {
+		log.Logf(0, "new mismatch class: %v", class)
+	}
+}
+
+// runMismatch runs the program for minimization of mismatch reproducers.
+func (vrf *Verifier) runMismatch(p *prog.Prog) ([]*verifier.Result, error) {
+	res, err := vrf.Run(p, NewEnvironment)
+	if err != nil {
+		return nil, err
+	}
+	return comparerResults(res), nil
+}
+
 // generate returns a newly generated program or error.
 func (vrf *Verifier) generate() *prog.Prog {
 	vrf.progGeneratorInit.Wait()
@@ -10,12 +10,15 @@
 // If -fix flag is specified, it does fix bisection. Otherwise it does cause bisection. Also
 // wanted syzkaller and kernel commits can be specified using -syzkaller_commit and
 // -kernel_commit. HEAD is used if commits are not specified.
+// If -configs flag is specified, it does config bisection on the kernel commit instead:
+// it finds the config options that are enabled in kernel_config, but not in kernel_baseline_config,
+// and that trigger the crash.
 //
 // The crash dir should contain the following files:
 //   - repro.cprog or repro.prog: reproducer for the crash
 //   - repro.opts: syzkaller reproducer options (e.g. {"procs":1,"sandbox":"none",...}) (optional)
 //
-// The tool stores bisection result into cause.commit or fix.commit.
+// The tool stores bisection result into cause.commit or fix.commit (cause.configs for config bisection).
 package main
 
 import (
@@ -37,6 +40,7 @@ var (
 	flagConfig            = flag.String("config", "", "bisect config file")
 	flagCrash             = flag.String("crash", "", "dir with crash info")
 	flagFix               = flag.Bool("fix", false, "search for crash fix")
+	flagConfigs           = flag.Bool("configs", false, "search for kernel configs that trigger the crash")
 	flagKernelCommit      = flag.String("kernel_commit", "", "original kernel commit")
 	flagKernelCommitTitle = flag.String("kernel_commit_title", "", "original kernel commit title")
 	flagSyzkallerCommit   = flag.String("syzkaller_commit", "", "original syzkaller commit")
@@ -67,6 +71,8 @@ type Config struct {
 	Cmdline   string               `json:"cmdline"`
 	CrossTree bool                 `json:"cross_tree"`
 	Backports []vcs.BackportCommit `json:"backports"`
+	// Number of commits to build and test in parallel (see bisect.Config.Parallel).
+	Parallel int `json:"parallel"`
 
 	KernelConfig         string `json:"kernel_config"`
 	KernelBaselineConfig string `json:"kernel_baseline_config"`
@@ -109,6 +115,8 @@ func main() {
 		BinDir:          mycfg.BinDir,
 		Ccache:          mycfg.Ccache,
 		CrossTree:       mycfg.CrossTree,
+		Parallel:        mycfg.Parallel,
+		ConfigBisect:    *flagConfigs,
 		Kernel: bisect.KernelConfig{
 			Repo:        mycfg.KernelRepo,
 			Branch:      mycfg.KernelBranch,
@@ -135,6 +143,10 @@ func main() {
 		fmt.Fprintf(os.Stderr, "no repro.cprog or repro.prog found\n")
 		os.Exit(1)
 	}
+	if *flagConfigs && (*flagFix || len(cfg.Kernel.BaselineConfig) == 0) {
+		fmt.Fprintf(os.Stderr, "-configs requires kernel_baseline_config and can't be used with -fix\n")
+		os.Exit(1)
+	}
 
 	if cfg.Syzkaller.Commit == "" {
 		cfg.Syzkaller.Commit = vcs.HEAD
@@ -149,6 +161,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *flagConfigs {
+		saveResultConfigs(result.Configs)
+		return
+	}
 	saveResultCommits(result.Commits)
 }
 
@@ -165,6 +181,17 @@ func loadFile(path, file string, dst *[]byte, mandatory bool) {
 	*dst = data
 }
 
+func saveResultConfigs(configs []string) {
+	var result string
+	for _, cfg := range configs {
+		result += "CONFIG_" + cfg + "\n"
+	}
+	if result == "" {
+		result = "the crash is triggered by non-tristate config values\n"
+	}
+	osutil.WriteFile(filepath.Join(*flagCrash, "cause.configs"), []byte(result))
+}
+
 func saveResultCommits(commits []*vcs.Commit) {
 	var result string
 	if len(commits) > 0 {
@@ -18,6 +18,18 @@
 // or use all pcs in rg.Symbols
 //
 //	syz-cover -config config_file
+//
+// To see what one coverage covers that another one does not, pass the base coverage with -diff-base:
+//
+//	syz-cover -config config_file -diff-base base.rawcover new.rawcover
+//
+// If the base coverage was collected on a different kernel build, also pass its config
+// with -diff-base-config, coverage is then compared by source lines.
+// Instead of raw coverage files, either side may be a single corpus.db file.
+// The corpus is then replayed with syz-manager -mode=corpus-triage (which needs to be built)
+// on the kernel from the corresponding config, and its raw coverage is compared:
+//
+//	syz-cover -config config_file -diff-base base/corpus.db new/corpus.db
 package main
 
 import (
@@ -30,11 +42,13 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/civil"
+	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/cover/backend"
 	"github.com/google/syzkaller/pkg/coveragedb"
@@ -62,9 +76,16 @@ var (
 	flagSourceCommit = flag.String("source-commit", "", "[optional] filter input commit")
 	flagExports      = flag.String("exports", "cover",
 		"[optional] comma separated list of exports for which we want to generate coverage, "+
-			"possible values are: cover, subsystem, module, funccover, json, jsonl, rawcover, rawcoverfiles, all")
+			"possible values are: cover, subsystem, module, funccover, json, jsonl, lcov, cobertura, "+
+			"rawcover, rawcoverfiles, all")
 	flagForce = flag.Bool("force", false, "[optional] create coverage report when "+
 		"there are missing coverage callbacks")
+	flagDiffBase = flag.String("diff-base", "", "[optional] comma separated list of raw coverage files "+
+		"or a corpus.db file to compare the coverage against (only cover and jsonl exports are supported)")
+	flagDiffBaseConfig = flag.String("diff-base-config", "", "[optional] configuration file of the kernel "+
+		"build the -diff-base coverage was collected on, if it differs from -config")
+	flagDiffBaseModules = flag.String("diff-base-modules", "",
+		"[optional] modules JSON info of the machine the -diff-base coverage was collected on")
 )
 
 func toolFileCover() {
@@ -124,6 +145,10 @@ func main() {
 		toolFileCover()
 		return
 	}
+	if *flagDiffBase != "" {
+		toolDiffCover()
+		return
+	}
 	cfg, err := mgrconfig.LoadFile(*flagConfig)
 	if err != nil {
 		tool.Fail(err)
@@ -164,12 +189,143 @@ func main() {
 			doReport(params, "json", rg.DoLineJSON)
 		case "jsonl":
 			doReport(params, "jsonl", rg.DoCoverJSONL)
+		case "lcov":
+			doReport(params, "syz-cover.info", rg.DoLCOV)
+		case "cobertura":
+			doReport(params, "syz-cover-cobertura.xml", rg.DoCobertura)
 		default:
 			tool.Failf("unknown export type: %q", export)
 		}
 	}
 }
 
+func toolDiffCover() {
+	if len(flag.Args()) == 0 {
+		tool.Failf("-diff-base requires the coverage to compare")
+	}
+	params := cover.DiffParams{
+		New: loadDiffInput(*flagConfig, flag.Args(), *flagModules, nil),
+	}
+	if *flagDiffBaseConfig == "" {
+		// The same build, so share the report generator.
+		params.Base = loadDiffInput(*flagConfig, strings.Split(*flagDiffBase, ","),
+			*flagDiffBaseModules, params.New.RG)
+	} else {
+		params.Base = loadDiffInput(*flagDiffBaseConfig, strings.Split(*flagDiffBase, ","),
+			*flagDiffBaseModules, nil)
+	}
+	for _, export := range strings.Split(*flagExports, ",") {
+		switch export {
+		case "cover":
+			doDiffReport(params, "syz-cover-diff.html", cover.DoDiffHTML)
+		case "jsonl":
+			doDiffReport(params, "syz-cover-diff.jsonl", cover.DoDiffJSONL)
+		default:
+			tool.Failf("export type %q is not supported with -diff-base", export)
+		}
+	}
+}
+
+// loadDiffInput loads one side of the coverage diff from raw coverage files
+// (collected on machines with the module layout from modulesFile, if set) or from a corpus.db file.
+// The report generator is created from the config, unless rg is given.
+func loadDiffInput(cfgFile string, files []string, modulesFile string, rg *cover.ReportGenerator) cover.DiffInput {
+	cfg, err := mgrconfig.LoadFile(cfgFile)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if rg == nil {
+		modules, err := backend.DiscoverModules(cfg.SysTarget, cfg.KernelObj, cfg.ModuleObj)
+		if err != nil {
+			tool.Fail(err)
+		}
+		rg, err = cover.MakeReportGenerator(cfg, modules)
+		if err != nil {
+			tool.Fail(err)
+		}
+	}
+	input := cover.DiffInput{
+		Name: strings.Join(files, ","),
+		RG:   rg,
+	}
+	if len(files) == 1 && filepath.Ext(files[0]) == ".db" {
+		input.PCs, input.Modules, err = replayCorpus(cfg, cfgFile, files[0])
+		if err != nil {
+			tool.Fail(err)
+		}
+		return input
+	}
+	if input.PCs, err = readPCs(files); err != nil {
+		tool.Fail(err)
+	}
+	if modulesFile != "" {
+		if input.Modules, err = loadModules(modulesFile); err != nil {
+			tool.Fail(err)
+		}
+	}
+	return input
+}
+
+// replayCorpus runs the corpus with syz-manager in a temporary workdir and returns
+// its raw coverage along with the module layout of the machines it was collected on.
+func replayCorpus(cfg *mgrconfig.Config, cfgFile, corpusFile string) ([]uint64, []*vminfo.KernelModule, error) {
+	workdir, err := os.MkdirTemp("", "syz-cover-replay")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(workdir)
+	if err := osutil.CopyFile(corpusFile, filepath.Join(workdir, "corpus.db")); err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(cfgFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err = config.PatchJSON(data, map[string]any{
+		"workdir":          workdir,
+		"http":             "",
+		"dashboard_client": "",
+		"hub_client":       "",
+		"reproduce":        false,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	replayCfg := filepath.Join(workdir, "manager.cfg")
+	if err := osutil.WriteFile(replayCfg, data); err != nil {
+		return nil, nil, err
+	}
+	log.Logf(0, "replaying %v", corpusFile)
+	cmd := osutil.GraciousCommand(filepath.Join(cfg.Syzkaller, "bin", "syz-manager"),
+		"-config", replayCfg, "-mode", "corpus-triage")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, nil, fmt.Errorf("failed to replay %v: %w", corpusFile, err)
+	}
+	pcs, err := readPCs([]string{filepath.Join(workdir, "rawcover")})
+	if err != nil {
+		return nil, nil, err
+	}
+	modules, err := loadModules(filepath.Join(workdir, "modules.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return pcs, modules, nil
+}
+
+func doDiffReport(params cover.DiffParams, fname string,
+	fn func(w io.Writer, params cover.DiffParams) error) {
+	buf := new(bytes.Buffer)
+	if err := fn(buf, params); err != nil {
+		tool.Fail(err)
+	}
+	log.Logf(0, "write to %v", fname)
+	if err := osutil.WriteFile(fname, buf.Bytes()); err != nil {
+		tool.Fail(err)
+	}
+}
+
 func doReport(params cover.HandlerParams, fname string,
 	fn func(w io.Writer, params cover.HandlerParams) error) {
 	buf := new(bytes.Buffer)
@@ -694,10 +694,32 @@ struct FunctionAnalyzer : RecursiveASTVisitor<FunctionAnalyzer> {
                                                         .Arg = AI,
                                                     });
       }
+      noteUserCopy(Call, Callee);
     }
     return true;
   }
 
+  // Records copies of constant size to/from user pointers.
+  // These allow to infer types of pointers that are not declared as such (e.g. ioctl arguments).
+  void noteUserCopy(const CallExpr* Call, const std::string& Callee) {
+    bool ToUser = Callee == "copy_to_user" || Callee == "_copy_to_user" || Callee == "__copy_to_user";
+    bool FromUser = Callee == "copy_from_user" || Callee == "_copy_from_user" || Callee == "__copy_from_user";
+    if ((!ToUser && !FromUser) || Call->getNumArgs() != 3)
+      return;
+    auto Ptr = getTypingEntity(Call->getArg(ToUser ? 0 : 1));
+    Expr::EvalResult Size;
+    if (!Ptr || !Call->getArg(2)->EvaluateAsInt(Size, *Context) || Size.Val.getInt().getExtValue() <= 0)
+      return;
+    UserCopy Copy{
+        .Ptr = std::move(*Ptr),
+        .Size = static_cast<int>(Size.Val.getInt().getExtValue()),
+        .ToUser = ToUser,
+    };
+    if (auto SizeofType = Extractor->getSizeofType(Call->getArg(2)))
+      Copy.Type = std::make_unique<FieldType>(Extractor->genType(*SizeofType));
+    Current->UserCopies.push_back(std::move(Copy));
+  }
+
   bool VisitSwitchStmt(const SwitchStmt* S) {
     // We are only interested in switches on the function arguments
     // with cases that mention defines from uapi headers.
@@ -156,12 +156,21 @@ struct TypingFact {
   TypingEntity Dst;
 };
 
+// UserCopy describes a copy_from_user/copy_to_user call.
+struct UserCopy {
+  TypingEntity Ptr; // the user pointer
+  int Size = 0;
+  bool ToUser = false;
+  std::unique_ptr<FieldType> Type; // type passed to sizeof in the size argument, if any
+};
+
 struct FunctionScope {
   int Arg = 0;
   int StartLine = 0;
   int EndLine = 0;
   std::vector<std::string> Values;
   std::vector<std::string> Calls;
+  std::vector<UserCopy> UserCopies;
   std::vector<TypingFact> Facts;
 };
 
@@ -361,6 +370,14 @@ inline void print(JSONPrinter& Printer, const TypingFact& V) {
   Printer.Field("dst", V.Dst, true);
 }
 
+inline void print(JSONPrinter& Printer, const UserCopy& V) {
+  JSONPrinter::Scope Scope(Printer);
+  Printer.Field("ptr", V.Ptr);
+  Printer.Field("size", V.Size);
+  Printer.Field("to_user", V.ToUser);
+  Printer.Field("type", V.Type, true);
+}
+
 inline void print(JSONPrinter& Printer, const FunctionScope& V) {
   JSONPrinter::Scope Scope(Printer);
   Printer.Field("arg", V.Arg);
@@ -368,6 +385,7 @@ inline void print(JSONPrinter& Printer, const FunctionScope& V) {
   Printer.Field("start_line", V.StartLine);
   Printer.Field("end_line", V.EndLine);
   Printer.Field("calls", V.Calls);
+  Printer.Field("user_copies", V.UserCopies);
   Printer.Field("facts", V.Facts, true);
 }
 
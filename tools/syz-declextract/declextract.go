@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -28,6 +29,7 @@ import (
 	"github.com/google/syzkaller/pkg/subsystem"
 	_ "github.com/google/syzkaller/pkg/subsystem/lists"
 	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
 	"golang.org/x/sync/errgroup"
 )
@@ -110,10 +112,14 @@ func run(cfg *config) (*declextract.Result, error) {
 		return nil, fmt.Errorf("failed to typecheck descriptions: %w\n%s", err, errors.Bytes())
 	}
 	finishInterfaces(res.Interfaces, consts, cfg.autoFile)
+	removeUnused(desc, "", unusedNodes)
+	// The compiler does not accept unused types, so this needs to be done after removing them.
+	if err := checkManualIoctls(desc.Clone(), res.Interfaces, consts, cfg.autoFile); err != nil {
+		return nil, err
+	}
 	if err := osutil.WriteFile(cfg.autoFile+".info", serialize(res.Interfaces)); err != nil {
 		return nil, err
 	}
-	removeUnused(desc, "", unusedNodes)
 	// Second pass to remove unused defines/includes. This needs to be done after removing
 	// other garbage b/c they may be used by other garbage.
 	unusedConsts, err := compiler.CollectUnusedConsts(desc.Clone(), target, res.IncludeUse, eh)
@@ -259,6 +265,16 @@ func serialize(interfaces []*declextract.Interface) []byte {
 		for _, subsys := range iface.Subsystems {
 			fmt.Fprintf(w, "\tsubsystem:%v", subsys)
 		}
+		if iface.Type == declextract.IfaceIoctl {
+			source := iface.ArgSource
+			if source == "" {
+				source = "unknown"
+			}
+			fmt.Fprintf(w, "\targ:%v", source)
+		}
+		for _, call := range iface.ManualMismatches {
+			fmt.Fprintf(w, "\tmanual_mismatch:%v", call)
+		}
 		fmt.Fprintf(w, "\n")
 	}
 	return w.Bytes()
@@ -289,6 +305,109 @@ func finishInterfaces(interfaces []*declextract.Interface, consts map[string]*co
 	}
 }
 
+// checkManualIoctls finds hand-written ioctl descriptions that contradict the inferred ioctl arguments:
+// the command is the same, but the argument points to memory of a different size.
+func checkManualIoctls(desc *ast.Description, interfaces []*declextract.Interface,
+	consts map[string]*compiler.ConstInfo, autoFile string) error {
+	// Syscall name -> ioctl command const.
+	manualCmds := make(map[string]string)
+	for _, node := range desc.Nodes {
+		call, ok := node.(*ast.Call)
+		if !ok || call.Pos.File == autoFile || call.CallName != "ioctl" || len(call.Args) < 3 {
+			continue
+		}
+		if cmd := call.Args[1].Type; cmd.Ident == "const" && len(cmd.Args) != 0 && cmd.Args[0].Ident != "" {
+			manualCmds[call.Name.Name] = cmd.Args[0].Ident
+		}
+	}
+	if len(manualCmds) == 0 {
+		return nil
+	}
+	// Type sizes may depend on const values, so use the real values where we have them.
+	// Values of other consts are not known, but they need to be distinct
+	// (e.g. the compiler rejects flags with all equal values). So we invent them twice
+	// with different values: if the size of the argument is not the same in both cases,
+	// it depends on the invented values and can't be compared with the inferred size.
+	values := compiler.DeserializeConstFile(filepath.Join(filepath.Dir(autoFile), "*.const"),
+		func(pos ast.Pos, msg string) {}).Arch(target.Arch)
+	if values == nil {
+		values = make(map[string]uint64)
+	}
+	var sizes [2]map[string]map[string]uint64
+	for i := range sizes {
+		invented := maps.Clone(values)
+		for _, info := range consts {
+			for _, c := range info.Consts {
+				if _, ok := invented[c.Name]; !ok {
+					invented[c.Name] = uint64(len(invented)+1) * uint64(i+1)
+				}
+			}
+		}
+		var err error
+		if sizes[i], err = manualIoctlSizes(desc.Clone(), manualCmds, invented); err != nil {
+			return err
+		}
+	}
+	manualSizes := sizes[0]
+	for cmd, calls := range manualSizes {
+		for call, size := range calls {
+			if sizes[1][cmd][call] != size {
+				delete(calls, call)
+			}
+		}
+	}
+	for _, iface := range interfaces {
+		if iface.Type != declextract.IfaceIoctl || iface.ArgSize == 0 {
+			continue
+		}
+		for call, size := range manualSizes[iface.IdentifyingConst] {
+			if size != uint64(iface.ArgSize) {
+				iface.ManualMismatches = append(iface.ManualMismatches, call)
+			}
+		}
+		slices.Sort(iface.ManualMismatches)
+	}
+	return nil
+}
+
+// manualIoctlSizes returns the sizes of the arguments of the hand-written ioctls
+// (ioctl command const -> syscall name -> size) compiled with the given const values.
+func manualIoctlSizes(desc *ast.Description, manualCmds map[string]string,
+	values map[string]uint64) (map[string]map[string]uint64, error) {
+	eh, errors := errorHandler()
+	compiled := compiler.Compile(desc, values, target, eh)
+	if compiled == nil {
+		return nil, fmt.Errorf("failed to compile descriptions\n%s", errors.Bytes())
+	}
+	// Compiled syscalls refer to types by index in compiled.Types.
+	resolve := func(typ prog.Type) prog.Type {
+		if ref, ok := typ.(prog.Ref); ok {
+			return compiled.Types[ref]
+		}
+		return typ
+	}
+	sizes := make(map[string]map[string]uint64)
+	for _, call := range compiled.Syscalls {
+		cmd := manualCmds[call.Name]
+		if cmd == "" {
+			continue
+		}
+		ptr, ok := resolve(call.Args[2].Type).(*prog.PtrType)
+		if !ok {
+			continue
+		}
+		elem := resolve(ptr.Elem)
+		if elem.Varlen() {
+			continue
+		}
+		if sizes[cmd] == nil {
+			sizes[cmd] = make(map[string]uint64)
+		}
+		sizes[cmd][call.Name] = elem.Size()
+	}
+	return sizes, nil
+}
+
 func buildSyscallRenameMap(sourceDir string, arches []string) (map[string][]string, error) {
 	// Some syscalls have different names and entry points and thus need to be renamed.
 	// e.g. SYSCALL_DEFINE1(setuid16, old_uid_t, uid) is referred to in the .tbl file with setuid.
@@ -0,0 +1,12 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+#define __user __attribute__((btf_type_tag("user")))
+
+static inline unsigned long copy_from_user(void* to, const void __user* from, unsigned long n) {
+	return 0;
+}
+
+static inline unsigned long copy_to_user(void __user* to, const void* from, unsigned long n) {
+	return 0;
+}
@@ -0,0 +1,18 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+#include "ioctl.h"
+
+// Old-style ioctls defined directly via numbers.
+#define COPY_IOCTL1		0x1001
+#define COPY_IOCTL2		0x1002
+#define COPY_IOCTL3		0x1003
+#define COPY_IOCTL4		_IOW('c', 4, struct copy_ioctl_arg)
+
+struct copy_ioctl_arg {
+	int a, b;
+};
+
+struct copy_ioctl_arg2 {
+	int a, b, c;
+};
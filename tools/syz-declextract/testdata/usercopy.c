@@ -0,0 +1,38 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+#include "include/fs.h"
+#include "include/linux/uaccess.h"
+#include "include/uapi/usercopy.h"
+
+static void copy_open() {}
+
+static void copy_ioctl_set(void __user* argp) {
+	struct copy_ioctl_arg2 a;
+	copy_from_user(&a, argp, sizeof(a));
+}
+
+static void copy_ioctl(void* file, unsigned int cmd, unsigned long arg) {
+	void __user* argp = (void __user*)arg;
+	switch (cmd) {
+	case COPY_IOCTL1: {
+		struct copy_ioctl_arg a;
+		copy_from_user(&a, argp, sizeof(a));
+		copy_to_user(argp, &a, sizeof(a));
+		break;
+	}
+	case COPY_IOCTL2:
+		copy_ioctl_set(argp);
+		break;
+	case COPY_IOCTL3:
+		copy_to_user(argp, &cmd, 4);
+		break;
+	case COPY_IOCTL4:
+		break;
+	}
+}
+
+const struct file_operations copy_fops = {
+	.open = copy_open,
+	.unlocked_ioctl = copy_ioctl,
+};
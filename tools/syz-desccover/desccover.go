@@ -0,0 +1,86 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-desccover shows which parts of syscall descriptions are exercised by corpus programs.
+// By default it prints only potential problems: fields that always have the same value,
+// flag values that are never set and union options that are never chosen.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/desccover"
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+)
+
+var (
+	flagOS     = flag.String("os", runtime.GOOS, "target os")
+	flagArch   = flag.String("arch", runtime.GOARCH, "target arch")
+	flagCorpus = flag.String("corpus", "", "name of the corpus file")
+	flagAll    = flag.Bool("all", false, "print all fields and unions, not only potential problems")
+)
+
+func main() {
+	flag.Parse()
+	target, err := prog.GetTarget(*flagOS, *flagArch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	corpus, err := db.ReadCorpus(*flagCorpus, target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read corpus: %v\n", err)
+		os.Exit(1)
+	}
+	stats := desccover.NewStats(target)
+	for _, p := range corpus {
+		stats.Add(p)
+	}
+	rep := stats.Report()
+	if !*flagAll {
+		rep = rep.Problems()
+	}
+	showReport(rep)
+}
+
+func showReport(rep *desccover.Report) {
+	fmt.Printf("programs: %v\n\nFIELDS:\n", rep.Progs)
+	for _, field := range rep.Fields {
+		var values []string
+		for _, val := range field.Values {
+			values = append(values, fmt.Sprintf("%#x:%v", val.Value, val.Count))
+		}
+		if field.Other != 0 {
+			values = append(values, fmt.Sprintf("other:%v", field.Other))
+		}
+		constant := ""
+		if field.Constant {
+			constant = " constant"
+		}
+		fmt.Printf("%-40v %-20v %8v%v  %v\n", field.Name, field.Type, field.Count, constant, strings.Join(values, " "))
+		if len(field.UnusedFlags) != 0 {
+			fmt.Printf("\tunused flags: %v\n", strings.Join(field.UnusedFlags, " "))
+		}
+	}
+	fmt.Printf("\nUNIONS:\n")
+	for _, union := range rep.Unions {
+		var unused, used []string
+		for _, opt := range union.Options {
+			if opt.Count == 0 {
+				unused = append(unused, opt.Name)
+			} else {
+				used = append(used, fmt.Sprintf("%v:%v", opt.Name, opt.Count))
+			}
+		}
+		fmt.Printf("%-40v %8v  %v\n", union.Name, union.Count, strings.Join(used, " "))
+		if len(unused) != 0 {
+			fmt.Printf("\tunused options: %v\n", strings.Join(unused, " "))
+		}
+	}
+}
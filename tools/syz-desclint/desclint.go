@@ -0,0 +1,111 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-desclint runs semantic lint checks on syscall descriptions
+// and prints problems along with suggested fixes (if any).
+// It should be run from the syzkaller source dir.
+//
+// Usage:
+//
+//	syz-desclint -os=linux -arch=amd64 [-enable=check1,check2] [-disable=check3]
+//
+// Use -list to see the available checks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var (
+	flagOS      = flag.String("os", runtime.GOOS, "target OS")
+	flagArch    = flag.String("arch", runtime.GOARCH, "target arch")
+	flagEnable  = flag.String("enable", "", "comma-separated list of checks to run (all by default)")
+	flagDisable = flag.String("disable", "", "comma-separated list of checks to skip")
+	flagList    = flag.Bool("list", false, "list available checks and exit")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagList {
+		for _, check := range compiler.LintChecks {
+			fmt.Printf("%-16v %v\n", check.Name, check.Description)
+		}
+		return
+	}
+	target := targets.Get(*flagOS, *flagArch)
+	if target == nil {
+		tool.Failf("unknown target %v/%v", *flagOS, *flagArch)
+	}
+	checks, err := selectChecks(*flagEnable, *flagDisable)
+	if err != nil {
+		tool.Fail(err)
+	}
+	desc := ast.ParseGlob(filepath.Join("sys", target.OS, "*.txt"), nil)
+	if desc == nil {
+		tool.Failf("failed to parse descriptions")
+	}
+	consts := compiler.DeserializeConstFile(filepath.Join("sys", target.OS, "*.const"), nil).Arch(target.Arch)
+	if consts == nil {
+		tool.Failf("failed to parse const files")
+	}
+	issues, err := compiler.Lint(desc, consts, target, checks, nil)
+	if err != nil {
+		tool.Fail(err)
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) != 0 {
+		os.Exit(1)
+	}
+}
+
+func selectChecks(enable, disable string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, check := range compiler.LintChecks {
+		known[check.Name] = true
+	}
+	disabled := make(map[string]bool)
+	for _, name := range splitList(disable) {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown check %q", name)
+		}
+		disabled[name] = true
+	}
+	enabled := splitList(enable)
+	if len(enabled) == 0 {
+		for _, check := range compiler.LintChecks {
+			enabled = append(enabled, check.Name)
+		}
+	}
+	var res []string
+	for _, name := range enabled {
+		if !disabled[name] {
+			res = append(res, name)
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("all checks are disabled")
+	}
+	return res, nil
+}
+
+func splitList(list string) []string {
+	var res []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			res = append(res, name)
+		}
+	}
+	return res
+}
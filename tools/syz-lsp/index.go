@@ -0,0 +1,293 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// index is the result of analysis of all descriptions in a single directory.
+type index struct {
+	dir    string
+	os     string
+	arch   string
+	files  map[string][]byte // file path -> analyzed contents
+	defs   map[string][]*definition
+	consts map[string]map[string]uint64 // const name -> arch -> value
+	diags  map[string][]diagnostic      // file path -> diagnostics
+}
+
+type definition struct {
+	typ  string // as returned by ast.Node.Info
+	pos  ast.Pos
+	node ast.Node
+}
+
+// analyze parses and compiles descriptions in dir.
+// overlay contains contents of files that are open in the editor and may be not saved yet.
+func analyze(dir, OS, arch string, overlay map[string][]byte) *index {
+	idx := &index{
+		dir:    dir,
+		os:     OS,
+		arch:   arch,
+		files:  make(map[string][]byte),
+		defs:   make(map[string][]*definition),
+		consts: make(map[string]map[string]uint64),
+		diags:  make(map[string][]diagnostic),
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	for file := range overlay {
+		if filepath.Dir(file) == dir && strings.HasSuffix(file, ".txt") && !contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	eh := func(pos ast.Pos, msg string) {
+		if pos.File == "" || pos.Builtin() {
+			return
+		}
+		idx.diags[pos.File] = append(idx.diags[pos.File], diagnostic{
+			Range:    idx.identRange(pos),
+			Severity: severityError,
+			Source:   "syz-lsp",
+			Message:  msg,
+		})
+	}
+	desc := &ast.Description{}
+	parsed := true
+	for _, file := range files {
+		data, ok := overlay[file]
+		if !ok {
+			var err error
+			if data, err = os.ReadFile(file); err != nil {
+				continue
+			}
+		}
+		idx.files[file] = data
+		fileDesc := ast.Parse(data, file, eh)
+		if fileDesc == nil {
+			parsed = false
+			continue
+		}
+		desc.Nodes = append(desc.Nodes, fileDesc.Nodes...)
+	}
+	for _, n := range desc.Nodes {
+		if ident := definedName(n); ident != nil {
+			_, typ, _ := n.Info()
+			idx.defs[ident.Name] = append(idx.defs[ident.Name], &definition{typ: typ, pos: ident.Pos, node: n})
+		}
+	}
+	constFile := compiler.DeserializeConstFile(filepath.Join(dir, "*.const"), func(pos ast.Pos, msg string) {})
+	for arch := range targets.List[OS] {
+		for name, val := range constFile.Arch(arch) {
+			if idx.consts[name] == nil {
+				idx.consts[name] = make(map[string]uint64)
+			}
+			idx.consts[name][arch] = val
+		}
+	}
+	// Compilation errors of partially parsed descriptions are misleading
+	// (e.g. all types from a broken file are unknown), so only parsing errors are reported then.
+	target := targets.Get(OS, arch)
+	if parsed && target != nil {
+		var consts map[string]uint64
+		if constFile != nil {
+			consts = constFile.Arch(arch)
+		}
+		compiler.Compile(desc, consts, target, eh)
+	}
+	return idx
+}
+
+func definedName(n ast.Node) *ast.Ident {
+	switch n := n.(type) {
+	case *ast.Resource:
+		return n.Name
+	case *ast.Struct:
+		return n.Name
+	case *ast.TypeDef:
+		return n.Name
+	case *ast.IntFlags:
+		return n.Name
+	case *ast.StrFlags:
+		return n.Name
+	case *ast.Call:
+		return n.Name
+	case *ast.Define:
+		return n.Name
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
+
+// identRange returns the range of the identifier that starts at pos (or a single character).
+func (idx *index) identRange(pos ast.Pos) lspRange {
+	start := position{Line: max(pos.Line-1, 0), Character: max(pos.Col-1, 0)}
+	end := start
+	end.Character++
+	line := lineAt(idx.files[pos.File], start.Line)
+	for i := start.Character; i < len(line) && isIdentChar(line[i]); i++ {
+		end.Character = i + 1
+	}
+	return lspRange{Start: start, End: end}
+}
+
+func lineAt(text []byte, line int) []byte {
+	for ; line > 0; line-- {
+		nl := bytes.IndexByte(text, '\n')
+		if nl == -1 {
+			return nil
+		}
+		text = text[nl+1:]
+	}
+	if nl := bytes.IndexByte(text, '\n'); nl != -1 {
+		text = text[:nl]
+	}
+	return text
+}
+
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$'
+}
+
+// wordAt returns the identifier under the cursor.
+func wordAt(text []byte, pos position) string {
+	line := lineAt(text, pos.Line)
+	start, end := min(pos.Character, len(line)), min(pos.Character, len(line))
+	for start > 0 && isIdentChar(line[start-1]) {
+		start--
+	}
+	for end < len(line) && isIdentChar(line[end]) {
+		end++
+	}
+	return string(line[start:end])
+}
+
+func (idx *index) definitions(word string) []location {
+	var res []location
+	for _, def := range idx.defs[word] {
+		res = append(res, location{
+			URI:   pathToURI(def.pos.File),
+			Range: idx.identRange(def.pos),
+		})
+	}
+	return res
+}
+
+// hover returns markdown describing the identifier: its definition and values of the consts it refers to.
+func (idx *index) hover(word string) string {
+	buf := new(bytes.Buffer)
+	for _, def := range idx.defs[word] {
+		text := strings.TrimSpace(ast.SerializeNode(def.node))
+		if lines := strings.Split(text, "\n"); len(lines) > maxHoverLines {
+			text = strings.Join(lines[:maxHoverLines], "\n") + "\n..."
+		}
+		fmt.Fprintf(buf, "```\n%v\n```\n", text)
+		if flags, ok := def.node.(*ast.IntFlags); ok {
+			for _, val := range flags.Values {
+				if val.Ident != "" {
+					fmt.Fprintf(buf, "- %v\n", idx.constValue(val.Ident))
+				}
+			}
+		}
+	}
+	if _, ok := idx.consts[word]; ok {
+		fmt.Fprintf(buf, "%v\n", idx.constValue(word))
+	}
+	return buf.String()
+}
+
+const maxHoverLines = 30
+
+// constValue formats values of the const for all arches, e.g. "O_RDWR = 2" or "FOO = amd64:1 arm:2".
+func (idx *index) constValue(name string) string {
+	vals := idx.consts[name]
+	if len(vals) == 0 {
+		return fmt.Sprintf("%v = ???", name)
+	}
+	var arches []string
+	distinct := make(map[uint64]bool)
+	for arch, val := range vals {
+		arches = append(arches, arch)
+		distinct[val] = true
+	}
+	if len(distinct) == 1 {
+		val := vals[arches[0]]
+		return fmt.Sprintf("%v = %v (%#x)", name, val, val)
+	}
+	sort.Strings(arches)
+	var res []string
+	for _, arch := range arches {
+		res = append(res, fmt.Sprintf("%v:%v", arch, vals[arch]))
+	}
+	return fmt.Sprintf("%v = %v", name, strings.Join(res, " "))
+}
+
+// complete returns completion candidates given the text of the current line up to the cursor.
+func (idx *index) complete(line string) []completionItem {
+	kw := compiler.BuiltinKeywords()
+	attrs := func(names []string) []completionItem {
+		var res []completionItem
+		for _, name := range names {
+			res = append(res, completionItem{Label: name, Kind: completionKindProperty, Detail: "attribute"})
+		}
+		return res
+	}
+	// Attributes go into parentheses after fields and calls, and into brackets after structs and unions.
+	trimmed := strings.TrimSpace(line)
+	if open := strings.LastIndexByte(line, '('); open != -1 && !strings.Contains(line[open:], ")") {
+		if line[0] == ' ' || line[0] == '\t' {
+			return attrs(kw.FieldAttrs)
+		}
+		if strings.Contains(line[:open], ")") {
+			return attrs(kw.CallAttrs)
+		}
+	}
+	if strings.HasPrefix(trimmed, "}") && strings.Contains(trimmed, "[") {
+		return attrs(kw.StructAttrs)
+	}
+	if strings.HasPrefix(trimmed, "]") && strings.Contains(trimmed, "[") {
+		return attrs(kw.UnionAttrs)
+	}
+	var res []completionItem
+	for _, name := range kw.Types {
+		res = append(res, completionItem{Label: name, Kind: completionKindKeyword, Detail: "builtin"})
+	}
+	var names []string
+	for name := range idx.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := idx.defs[name][0]
+		kind := completionKindStruct
+		switch def.typ {
+		case "syscall", "define":
+			continue
+		case "resource":
+			kind = completionKindClass
+		case "flags", "string flags":
+			kind = completionKindEnum
+		}
+		res = append(res, completionItem{Label: name, Kind: kind, Detail: def.typ})
+	}
+	return res
+}
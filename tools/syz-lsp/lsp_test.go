@@ -0,0 +1,192 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testFileA = `resource fd_test[int32]
+
+syz_open(a ptr[in, s0], f flags[test_flags]) fd_test
+
+s0 {
+	f0	int32
+	f1	fd_test
+}
+
+test_flags = TEST_A, TEST_B
+`
+	testFileB = `syz_close(fd fd_test, a ptr[in, s1])
+`
+	testConsts = `arches = 32, 32_fork, 64, 64_fork, 64_fuzz
+TEST_A = 1
+TEST_B = 2, 64:3
+`
+)
+
+type testClient struct {
+	t   *testing.T
+	w   io.Writer
+	r   *textproto.Reader
+	id  int
+	dir string
+}
+
+func (c *testClient) send(method string, id int, params any) {
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+	if id != 0 {
+		msg["id"] = id
+	}
+	data, err := json.Marshal(msg)
+	require.NoError(c.t, err)
+	_, err = io.WriteString(c.w, "Content-Length: "+strconv.Itoa(len(data))+"\r\n\r\n"+string(data))
+	require.NoError(c.t, err)
+}
+
+// receive returns the next message with the given id or method.
+func (c *testClient) receive(id int, method string) map[string]json.RawMessage {
+	for {
+		header, err := c.r.ReadMIMEHeader()
+		require.NoError(c.t, err)
+		size, err := strconv.Atoi(header.Get("Content-Length"))
+		require.NoError(c.t, err)
+		data := make([]byte, size)
+		_, err = io.ReadFull(c.r.R, data)
+		require.NoError(c.t, err)
+		var msg map[string]json.RawMessage
+		require.NoError(c.t, json.Unmarshal(data, &msg))
+		if id != 0 && string(msg["id"]) == strconv.Itoa(id) ||
+			method != "" && string(msg["method"]) == strconv.Quote(method) {
+			return msg
+		}
+	}
+}
+
+func (c *testClient) call(method string, params, result any) {
+	c.id++
+	c.send(method, c.id, params)
+	msg := c.receive(c.id, "")
+	require.Nil(c.t, msg["error"])
+	require.NoError(c.t, json.Unmarshal(msg["result"], result))
+}
+
+func (c *testClient) position(file string, line, char int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]any{"uri": pathToURI(filepath.Join(c.dir, file))},
+		"position":     map[string]any{"line": line, "character": char},
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	for file, data := range map[string]string{
+		"a.txt":       testFileA,
+		"b.txt":       testFileB,
+		"a.txt.const": testConsts,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(data), 0644))
+	}
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	srv := newServer(serverR, serverW, targets.TestOS, targets.TestArch64)
+	done := make(chan bool)
+	go func() {
+		shutdown, err := srv.serve()
+		assert.NoError(t, err)
+		done <- shutdown
+	}()
+	c := &testClient{
+		t:   t,
+		w:   clientW,
+		r:   textproto.NewReader(bufio.NewReader(clientR)),
+		dir: dir,
+	}
+
+	var init initializeResult
+	c.call("initialize", map[string]any{"rootUri": pathToURI(dir)}, &init)
+	assert.True(t, init.Capabilities.DefinitionProvider)
+	c.send("initialized", 0, map[string]any{})
+
+	// Diagnostics are published for the file with the unknown type.
+	uriB := pathToURI(filepath.Join(dir, "b.txt"))
+	c.send("textDocument/didOpen", 0, map[string]any{
+		"textDocument": map[string]any{"uri": uriB, "text": testFileB},
+	})
+	var diags publishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(c.receive(0, "textDocument/publishDiagnostics")["params"], &diags))
+	assert.Equal(t, uriB, diags.URI)
+	require.Len(t, diags.Diagnostics, 1)
+	assert.Contains(t, diags.Diagnostics[0].Message, "unknown type s1")
+	assert.Equal(t, lspRange{Start: position{0, 32}, End: position{0, 34}}, diags.Diagnostics[0].Range)
+
+	// Fixing the file clears the diagnostics.
+	c.send("textDocument/didChange", 0, map[string]any{
+		"textDocument":   map[string]any{"uri": uriB},
+		"contentChanges": []map[string]any{{"text": strings.Replace(testFileB, "s1", "s0", 1)}},
+	})
+	require.NoError(t, json.Unmarshal(c.receive(0, "textDocument/publishDiagnostics")["params"], &diags))
+	assert.Equal(t, uriB, diags.URI)
+	assert.Empty(t, diags.Diagnostics)
+
+	// Definition of fd_test used in b.txt is in a.txt.
+	var locs []location
+	c.call("textDocument/definition", c.position("b.txt", 0, 16), &locs)
+	assert.Equal(t, []location{{
+		URI:   pathToURI(filepath.Join(dir, "a.txt")),
+		Range: lspRange{Start: position{0, 9}, End: position{0, 16}},
+	}}, locs)
+
+	var h hover
+	c.call("textDocument/hover", c.position("a.txt", 9, 2), &h)
+	assert.Contains(t, h.Contents.Value, "test_flags = TEST_A, TEST_B")
+	assert.Contains(t, h.Contents.Value, "TEST_A = 1 (0x1)")
+	assert.Contains(t, h.Contents.Value, "TEST_B = 32:2 32_fork:2 64:3 64_fork:2 64_fuzz:2")
+
+	var items []completionItem
+	c.call("textDocument/completion", c.position("a.txt", 5, 5), &items)
+	labels := map[string]string{}
+	for _, item := range items {
+		labels[item.Label] = item.Detail
+	}
+	assert.Equal(t, "builtin", labels["int32"])
+	assert.Equal(t, "builtin", labels["ptr"])
+	assert.Equal(t, "resource", labels["fd_test"])
+	assert.Equal(t, "struct", labels["s0"])
+	assert.Equal(t, "flags", labels["test_flags"])
+	assert.NotContains(t, labels, "syz_open")
+
+	c.call("shutdown", nil, new(any))
+	c.send("exit", 0, nil)
+	assert.True(t, <-done)
+}
+
+func TestComplete(t *testing.T) {
+	idx := &index{}
+	labels := func(line string) []string {
+		var res []string
+		for _, item := range idx.complete(line) {
+			res = append(res, item.Label)
+		}
+		return res
+	}
+	assert.Contains(t, labels("\tf0\tint32 ("), "out_overlay")
+	assert.Contains(t, labels("foo(a int32) (dis"), "disabled")
+	assert.Equal(t, []string{"align", "packed", "size"}, labels("} ["))
+	assert.Equal(t, []string{"size", "varlen"}, labels("] ["))
+	assert.Contains(t, labels("foo(a ptr[in, "), "array")
+}
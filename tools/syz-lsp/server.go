@@ -0,0 +1,291 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+type server struct {
+	conn *conn
+	os   string // OS for descriptions outside of sys/OS dirs
+	arch string // arch to compile descriptions for, if empty, chosen automatically
+
+	mu        sync.Mutex
+	docs      map[string][]byte // path -> contents of files open in the editor
+	indexes   map[string]*index // dir -> the latest analysis results
+	published map[string]bool   // files with non-empty published diagnostics
+	shutdown  bool
+	analyzeC  chan string
+}
+
+// Analysis of all linux descriptions takes a few seconds, so we don't re-analyze on every key press.
+const analysisDelay = 500 * time.Millisecond
+
+func newServer(r io.Reader, w io.Writer, OS, arch string) *server {
+	return &server{
+		conn:      newConn(r, w),
+		os:        OS,
+		arch:      arch,
+		docs:      make(map[string][]byte),
+		indexes:   make(map[string]*index),
+		published: make(map[string]bool),
+		analyzeC:  make(chan string, 16),
+	}
+}
+
+// serve handles messages until the exit notification or EOF.
+// Returns whether shutdown was requested before exit.
+func (s *server) serve() (bool, error) {
+	go s.analysisLoop()
+	for {
+		req, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return false, err
+		}
+		if req.Method == "exit" {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			return s.shutdown, nil
+		}
+		result, rerr := s.handle(req)
+		if req.ID == nil {
+			if rerr != nil {
+				log.Logf(0, "%v: %v", req.Method, rerr.Message)
+			}
+			continue
+		}
+		if err := s.conn.reply(req.ID, result, rerr); err != nil {
+			return false, err
+		}
+	}
+}
+
+func (s *server) handle(req *request) (any, *rpcError) {
+	decode := func(params any) *rpcError {
+		if err := json.Unmarshal(req.Params, params); err != nil {
+			return &rpcError{Code: errInvalidParams, Message: err.Error()}
+		}
+		return nil
+	}
+	switch req.Method {
+	case "initialize":
+		return &initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   textDocumentSyncFull,
+				DefinitionProvider: true,
+				HoverProvider:      true,
+				CompletionProvider: completionOptions{
+					TriggerCharacters: []string{"[", "(", ","},
+				},
+			},
+			ServerInfo: serverInfo{Name: "syz-lsp"},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "textDocument/didSave":
+		return nil, nil
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		s.mu.Unlock()
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+		return nil, s.updateDoc(params.TextDocument.URI, []byte(params.TextDocument.Text))
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.updateDoc(params.TextDocument.URI, []byte(text))
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+		file, err := uriToPath(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		delete(s.docs, file)
+		s.mu.Unlock()
+		s.analyzeC <- filepath.Dir(file)
+		return nil, nil
+	case "textDocument/definition", "textDocument/hover", "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+		return s.query(req.Method, params)
+	}
+	return nil, &rpcError{Code: errMethodNotFound, Message: fmt.Sprintf("unsupported method %v", req.Method)}
+}
+
+func (s *server) updateDoc(uri string, text []byte) *rpcError {
+	file, err := uriToPath(uri)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.docs[file] = text
+	s.mu.Unlock()
+	s.analyzeC <- filepath.Dir(file)
+	return nil
+}
+
+func (s *server) query(method string, params textDocumentPositionParams) (any, *rpcError) {
+	file, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	idx := s.index(filepath.Dir(file))
+	s.mu.Lock()
+	text, ok := s.docs[file]
+	s.mu.Unlock()
+	if !ok {
+		text = idx.files[file]
+	}
+	switch method {
+	case "textDocument/definition":
+		return idx.definitions(wordAt(text, params.Position)), nil
+	case "textDocument/hover":
+		contents := idx.hover(wordAt(text, params.Position))
+		if contents == "" {
+			return nil, nil
+		}
+		return &hover{Contents: markupContent{Kind: "markdown", Value: contents}}, nil
+	default:
+		line := lineAt(text, params.Position.Line)
+		line = line[:min(params.Position.Character, len(line))]
+		return idx.complete(string(line)), nil
+	}
+}
+
+// index returns the latest analysis results for the dir, running the analysis if there are none yet.
+func (s *server) index(dir string) *index {
+	s.mu.Lock()
+	idx := s.indexes[dir]
+	s.mu.Unlock()
+	if idx == nil {
+		idx = s.analyze(dir)
+	}
+	return idx
+}
+
+func (s *server) analysisLoop() {
+	for dir := range s.analyzeC {
+		dirs := map[string]bool{dir: true}
+		// Coalesce updates that come in quick succession.
+		for timeout := time.After(analysisDelay); ; {
+			select {
+			case dir := <-s.analyzeC:
+				dirs[dir] = true
+				continue
+			case <-timeout:
+			}
+			break
+		}
+		for dir := range dirs {
+			s.analyze(dir)
+		}
+	}
+}
+
+func (s *server) analyze(dir string) *index {
+	OS, arch := s.target(dir)
+	s.mu.Lock()
+	overlay := make(map[string][]byte)
+	for file, text := range s.docs {
+		overlay[file] = text
+	}
+	s.mu.Unlock()
+	idx := analyze(dir, OS, arch, overlay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.indexes[dir] = idx
+	var files []string
+	for file := range idx.files {
+		if len(idx.diags[file]) != 0 || s.published[file] {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		diags := idx.diags[file]
+		if diags == nil {
+			diags = []diagnostic{}
+		}
+		s.published[file] = len(diags) != 0
+		if err := s.conn.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{
+			URI:         pathToURI(file),
+			Diagnostics: diags,
+		}); err != nil {
+			log.Logf(0, "failed to publish diagnostics: %v", err)
+		}
+	}
+	return idx
+}
+
+// target returns OS/arch for descriptions in the dir: sys/OS dirs are compiled for that OS.
+func (s *server) target(dir string) (string, string) {
+	OS := s.os
+	if targets.List[filepath.Base(dir)] != nil {
+		OS = filepath.Base(dir)
+	}
+	arches := targets.List[OS]
+	if arches[s.arch] != nil {
+		return OS, s.arch
+	}
+	if arches[targets.AMD64] != nil {
+		return OS, targets.AMD64
+	}
+	var names []string
+	for arch := range arches {
+		names = append(names, arch)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return OS, ""
+	}
+	return OS, names[0]
+}
+
+func uriToPath(uri string) (string, *rpcError) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", &rpcError{Code: errInvalidParams, Message: fmt.Sprintf("unsupported document uri %q", uri)}
+	}
+	return filepath.Clean(u.Path), nil
+}
+
+func pathToURI(file string) string {
+	if !strings.HasPrefix(file, "/") {
+		if abs, err := filepath.Abs(file); err == nil {
+			file = abs
+		}
+	}
+	return (&url.URL{Scheme: "file", Path: file}).String()
+}
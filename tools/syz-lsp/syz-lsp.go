@@ -0,0 +1,42 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-lsp is a Language Server Protocol server for syzlang descriptions.
+// It communicates with the editor over stdin/stdout and provides:
+//   - diagnostics (parsing and compilation errors, the same as syz-sysgen reports),
+//   - go-to-definition for types, resources, flags, syscalls and defines across files,
+//   - hover with definitions and const values from .const files,
+//   - completion of type names and attributes.
+//
+// All descriptions in the directory of the edited file are analyzed together.
+// Files in sys/OS directories are compiled for that OS, other files for the -os flag.
+//
+// For example, for Neovim:
+//
+//	vim.lsp.start({name = "syz-lsp", cmd = {"syz-lsp"}, root_dir = "/path/to/syzkaller"})
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+var (
+	flagOS   = flag.String("os", targets.Linux, "OS to compile descriptions for if they are not in a sys/OS dir")
+	flagArch = flag.String("arch", "", "arch to compile descriptions for (default: amd64, if supported)")
+)
+
+func main() {
+	defer tool.Init()()
+	srv := newServer(os.Stdin, os.Stdout, *flagOS, *flagArch)
+	shutdown, err := srv.serve()
+	if err != nil {
+		tool.Fail(err)
+	}
+	if !shutdown {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-patchtest tests kernel patches against reproducers on a local machine,
+// without the dashboard, App Engine or Kubernetes.
+//
+// The tool requires a config file passed in -config flag, see Config type below for details.
+//
+// In the one-shot mode it tests a single patch (-patch flag) against the reproducer
+// in the crash dir (-crash flag, see tools/syz-bisect for the expected files)
+// and prints the JSON result to stdout:
+//
+//	syz-patchtest -config patchtest.cfg -crash crash-dir -patch fix.patch
+//
+// With -http flag it runs as a service that accepts requests over HTTP and persists
+// tests and results in the storage dir:
+//
+//	POST /api/tests      - submit a request (see patchtest.Request), returns the test ID
+//	GET  /api/tests      - list all tests
+//	GET  /api/tests/<id> - get the test status, result and log
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/patchtest"
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagConfig       = flag.String("config", "", "patch testing config file")
+	flagHTTP         = flag.String("http", "", "serve HTTP API on this address")
+	flagCrash        = flag.String("crash", "", "dir with crash info (one-shot mode)")
+	flagPatch        = flag.String("patch", "", "patch file to test (one-shot mode)")
+	flagKernelRepo   = flag.String("kernel_repo", "", "kernel repo to test on (one-shot mode)")
+	flagKernelBranch = flag.String("kernel_branch", "", "kernel branch to test on (one-shot mode)")
+	flagKernelCommit = flag.String("kernel_commit", "", "kernel commit to test on (one-shot mode)")
+	flagRuns         = flag.Int("runs", 0, "number of reproducer runs on each kernel (one-shot mode)")
+	flagSkipBase     = flag.Bool("skip_base", false, "don't test the unpatched kernel (one-shot mode)")
+)
+
+type Config struct {
+	Compiler string `json:"compiler"`
+	Make     string `json:"make"`
+	Linker   string `json:"linker"`
+	Ccache   string `json:"ccache"`
+	// Directory with user-space system for building kernel images
+	// (for linux that's the input to tools/create-gce-image.sh).
+	Userspace string `json:"userspace"`
+	Sysctl    string `json:"sysctl"`
+	Cmdline   string `json:"cmdline"`
+	// Default kernel config, requests may override it.
+	KernelConfig string `json:"kernel_config"`
+	BuildCPUs    int    `json:"build_cpus"`
+	// If set, syzkaller is checked out and built at syzkaller_commit before testing.
+	// Otherwise, the syzkaller build in the manager config syzkaller dir is used.
+	SyzkallerRepo   string `json:"syzkaller_repo"`
+	SyzkallerCommit string `json:"syzkaller_commit"`
+	// Directory where the service stores tests and results.
+	Storage string `json:"storage"`
+
+	// Manager config used to build images and boot VMs.
+	// kernel_src is the kernel checkout that is used for testing.
+	Manager json.RawMessage `json:"manager"`
+}
+
+func main() {
+	flag.Parse()
+	os.Setenv("SYZ_DISABLE_SANDBOXING", "yes")
+	mycfg := &Config{
+		SyzkallerCommit: "master",
+	}
+	if err := config.LoadFile(*flagConfig, mycfg); err != nil {
+		tool.Fail(err)
+	}
+	mgrcfg, err := mgrconfig.LoadData(mycfg.Manager)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if mgrcfg.Workdir == "" {
+		mgrcfg.Workdir, err = os.MkdirTemp("", "syz-patchtest")
+		if err != nil {
+			tool.Failf("failed to create temp dir: %v", err)
+		}
+		defer os.RemoveAll(mgrcfg.Workdir)
+	}
+	cfg := &patchtest.Config{
+		Manager:         mgrcfg,
+		SyzkallerRepo:   mycfg.SyzkallerRepo,
+		SyzkallerCommit: mycfg.SyzkallerCommit,
+		Compiler:        mycfg.Compiler,
+		Make:            mycfg.Make,
+		Linker:          mycfg.Linker,
+		Ccache:          mycfg.Ccache,
+		Userspace:       mycfg.Userspace,
+		Cmdline:         mycfg.Cmdline,
+		Sysctl:          mycfg.Sysctl,
+		BuildCPUs:       mycfg.BuildCPUs,
+	}
+	if mycfg.KernelConfig != "" {
+		if cfg.KernelConfig, err = os.ReadFile(mycfg.KernelConfig); err != nil {
+			tool.Fail(err)
+		}
+	}
+	tester, err := patchtest.NewTester(cfg)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if *flagHTTP != "" {
+		serve(tester, mycfg.Storage)
+		return
+	}
+	req := &patchtest.Request{
+		KernelRepo:   *flagKernelRepo,
+		KernelBranch: *flagKernelBranch,
+		KernelCommit: *flagKernelCommit,
+		Runs:         *flagRuns,
+		SkipBase:     *flagSkipBase,
+	}
+	if err := patchtest.ReadRequestFiles(req, *flagCrash, *flagPatch); err != nil {
+		tool.Fail(err)
+	}
+	res, err := tester.Run(req, func(msg string, args ...any) {
+		log.Logf(0, msg, args...)
+	})
+	if err != nil {
+		tool.Failf("patch testing failed: %v", err)
+	}
+	data, err := json.MarshalIndent(res, "", "\t")
+	if err != nil {
+		tool.Fail(err)
+	}
+	os.Stdout.Write(append(data, '\n'))
+}
+
+func serve(runner patchtest.Runner, dir string) {
+	if dir == "" {
+		tool.Failf("the service requires storage dir in the config")
+	}
+	storage, err := patchtest.NewFileStorage(dir)
+	if err != nil {
+		tool.Fail(err)
+	}
+	service, err := patchtest.NewService(runner, storage)
+	if err != nil {
+		tool.Fail(err)
+	}
+	stop := make(chan struct{})
+	osutil.HandleInterrupts(stop)
+	go service.Loop(stop)
+	http.Handle("/api/tests", service)
+	http.Handle("/api/tests/", service)
+	log.Logf(0, "serving patch testing API on %v", *flagHTTP)
+	server := &http.Server{Addr: *flagHTTP}
+	go func() {
+		<-stop
+		server.Close()
+	}()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		tool.Fail(err)
+	}
+}
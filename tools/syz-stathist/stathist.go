@@ -0,0 +1,86 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-stathist exports stats graphs history saved by syz-manager (workdir/stats-history.json)
+// as CSV for offline plotting. Each CSV record contains graph title, line name,
+// seconds since the manager start and the value.
+//
+// Usage:
+//
+//	syz-stathist -history=workdir/stats-history.json [-graph=regexp] [-out=file.csv]
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagHistory = flag.String("history", "", "stats history file (workdir/stats-history.json)")
+	flagGraph   = flag.String("graph", "", "regexp that graph titles must match (all by default)")
+	flagOut     = flag.String("out", "", "output CSV file (stdout by default)")
+)
+
+func main() {
+	defer tool.Init()()
+	if *flagHistory == "" {
+		tool.Failf("specify -history")
+	}
+	graphRe, err := regexp.Compile(*flagGraph)
+	if err != nil {
+		tool.Failf("bad -graph: %v", err)
+	}
+	graphs, err := stat.ReadHistory(*flagHistory)
+	if err != nil {
+		tool.Fail(err)
+	}
+	out := io.Writer(os.Stdout)
+	if *flagOut != "" {
+		f, err := os.Create(*flagOut)
+		if err != nil {
+			tool.Fail(err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := writeCSV(out, graphs, graphRe); err != nil {
+		tool.Fail(err)
+	}
+}
+
+func writeCSV(out io.Writer, graphs []stat.UIGraph, graphRe *regexp.Regexp) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"graph", "line", "seconds", "value"}); err != nil {
+		return err
+	}
+	for _, graph := range graphs {
+		if !graphRe.MatchString(graph.Title) {
+			continue
+		}
+		for i, line := range graph.Lines {
+			// Lines are formatted as "name: description".
+			name, _, _ := strings.Cut(line, ": ")
+			for _, point := range graph.Points {
+				err := w.Write([]string{graph.Title, name, strconv.Itoa(point.X),
+					strconv.FormatFloat(point.Y[i], 'g', -1, 64)})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
@@ -6,14 +6,21 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"sort"
 )
 
 // TraceTree struct contains intermediate representation of trace.
 // If a trace is multiprocess it constructs a trace for each type.
 type TraceTree struct {
 	TraceMap map[int64]*Trace
-	Ptree    map[int64][]int64
-	RootPid  int64
+	// Ptree maps a process to the child processes it created.
+	Ptree map[int64][]int64
+	// Threads maps a thread group leader to the other threads of the group.
+	Threads map[int64][]int64
+	RootPid int64
+
+	leader map[int64]int64
+	line   int
 }
 
 // NewTraceTree initializes a TraceTree.
@@ -21,6 +28,8 @@ func NewTraceTree() *TraceTree {
 	return &TraceTree{
 		TraceMap: make(map[int64]*Trace),
 		Ptree:    make(map[int64][]int64),
+		Threads:  make(map[int64][]int64),
+		leader:   make(map[int64]int64),
 	}
 }
 
@@ -32,8 +41,93 @@ func (tree *TraceTree) add(call *Syscall) {
 		tree.TraceMap[call.Pid] = new(Trace)
 	}
 	c := tree.TraceMap[call.Pid].add(call)
-	if c.CallName == "clone" && !c.Paused {
-		tree.Ptree[c.Pid] = append(tree.Ptree[c.Pid], c.Ret)
+	if c.Paused || c.Ret <= 0 {
+		return
+	}
+	switch c.CallName {
+	case "clone", "clone3":
+		if cloneFlags(c)&cloneThread != 0 {
+			leader := tree.Leader(c.Pid)
+			tree.leader[c.Ret] = leader
+			tree.Threads[leader] = append(tree.Threads[leader], c.Ret)
+			break
+		}
+		fallthrough
+	case "fork", "vfork":
+		parent := tree.Leader(c.Pid)
+		tree.Ptree[parent] = append(tree.Ptree[parent], c.Ret)
+	}
+}
+
+// Leader returns the thread group leader of the thread (the pid of the process).
+func (tree *TraceTree) Leader(pid int64) int64 {
+	if leader, ok := tree.leader[pid]; ok {
+		return leader
+	}
+	return pid
+}
+
+// ProcessCalls returns calls of all threads of the process in the order they were started.
+func (tree *TraceTree) ProcessCalls(pid int64) []*Syscall {
+	var calls []*Syscall
+	for _, tid := range append([]int64{pid}, tree.Threads[pid]...) {
+		if trace := tree.TraceMap[tid]; trace != nil {
+			calls = append(calls, trace.Calls...)
+		}
+	}
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Seq < calls[j].Seq
+	})
+	return calls
+}
+
+const cloneThread = 0x10000 // CLONE_THREAD
+
+func cloneFlags(call *Syscall) uint64 {
+	var flags IrType
+	switch call.CallName {
+	case "clone":
+		// On x86-64 strace prints child_stack first and then flags.
+		if len(call.Args) > 1 {
+			flags = call.Args[1]
+		}
+	case "clone3":
+		if len(call.Args) > 0 {
+			if args, ok := call.Args[0].(*GroupType); ok && len(args.Elems) > 0 {
+				flags = args.Elems[0]
+			}
+		}
+	}
+	if val, ok := flags.(Constant); ok {
+		return val.Val()
+	}
+	return 0
+}
+
+// finalize orders calls of each process and detects calls that ran concurrently
+// with calls of other threads of the same process.
+func (tree *TraceTree) finalize() {
+	var all []*Syscall
+	for _, trace := range tree.TraceMap {
+		all = append(all, trace.Calls...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].start != all[j].start {
+			return all[i].start < all[j].start
+		}
+		return all[i].Pid < all[j].Pid
+	})
+	for i, call := range all {
+		call.Seq = i
+	}
+	for pid := range tree.TraceMap {
+		if tree.Leader(pid) != pid {
+			continue
+		}
+		calls := tree.ProcessCalls(pid)
+		for i := 0; i+1 < len(calls); i++ {
+			calls[i].Concurrent = calls[i+1].start < calls[i].end
+		}
 	}
 }
 
@@ -51,6 +145,8 @@ func (trace *Trace) add(call *Syscall) *Syscall {
 	lastCall.Args = append(lastCall.Args, call.Args...)
 	lastCall.Paused = false
 	lastCall.Ret = call.Ret
+	lastCall.end = call.end
+	lastCall.duration = call.duration
 	return lastCall
 }
 
@@ -68,6 +164,17 @@ type Syscall struct {
 	Ret      int64
 	Paused   bool
 	Resumed  bool
+	// Position of the call in the trace among calls of all processes and threads.
+	Seq int
+	// The call did not return before the next call of the same process was started
+	// (by another thread).
+	Concurrent bool
+
+	// Order of the call start/end, either a line number or a timestamp.
+	start     float64
+	end       float64
+	timestamp float64
+	duration  float64
 }
 
 // NewSyscall - constructor
@@ -9,13 +9,17 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/google/syzkaller/pkg/log"
 )
 
-func parseSyscall(scanner *bufio.Scanner) (int, *Syscall) {
-	lex := newStraceLexer(scanner.Bytes())
+func parseSyscall(line []byte) (int, *Syscall) {
+	lex := newStraceLexer(line)
 	ret := StraceParse(lex)
 	return ret, lex.result
 }
@@ -27,10 +31,135 @@ func shouldSkip(line string) bool {
 		strings.Contains(line, "<ptrace(SYSCALL):No such process>")
 }
 
+var (
+	// strace -f prints "[pid 123] " instead of "123 " if the trace is not written to a file.
+	pidPrefixRe = regexp.MustCompile(`^\s*\[pid\s+(\d+)\]\s*`)
+	// Timestamps printed with -t, -tt, -ttt or -r.
+	timestampRe = regexp.MustCompile(`^(\s*\d+\s+)?\s*(\d+:\d+:\d+(?:\.\d+)?|\d+\.\d+)\s+`)
+	// Time spent in the call printed with -T.
+	durationRe = regexp.MustCompile(`\s*<(\d+\.\d+)>\s*$`)
+)
+
+// preprocessLine strips the parts of the line that are not handled by the grammar
+// and returns the call timestamp and duration if they are present.
+func preprocessLine(line []byte) ([]byte, float64, float64) {
+	line = pidPrefixRe.ReplaceAll(line, []byte("$1 "))
+	timestamp, duration := math.NaN(), 0.0
+	if match := timestampRe.FindSubmatchIndex(line); match != nil {
+		timestamp = parseTimestamp(string(line[match[4]:match[5]]))
+		var pid []byte
+		if match[2] != -1 {
+			pid = line[match[2]:match[3]]
+		}
+		line = append(append([]byte{}, pid...), line[match[1]:]...)
+	}
+	if match := durationRe.FindSubmatchIndex(line); match != nil {
+		duration, _ = strconv.ParseFloat(string(line[match[2]:match[3]]), 64)
+		line = line[:match[0]]
+	}
+	return line, timestamp, duration
+}
+
+func parseTimestamp(ts string) float64 {
+	var ret float64
+	for _, part := range strings.Split(ts, ":") {
+		val, _ := strconv.ParseFloat(part, 64)
+		ret = ret*60 + val
+	}
+	return ret
+}
+
 // ParseData parses each line of a strace file in a loop.
+// The trace may contain calls of multiple processes and threads (strace -f).
 func ParseData(data []byte) (*TraceTree, error) {
 	tree := NewTraceTree()
-	// Creating the process tree
+	if _, err := tree.parse(data, 0); err != nil {
+		return nil, err
+	}
+	if len(tree.TraceMap) == 0 {
+		return nil, nil
+	}
+	tree.finalize()
+	return tree, nil
+}
+
+// ParsePerThreadData parses traces produced by strace -ff, which writes calls of each process
+// and thread into a separate file. The traces are indexed by pid.
+// If the traces contain timestamps (-tt/-ttt) and call durations (-T), calls of the different threads
+// are ordered by time, otherwise calls of a new thread/process follow the call that created it.
+func ParsePerThreadData(traces map[int64][]byte) (*TraceTree, error) {
+	var pids []int64
+	for pid := range traces {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	tree := NewTraceTree()
+	timed := true
+	for _, pid := range pids {
+		hasTime, err := tree.parse(traces[pid], pid)
+		if err != nil {
+			return nil, fmt.Errorf("trace of pid %v: %w", pid, err)
+		}
+		timed = timed && hasTime
+	}
+	if len(tree.TraceMap) == 0 {
+		return nil, nil
+	}
+	children := make(map[int64]bool)
+	for _, childs := range tree.Ptree {
+		for _, child := range childs {
+			children[child] = true
+		}
+	}
+	tree.RootPid = 0
+	for _, pid := range pids {
+		if tree.TraceMap[pid] != nil && !children[pid] && tree.Leader(pid) == pid {
+			tree.RootPid = pid
+			break
+		}
+	}
+	if timed {
+		for _, trace := range tree.TraceMap {
+			for _, call := range trace.Calls {
+				call.start, call.end = call.timestamp, call.timestamp+call.duration
+			}
+		}
+	} else {
+		pos := 0
+		for _, pid := range pids {
+			if tree.TraceMap[pid] != nil && !children[pid] && tree.Leader(pid) == pid {
+				tree.orderAfterParent(pid, &pos)
+			}
+		}
+	}
+	tree.finalize()
+	return tree, nil
+}
+
+// orderAfterParent assigns sequential positions to calls of the thread, calls of the threads
+// and processes it created are placed right after the creating call.
+func (tree *TraceTree) orderAfterParent(pid int64, pos *int) {
+	trace := tree.TraceMap[pid]
+	if trace == nil {
+		return
+	}
+	for _, call := range trace.Calls {
+		call.start = float64(*pos)
+		call.end = call.start
+		*pos++
+		switch call.CallName {
+		case "clone", "clone3", "fork", "vfork":
+			if call.Ret > 0 && !call.Paused {
+				tree.orderAfterParent(call.Ret, pos)
+			}
+		}
+	}
+}
+
+// parse adds calls from the trace to the tree. If pid is not 0, all calls are attributed to it.
+// Returns true if all calls have timestamps.
+func (tree *TraceTree) parse(data []byte, pid int64) (bool, error) {
+	timed := true
 	scanner := bufio.NewScanner(bytes.NewReader(data))
 	scanner.Buffer(nil, 64<<20)
 	for scanner.Scan() {
@@ -39,17 +168,25 @@ func ParseData(data []byte) (*TraceTree, error) {
 			continue
 		}
 		log.Logf(4, "scanning call: %s", line)
-		ret, call := parseSyscall(scanner)
+		stripped, timestamp, duration := preprocessLine(scanner.Bytes())
+		ret, call := parseSyscall(stripped)
 		if call == nil || ret != 0 {
-			return nil, fmt.Errorf("failed to parse line: %v", line)
+			return false, fmt.Errorf("failed to parse line: %v", line)
+		}
+		if pid != 0 {
+			call.Pid = pid
+		}
+		timed = timed && !math.IsNaN(timestamp)
+		call.timestamp, call.duration = timestamp, duration
+		tree.line++
+		call.start, call.end = float64(tree.line), float64(tree.line)
+		if call.Paused {
+			call.end = math.Inf(1)
 		}
 		tree.add(call)
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return false, err
 	}
-	if len(tree.TraceMap) == 0 {
-		return nil, nil
-	}
-	return tree, nil
+	return timed, nil
 }
@@ -222,3 +222,99 @@ func TestParseGroupType(t *testing.T) {
 		}
 	}
 }
+
+func TestParseThreads(t *testing.T) {
+	data := `100 openat(-100, "\x2f\x64\x65\x76", 0) = 3
+		100 clone(0x7f0000, 0x3d0f00, 0x7f1, 0x7f2, 0x7f3) = 101
+		[pid   101] read(3,  <unfinished ...>
+		100 write(3, "\x61", 1) = 1
+		101 <... read resumed> "", 1) = 0
+		100 close(3) = 0`
+	tree, err := ParseData([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Ptree[100]) != 0 {
+		t.Fatalf("threads must not be recorded as child processes: %v", tree.Ptree)
+	}
+	if len(tree.Threads[100]) != 1 || tree.Threads[100][0] != 101 || tree.Leader(101) != 100 {
+		t.Fatalf("expected thread 101 in process 100, got %v", tree.Threads)
+	}
+	checkProcessCalls(t, tree, 100, []string{"openat", "clone", "read", "write", "close"},
+		[]bool{false, false, true, false, false})
+}
+
+func TestParseTimestamps(t *testing.T) {
+	data := `[pid  7] 12:00:01.000100 open(1) = 3 <0.000010>
+		7 1700000000.000200 fstat(3) = 0 <0.000020>`
+	tree, err := ParseData([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := tree.TraceMap[7].Calls
+	if len(calls) != 2 || calls[0].CallName != "open" || calls[1].CallName != "fstat" {
+		t.Fatalf("failed to parse calls: %+v", calls)
+	}
+	if calls[0].timestamp != 43201.0001 || calls[1].duration != 0.00002 {
+		t.Fatalf("wrong timestamps: %v %v", calls[0].timestamp, calls[1].duration)
+	}
+}
+
+func TestParsePerThreadData(t *testing.T) {
+	type Test struct {
+		traces     map[int64]string
+		calls      []string
+		concurrent []bool
+	}
+	tests := []Test{
+		{
+			traces: map[int64]string{
+				100: `1.000 open(1) = 3 <0.001>
+					1.002 clone(0x7f0000, 0x3d0f00, 0x7f1, 0x7f2, 0x7f3) = 101 <0.001>
+					1.010 close(3) = 0 <0.001>`,
+				101: `1.004 read(3, "", 1) = 0 <0.010>`,
+			},
+			calls:      []string{"open", "clone", "read", "close"},
+			concurrent: []bool{false, false, true, false},
+		},
+		{
+			// Without timestamps calls of the thread follow the clone.
+			traces: map[int64]string{
+				100: `open(1) = 3
+					clone(0x7f0000, 0x3d0f00, 0x7f1, 0x7f2, 0x7f3) = 101
+					close(3) = 0`,
+				101: `read(3, "", 1) = 0
+					write(3, "", 0) = 0`,
+			},
+			calls:      []string{"open", "clone", "read", "write", "close"},
+			concurrent: []bool{false, false, false, false, false},
+		},
+	}
+	for _, test := range tests {
+		traces := make(map[int64][]byte)
+		for pid, trace := range test.traces {
+			traces[pid] = []byte(trace)
+		}
+		tree, err := ParsePerThreadData(traces)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.RootPid != 100 {
+			t.Fatalf("wrong root pid %v", tree.RootPid)
+		}
+		checkProcessCalls(t, tree, 100, test.calls, test.concurrent)
+	}
+}
+
+func checkProcessCalls(t *testing.T, tree *TraceTree, pid int64, names []string, concurrent []bool) {
+	calls := tree.ProcessCalls(pid)
+	if len(calls) != len(names) {
+		t.Fatalf("expected %v calls, got %v", len(names), len(calls))
+	}
+	for i, call := range calls {
+		if call.CallName != names[i] || call.Concurrent != concurrent[i] {
+			t.Errorf("call #%v: expected %v concurrent=%v, got %v concurrent=%v",
+				i, names[i], concurrent[i], call.CallName, call.Concurrent)
+		}
+	}
+}
@@ -29,24 +29,32 @@ func ParseData(data []byte, target *prog.Target) ([]*prog.Prog, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ParseTree(tree, target, nil), nil
+}
+
+// ParseTree generates a program for each process in the trace tree.
+// Calls of all threads of a process end up in the same program, calls that ran
+// concurrently with the following calls are marked as async.
+// If stats is not nil, it's updated with the number of mapped and unmapped calls.
+func ParseTree(tree *parser.TraceTree, target *prog.Target, stats *Stats) []*prog.Prog {
 	if tree == nil {
-		return nil, nil
+		return nil
 	}
 	var progs []*prog.Prog
-	parseTree(tree, tree.RootPid, target, &progs)
-	return progs, nil
+	parseTree(tree, tree.RootPid, target, stats, &progs)
+	return progs
 }
 
 // parseTree groups system calls in the trace by process id.
 // The tree preserves process hierarchy i.e. parent->[]child
-func parseTree(tree *parser.TraceTree, pid int64, target *prog.Target, progs *[]*prog.Prog) {
+func parseTree(tree *parser.TraceTree, pid int64, target *prog.Target, stats *Stats, progs *[]*prog.Prog) {
 	log.Logf(2, "parsing trace pid %v", pid)
-	if p := genProg(tree.TraceMap[pid], target); p != nil {
+	if p := genProg(tree.ProcessCalls(pid), target, stats); p != nil {
 		*progs = append(*progs, p)
 	}
 	for _, childPid := range tree.Ptree[pid] {
 		if tree.TraceMap[childPid] != nil {
-			parseTree(tree, childPid, target, progs)
+			parseTree(tree, childPid, target, stats, progs)
 		}
 	}
 }
@@ -61,8 +69,11 @@ type context struct {
 	currentSyzCall    *prog.Call
 }
 
-// genProg converts a trace to one of our programs.
-func genProg(trace *parser.Trace, target *prog.Target) *prog.Prog {
+// The executor has a limited number of threads, see also prog.AssignRandomAsync.
+const maxAsync = 24
+
+// genProg converts calls of a process to one of our programs.
+func genProg(calls []*parser.Syscall, target *prog.Target, stats *Stats) *prog.Prog {
 	retCache := newRCache()
 	ctx := &context{
 		builder:     prog.MakeProgGen(target),
@@ -70,31 +81,47 @@ func genProg(trace *parser.Trace, target *prog.Target) *prog.Prog {
 		selectors:   newSelectors(target, retCache),
 		returnCache: retCache,
 	}
-	for _, sCall := range trace.Calls {
+	for _, sCall := range calls {
 		if sCall.Paused {
 			// Probably a case where the call was killed by a signal like the following
 			// 2179  wait4(2180,  <unfinished ...>
 			// 2179  <... wait4 resumed> 0x7fff28981bf8, 0, NULL) = ? ERESTARTSYS
 			// 2179  --- SIGUSR1 {si_signo=SIGUSR1, si_code=SI_USER, si_pid=2180, si_uid=0} ---
+			stats.add(sCall.CallName, callPaused)
 			continue
 		}
 		if shouldSkip(sCall) {
 			log.Logf(2, "skipping call: %s", sCall.CallName)
+			stats.add(sCall.CallName, callUnsupported)
 			continue
 		}
 		ctx.currentStraceCall = sCall
 		call := ctx.genCall()
 		if call == nil {
+			stats.add(sCall.CallName, callNoDescription)
 			continue
 		}
+		stats.add(sCall.CallName, callMapped)
+		call.Props.Async = sCall.Concurrent
 		if err := ctx.builder.Append(call); err != nil {
 			log.Fatalf("%v", err)
 		}
+		if sCall.CallName == "close" && sCall.Ret == 0 && len(sCall.Args) > 0 {
+			// The fd may be reused by a following call, possibly in another thread.
+			ctx.returnCache.remove(sCall.Args[0])
+		}
 	}
 	p, err := ctx.builder.Finalize()
 	if err != nil {
 		log.Fatalf("error validating program: %v", err)
 	}
+	async := 0
+	for _, call := range p.Calls {
+		if call.Props.Async {
+			async++
+			call.Props.Async = async <= maxAsync
+		}
+	}
 	return p
 }
 
@@ -139,7 +166,8 @@ func (ctx *context) genResult(syzType prog.Type, straceRet int64) {
 	case *prog.ResourceType:
 		log.Logf(2, "call: %s returned a resource type with val: %s",
 			ctx.currentStraceCall.CallName, straceExpr.String())
-		ctx.returnCache.cache(syzType, straceExpr, ctx.currentSyzCall.Ret)
+		ctx.returnCache.cache(syzType, straceExpr, ctx.currentSyzCall.Ret,
+			ctx.currentSyzCall, ctx.currentStraceCall.Pid)
 	}
 }
 
@@ -424,13 +452,13 @@ func (ctx *context) genResource(syzType *prog.ResourceType, dir prog.Dir, traceT
 	if dir == prog.DirOut {
 		log.Logf(2, "resource returned by call argument: %s", traceType.String())
 		res := prog.MakeResultArg(syzType, dir, nil, syzType.Default())
-		ctx.returnCache.cache(syzType, traceType, res)
+		ctx.returnCache.cache(syzType, traceType, res, ctx.currentSyzCall, ctx.currentStraceCall.Pid)
 		return res
 	}
 	switch a := traceType.(type) {
 	case parser.Constant:
 		val := a.Val()
-		if arg := ctx.returnCache.get(syzType, traceType); arg != nil {
+		if arg := ctx.returnCache.use(syzType, traceType, ctx.currentStraceCall.Pid); arg != nil {
 			res := prog.MakeResultArg(syzType, dir, arg.(*prog.ResultArg), syzType.Default())
 			return res
 		}
@@ -442,7 +470,7 @@ func (ctx *context) genResource(syzType *prog.ResourceType, dir prog.Dir, traceT
 			// last argument is a pointer to a resource. Strace will output a pointer to
 			// a number x as [x].
 			res := prog.MakeResultArg(syzType, dir, nil, syzType.Default())
-			ctx.returnCache.cache(syzType, a.Elems[0], res)
+			ctx.returnCache.cache(syzType, a.Elems[0], res, ctx.currentSyzCall, ctx.currentStraceCall.Pid)
 			return res
 		}
 		log.Fatalf("generating resource type from GroupType with %d elements", len(a.Elems))
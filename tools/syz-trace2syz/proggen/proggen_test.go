@@ -280,7 +280,7 @@ ioctl$RTC_WKALM_SET(r0, 0x4028700f, &(0x7f0000000040)={0x0, 0x0, {0x0, 0x0, 0x0,
 		if err != nil {
 			t.Fatal(err)
 		}
-		p := genProg(tree.TraceMap[tree.RootPid], target)
+		p := genProg(tree.ProcessCalls(tree.RootPid), target, nil)
 		if p == nil {
 			t.Fatalf("failed to parse trace")
 		}
@@ -291,3 +291,53 @@ ioctl$RTC_WKALM_SET(r0, 0x4028700f, &(0x7f0000000040)={0x0, 0x0, {0x0, 0x0, 0x0,
 		}
 	}
 }
+
+func TestParseThreads(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target.ConstMap = make(map[string]uint64)
+	for _, c := range target.Consts {
+		target.ConstMap[c.Name] = c.Value
+	}
+	input := `100 open("file", 66) = 3
+		100 clone(0x7f0000, 0x3d0f00, 0x7f1, 0x7f2, 0x7f3) = 101
+		101 read(3,  <unfinished ...>
+		100 write(3, "somedata", 8) = 8
+		100 pipe([4, 5] <unfinished ...>
+		101 <... read resumed> "", 1) = 0
+		101 write(5, "x", 1) = 1
+		100 <... pipe resumed> ) = 0
+		100 close(3) = 0
+		100 fstat(3, {st_mode=0, st_size=0}) = -1 EBADF (Bad file descriptor)
+		100 unknown_call(1) = 0`
+	tree, err := parser.ParseData([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := NewStats()
+	progs := ParseTree(tree, target, stats)
+	if len(progs) != 1 {
+		t.Fatalf("expected 1 program, got %v", len(progs))
+	}
+	got := string(bytes.TrimSpace(progs[0].Serialize()))
+	want := strings.TrimSpace(`
+r0 = open(&(0x7f0000000000)='file\x00', 0x42, 0x0)
+read(r0, &(0x7f0000000040), 0x0) (async)
+write(r0, &(0x7f0000000080)='somedata', 0x8)
+pipe(&(0x7f00000000c0)={0xffffffffffffffff, <r1=>0xffffffffffffffff})
+write(r1, &(0x7f0000000100)='x', 0x1)
+close(r0)
+fstat(0x3, &(0x7f0000000140))`)
+	if want != got {
+		t.Errorf("want:\n%v\n\ngot:\n%v", want, got)
+	}
+	total := stats.Total()
+	if total.Traced != 9 || total.Mapped != 7 || total.Unsupported != 1 || total.NoDescription != 1 {
+		t.Errorf("wrong stats: %+v", total)
+	}
+	if !strings.Contains(string(stats.Report()), "unknown_call") {
+		t.Errorf("report does not mention unmapped calls:\n%s", stats.Report())
+	}
+}
@@ -9,27 +9,77 @@ import (
 	"github.com/google/syzkaller/tools/syz-trace2syz/parser"
 )
 
-type returnCache map[string]prog.Arg
+// returnCache maps resource values seen in the trace to the arguments that produced them.
+// Threads of a process share fds, so a single cache is used for all threads of the process.
+type returnCache map[returnCacheKey]*returnCacheEntry
+
+type returnCacheKey struct {
+	kind string
+	val  string
+}
+
+type returnCacheEntry struct {
+	arg prog.Arg
+	// The call that produced the resource and the thread that executed it.
+	call *prog.Call
+	pid  int64
+}
 
 func newRCache() returnCache {
-	return make(map[string]prog.Arg)
+	return make(map[returnCacheKey]*returnCacheEntry)
 }
 
-func returnCacheKey(syzType prog.Type, traceType parser.IrType) string {
+func makeReturnCacheKey(syzType prog.Type, traceType parser.IrType) returnCacheKey {
 	a, ok := syzType.(*prog.ResourceType)
 	if !ok {
 		log.Fatalf("caching non resource type")
 	}
-	return a.Desc.Kind[0] + "-" + traceType.String()
+	return returnCacheKey{a.Desc.Kind[0], traceType.String()}
 }
 
-func (r returnCache) cache(syzType prog.Type, traceType parser.IrType, arg prog.Arg) {
-	log.Logf(2, "caching resource: %v", returnCacheKey(syzType, traceType))
-	r[returnCacheKey(syzType, traceType)] = arg
+func (r returnCache) cache(syzType prog.Type, traceType parser.IrType, arg prog.Arg, call *prog.Call, pid int64) {
+	key := makeReturnCacheKey(syzType, traceType)
+	log.Logf(2, "caching resource: %v-%v (pid %v)", key.kind, key.val, pid)
+	r[key] = &returnCacheEntry{arg: arg, call: call, pid: pid}
 }
 
 func (r returnCache) get(syzType prog.Type, traceType parser.IrType) prog.Arg {
-	result := r[returnCacheKey(syzType, traceType)]
-	log.Logf(2, "fetching resource: %s, val: %s", returnCacheKey(syzType, traceType), result)
-	return result
+	entry := r.lookup(syzType, traceType)
+	if entry == nil {
+		return nil
+	}
+	return entry.arg
+}
+
+func (r returnCache) lookup(syzType prog.Type, traceType parser.IrType) *returnCacheEntry {
+	key := makeReturnCacheKey(syzType, traceType)
+	entry := r[key]
+	log.Logf(2, "fetching resource: %v-%v, found: %v", key.kind, key.val, entry != nil)
+	return entry
+}
+
+// use returns the cached argument for the resource consumed by a call executed by thread pid.
+// The producing call can't be async anymore: the consumer needs its result.
+func (r returnCache) use(syzType prog.Type, traceType parser.IrType, pid int64) prog.Arg {
+	entry := r.lookup(syzType, traceType)
+	if entry == nil {
+		return nil
+	}
+	if entry.call != nil && entry.call.Props.Async {
+		log.Logf(2, "resource %v produced by pid %v is used by pid %v, making %v synchronous",
+			traceType, entry.pid, pid, entry.call.Meta.Name)
+		entry.call.Props.Async = false
+	}
+	return entry.arg
+}
+
+// remove drops all resources with the value, e.g. after the fd was closed.
+func (r returnCache) remove(traceType parser.IrType) {
+	val := traceType.String()
+	for key := range r {
+		if key.val == val {
+			log.Logf(2, "dropping resource: %v-%v", key.kind, key.val)
+			delete(r, key)
+		}
+	}
 }
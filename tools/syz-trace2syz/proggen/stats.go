@@ -0,0 +1,115 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package proggen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Stats counts how many traced calls were converted to program calls.
+type Stats struct {
+	Calls map[string]*CallStats
+}
+
+// CallStats holds the counters for a single system call name.
+type CallStats struct {
+	Traced        int // total number of calls in the traces
+	Mapped        int // converted to a program call
+	Unsupported   int // deliberately skipped, see unsupportedCalls
+	NoDescription int // no matching syscall description
+	Paused        int // the call never returned
+}
+
+// Unmapped returns the number of calls that are missing in the programs.
+func (cs *CallStats) Unmapped() int {
+	return cs.Traced - cs.Mapped
+}
+
+type callOutcome int
+
+const (
+	callMapped callOutcome = iota
+	callUnsupported
+	callNoDescription
+	callPaused
+)
+
+// NewStats returns empty statistics to pass to ParseTree.
+func NewStats() *Stats {
+	return &Stats{Calls: make(map[string]*CallStats)}
+}
+
+func (stats *Stats) add(name string, outcome callOutcome) {
+	if stats == nil {
+		return
+	}
+	cs := stats.Calls[name]
+	if cs == nil {
+		cs = new(CallStats)
+		stats.Calls[name] = cs
+	}
+	cs.Traced++
+	switch outcome {
+	case callMapped:
+		cs.Mapped++
+	case callUnsupported:
+		cs.Unsupported++
+	case callNoDescription:
+		cs.NoDescription++
+	case callPaused:
+		cs.Paused++
+	}
+}
+
+// Total returns the sum of counters over all calls.
+func (stats *Stats) Total() CallStats {
+	var total CallStats
+	for _, cs := range stats.Calls {
+		total.Traced += cs.Traced
+		total.Mapped += cs.Mapped
+		total.Unsupported += cs.Unsupported
+		total.NoDescription += cs.NoDescription
+		total.Paused += cs.Paused
+	}
+	return total
+}
+
+// Report returns a text table of calls that could not be mapped,
+// the most frequent ones go first.
+func (stats *Stats) Report() []byte {
+	var names []string
+	for name, cs := range stats.Calls {
+		if cs.Unmapped() != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ui, uj := stats.Calls[names[i]].Unmapped(), stats.Calls[names[j]].Unmapped()
+		if ui != uj {
+			return ui > uj
+		}
+		return names[i] < names[j]
+	})
+	buf := new(bytes.Buffer)
+	total := stats.Total()
+	fmt.Fprintf(buf, "mapped %v/%v traced calls (%.1f%%)\n\n",
+		total.Mapped, total.Traced, percent(total.Mapped, total.Traced))
+	fmt.Fprintf(buf, "%-32v %8v %8v %12v %15v %8v\n",
+		"call", "traced", "mapped", "unsupported", "no description", "paused")
+	for _, name := range names {
+		cs := stats.Calls[name]
+		fmt.Fprintf(buf, "%-32v %8v %8v %12v %15v %8v\n",
+			name, cs.Traced, cs.Mapped, cs.Unsupported, cs.NoDescription, cs.Paused)
+	}
+	return buf.Bytes()
+}
+
+func percent(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) * 100 / float64(b)
+}
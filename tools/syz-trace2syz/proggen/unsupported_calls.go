@@ -13,12 +13,16 @@ var (
 		"execve": true,
 		// Unsafe to set the addr argument to some random argument. Needs more care
 		"arch_prctl": true,
-		// Don't produce multithreaded programs.
+		// Threads of a process are merged into one program and their concurrency
+		// is expressed with async calls, waits and synchronization are not needed.
 		"wait4": true,
 		"wait":  true,
 		"futex": true,
 		// Cannot obtain coverage from the forks.
-		"clone": true,
+		"clone":  true,
+		"clone3": true,
+		"fork":   true,
+		"vfork":  true,
 		// Can support these calls but need to identify the ones in the trace that are worth keeping
 		"mmap":     true,
 		"msync":    true,
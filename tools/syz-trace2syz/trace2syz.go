@@ -10,13 +10,25 @@
 //	strace -o trace -a 1 -s 65500 -v -xx -f -Xraw ./a.out
 //	syz-trace2syz -file trace
 //
+// Traces of multi-threaded programs are also supported, calls of all threads of a process
+// are merged into one program and calls that ran concurrently become async calls.
+// Per-thread traces produced by strace -ff are converted with the -ff flag:
+//
+//	strace -o trace -a 1 -s 65500 -v -xx -ff -ttt -T -Xraw ./a.out
+//	syz-trace2syz -ff -file trace
+//
+// The -report flag saves statistics about the traced calls that could not be converted.
+//
 // Intended for seed selection or debugging
 package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 
 	"github.com/google/syzkaller/pkg/db"
@@ -25,6 +37,7 @@ import (
 	"github.com/google/syzkaller/prog"
 	_ "github.com/google/syzkaller/sys"
 	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/tools/syz-trace2syz/parser"
 	"github.com/google/syzkaller/tools/syz-trace2syz/proggen"
 )
 
@@ -32,6 +45,9 @@ var (
 	flagFile        = flag.String("file", "", "file to parse")
 	flagDir         = flag.String("dir", "", "directory to parse")
 	flagDeserialize = flag.String("deserialize", "", "(Optional) directory to store deserialized programs")
+	flagPerThread   = flag.Bool("ff", false, "traces were produced by strace -ff, files named prefix.pid form one trace"+
+		" (-file specifies the prefix)")
+	flagReport = flag.String("report", "", "(Optional) file to store the report about calls that could not be converted")
 )
 
 const (
@@ -70,17 +86,38 @@ func parseTraces(target *prog.Target) []*prog.Prog {
 	} else {
 		log.Fatalf("-file or -dir must be specified")
 	}
+	traces := make(map[string]map[int64]string)
+	if *flagPerThread {
+		if *flagFile != "" {
+			names = getTraceFiles(filepath.Dir(*flagFile))
+		}
+		traces = groupPerThreadFiles(names)
+		if *flagFile != "" {
+			prefix := filepath.Clean(*flagFile)
+			if traces[prefix] == nil {
+				log.Fatalf("no %v.<pid> files found", prefix)
+			}
+			traces = map[string]map[int64]string{prefix: traces[prefix]}
+		}
+		names = nil
+		for name := range traces {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
 
 	deserializeDir := *flagDeserialize
+	stats := proggen.NewStats()
 
 	totalFiles := len(names)
 	log.Logf(0, "parsing %v traces", totalFiles)
 	for i, file := range names {
 		log.Logf(1, "parsing file %v/%v: %v", i+1, totalFiles, filepath.Base(names[i]))
-		progs, err := proggen.ParseFile(file, target)
+		tree, err := parseTrace(file, traces[file])
 		if err != nil {
 			log.Fatalf("%v", err)
 		}
+		progs := proggen.ParseTree(tree, target, stats)
 		ret = append(ret, progs...)
 		if deserializeDir != "" {
 			for i, p := range progs {
@@ -91,9 +128,60 @@ func parseTraces(target *prog.Target) []*prog.Prog {
 			}
 		}
 	}
+	total := stats.Total()
+	log.Logf(0, "converted %v/%v traced calls (%v unsupported, %v without descriptions, %v unfinished)",
+		total.Mapped, total.Traced, total.Unsupported, total.NoDescription, total.Paused)
+	if *flagReport != "" {
+		if err := osutil.WriteFile(*flagReport, stats.Report()); err != nil {
+			log.Fatalf("failed to write report: %v", err)
+		}
+	}
 	return ret
 }
 
+// parseTrace parses a single trace file or, if perThread is not empty, a set of strace -ff files.
+func parseTrace(file string, perThread map[int64]string) (*parser.TraceTree, error) {
+	if len(perThread) == 0 {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		return parser.ParseData(data)
+	}
+	traces := make(map[int64][]byte)
+	for pid, name := range perThread {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		traces[pid] = data
+	}
+	return parser.ParsePerThreadData(traces)
+}
+
+var perThreadFileRe = regexp.MustCompile(`^(.+)\.([0-9]+)$`)
+
+// groupPerThreadFiles groups files named prefix.pid by the prefix.
+func groupPerThreadFiles(names []string) map[string]map[int64]string {
+	groups := make(map[string]map[int64]string)
+	for _, name := range names {
+		match := perThreadFileRe.FindStringSubmatch(name)
+		if match == nil {
+			log.Logf(1, "skipping %v: not a per-thread trace", name)
+			continue
+		}
+		pid, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		if groups[match[1]] == nil {
+			groups[match[1]] = make(map[int64]string)
+		}
+		groups[match[1]][pid] = name
+	}
+	return groups
+}
+
 func getTraceFiles(dir string) []string {
 	infos, err := os.ReadDir(dir)
 	if err != nil {